@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"log"
 	"plandex/api"
+	"plandex/auth"
 	"plandex/lib"
 
 	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/muesli/reflow/wrap"
 	"github.com/plandex/plandex/shared"
 )
@@ -29,6 +31,31 @@ func (m *changesUIModel) rejectFile() (*shared.CurrentPlanState, *shared.ApiErro
 	return planState, nil
 }
 
+// leaveFeedback approves or comments on the selected change, or the whole file if the file view is selected
+func (m *changesUIModel) leaveFeedback(approve bool, comment string) tea.Cmd {
+	req := shared.ReviewFeedbackRequest{
+		Path:    m.selectionInfo.currentPath,
+		Approve: approve,
+		Comment: comment,
+	}
+	if m.selectionInfo.currentRep != nil {
+		req.ReplacementId = m.selectionInfo.currentRep.Id
+	}
+
+	return func() tea.Msg {
+		reviewState, err := api.Client.LeaveReviewFeedback(lib.CurrentPlanId, lib.CurrentBranch, req)
+		if err != nil {
+			log.Printf("error leaving review feedback: %v", err)
+		}
+		return finishedFeedback{reviewState: reviewState, err: err}
+	}
+}
+
+// isReviewer is true if the current user has been asked to review the pending changes
+func (m changesUIModel) isReviewer() bool {
+	return m.currentPlan.ReviewState != nil && m.currentPlan.ReviewState.RequestForReviewer(auth.Current.UserId) != nil
+}
+
 func (m *changesUIModel) copyCurrentChange() error {
 	selectionInfo := m.selectionInfo
 	if selectionInfo.currentRep == nil {
@@ -6,6 +6,7 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/fatih/color"
+	"github.com/plandex/plandex/shared"
 )
 
 func (m changesUIModel) renderMainView() string {
@@ -104,9 +105,80 @@ func (m changesUIModel) renderMainViewHeader() string {
 		header = " 👉 " + m.selectionInfo.currentRep.StreamedChange.Summary
 	}
 
+	header += m.renderReviewFindings()
+	header += m.renderReviewFeedback()
+
 	return style.Render(header)
 }
 
+const maxReviewFindingsShown = 3
+
+func (m changesUIModel) renderReviewFindings() string {
+	if m.currentPlan.Review == nil {
+		return ""
+	}
+
+	findings := m.currentPlan.Review.FindingsForPath(m.selectionInfo.currentPath)
+	if len(findings) == 0 {
+		return ""
+	}
+
+	var res string
+	for i, finding := range findings {
+		if i == maxReviewFindingsShown {
+			res += color.New(color.FgHiWhite).Sprintf("\n    +%d more • plandex review --show", len(findings)-maxReviewFindingsShown)
+			break
+		}
+
+		line := ""
+		if finding.Line > 0 {
+			line = fmt.Sprintf("line %d: ", finding.Line)
+		}
+		res += fmt.Sprintf("\n %s %s%s", finding.Severity.Icon(), line, finding.Message)
+	}
+
+	return res
+}
+
+const maxReviewFeedbackShown = 3
+
+// renderReviewFeedback shows approvals and comments on the selected change, or on the whole file if the file view is selected
+func (m changesUIModel) renderReviewFeedback() string {
+	var res string
+	if m.feedbackErr != nil {
+		res += color.New(term.ColorHiRed).Sprintf("\n 🚨 %s", m.feedbackErr.Msg)
+	}
+
+	if m.currentPlan.ReviewState == nil {
+		return res
+	}
+
+	var feedback []*shared.ReviewFeedback
+	if m.selectionInfo.currentRep == nil {
+		feedback = m.currentPlan.ReviewState.FeedbackForPath(m.selectionInfo.currentPath)
+	} else {
+		feedback = m.currentPlan.ReviewState.FeedbackForReplacement(m.selectionInfo.currentRep.Id)
+	}
+
+	for i, f := range feedback {
+		if i == maxReviewFeedbackShown {
+			res += color.New(color.FgHiWhite).Sprintf("\n    +%d more • plandex reviews", len(feedback)-maxReviewFeedbackShown)
+			break
+		}
+
+		if f.Approved {
+			res += fmt.Sprintf("\n ✅ %s approved", f.UserName)
+			if f.Comment != "" {
+				res += ": " + f.Comment
+			}
+		} else {
+			res += fmt.Sprintf("\n 💬 %s: %s", f.UserName, f.Comment)
+		}
+	}
+
+	return res
+}
+
 func (m changesUIModel) renderMainViewFooter() string {
 	if m.selectedFullFile() {
 		return ""
@@ -4,6 +4,7 @@ import (
 	"github.com/charmbracelet/bubbles/help"
 	bubbleKey "github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -31,6 +32,9 @@ type changesUIModel struct {
 	isConfirmingRejectFile   bool
 	rejectFileErr            *shared.ApiError
 	justRejectedFile         bool
+	isCommenting             bool
+	commentInput             textinput.Model
+	feedbackErr              *shared.ApiError
 	spinner                  spinner.Model
 }
 
@@ -47,6 +51,8 @@ type keymap = struct {
 	end,
 	switchView,
 	reject,
+	approve,
+	comment,
 	copy,
 	applyAll,
 	yes,
@@ -63,12 +69,17 @@ func initialModel(currentPlan *shared.CurrentPlanState) *changesUIModel {
 	s.Spinner = spinner.Points
 	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
 
+	commentInput := textinput.New()
+	commentInput.Placeholder = "Leave a comment"
+	commentInput.CharLimit = 2000
+
 	initialState := changesUIModel{
 		currentPlan:              currentPlan,
 		selectedFileIndex:        0,
 		selectedReplacementIndex: 0,
 		help:                     help.New(),
 		spinner:                  s,
+		commentInput:             commentInput,
 		keymap: keymap{
 			up: bubbleKey.NewBinding(
 				bubbleKey.WithKeys("up"),
@@ -127,6 +138,16 @@ func initialModel(currentPlan *shared.CurrentPlanState) *changesUIModel {
 				bubbleKey.WithHelp("r", "reject file"),
 			),
 
+			approve: bubbleKey.NewBinding(
+				bubbleKey.WithKeys("v"),
+				bubbleKey.WithHelp("v", "approve"),
+			),
+
+			comment: bubbleKey.NewBinding(
+				bubbleKey.WithKeys("m"),
+				bubbleKey.WithHelp("m", "comment"),
+			),
+
 			copy: bubbleKey.NewBinding(
 				bubbleKey.WithKeys("c"),
 				bubbleKey.WithHelp("c", "copy change"),
@@ -1,6 +1,7 @@
 package changes_tui
 
 import (
+	"plandex/auth"
 	"plandex/term"
 	"strings"
 
@@ -27,7 +28,17 @@ func (m changesUIModel) renderPathTabs() string {
 			path = path[:20] + "⋯" + path[len(path)-20:]
 		}
 
-		tab := " 📄 " + path + "  "
+		icon := "📄"
+		if m.isReviewer() && m.currentPlan.ReviewState.IsPathApproved(auth.Current.UserId, paths[i], m.currentPlan.PlanResult) {
+			icon = "✅"
+		}
+		if m.currentPlan.Review != nil {
+			if severity := m.currentPlan.Review.MaxSeverityForPath(paths[i]); severity != "" {
+				icon = severity.Icon()
+			}
+		}
+
+		tab := " " + icon + " " + path + "  "
 
 		pathColor := term.ColorHiGreen
 		bgColor := color.BgGreen
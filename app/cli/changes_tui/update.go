@@ -2,6 +2,7 @@ package changes_tui
 
 import (
 	"plandex/types"
+	"strings"
 	"time"
 
 	bubbleKey "github.com/charmbracelet/bubbles/key"
@@ -15,6 +16,10 @@ type finishedRejectFile struct {
 	planState *shared.CurrentPlanState
 	err       *shared.ApiError
 }
+type finishedFeedback struct {
+	reviewState *shared.PlanReviewState
+	err         *shared.ApiError
+}
 
 func (m changesUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	// log.Println("msg:", msg)
@@ -65,7 +70,39 @@ func (m changesUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.setSelectionInfo()
 		m.updateMainView(true)
 
+	case finishedFeedback:
+		if msg.err != nil {
+			m.feedbackErr = msg.err
+		} else {
+			m.feedbackErr = nil
+			m.currentPlan.ReviewState = msg.reviewState
+		}
+		m.updateViewportSizes()
+
 	case tea.KeyMsg:
+		if m.isCommenting {
+			switch msg.Type {
+			case tea.KeyEnter:
+				comment := strings.TrimSpace(m.commentInput.Value())
+				m.isCommenting = false
+				m.commentInput.Blur()
+				m.commentInput.Reset()
+				if comment == "" {
+					return m, nil
+				}
+				return m, m.leaveFeedback(false, comment)
+			case tea.KeyEsc, tea.KeyCtrlC:
+				m.isCommenting = false
+				m.commentInput.Blur()
+				m.commentInput.Reset()
+				return m, nil
+			}
+
+			input, cmd := m.commentInput.Update(msg)
+			m.commentInput = input
+			return m, cmd
+		}
+
 		if m.isConfirmingRejectFile {
 			if !bubbleKey.Matches(msg, m.keymap.yes) && !bubbleKey.Matches(msg, m.keymap.no) &&
 				!bubbleKey.Matches(msg, m.keymap.quit) {
@@ -117,6 +154,15 @@ func (m changesUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case bubbleKey.Matches(msg, m.keymap.reject):
 			m.isConfirmingRejectFile = true
 
+		case bubbleKey.Matches(msg, m.keymap.approve):
+			if m.isReviewer() {
+				return m, m.leaveFeedback(true, "")
+			}
+
+		case bubbleKey.Matches(msg, m.keymap.comment):
+			m.isCommenting = true
+			return m, m.commentInput.Focus()
+
 		case bubbleKey.Matches(msg, m.keymap.yes):
 			m.isRejectingFile = true
 			m.isConfirmingRejectFile = false
@@ -21,6 +21,10 @@ func (m changesUIModel) View() string {
 		return m.renderConfirmRejectFile()
 	}
 
+	if m.isCommenting {
+		return m.renderCommentInput()
+	}
+
 	if m.isRejectingFile {
 		return m.renderIsRejectingFile()
 	}
@@ -143,7 +147,11 @@ func (m changesUIModel) renderHelp() string {
 		help += "(↑/↓) select change • "
 	}
 
-	help += "(ctrl+a) apply all changes • (q)uit"
+	if m.isReviewer() {
+		help += "(v) approve • "
+	}
+
+	help += "(m) comment • (ctrl+a) apply all changes • (q)uit"
 	style := lipgloss.NewStyle().Width(m.width).Inherit(topBorderStyle).Foreground(lipgloss.Color(helpTextColor))
 	return style.Render(help)
 }
@@ -224,6 +232,21 @@ func (m changesUIModel) renderConfirmRejectFile() string {
 	return style.Render(prompt)
 }
 
+func (m changesUIModel) renderCommentInput() string {
+	style := lipgloss.NewStyle().Padding(1).BorderStyle(lipgloss.NormalBorder()).BorderForeground(lipgloss.Color(borderColor)).Width(m.width - 2).Height(m.height - 2)
+
+	target := "💬 Comment on " + color.New(color.Bold, term.ColorHiMagenta).Sprint(m.selectionInfo.currentPath)
+	if m.selectionInfo.currentRep != nil {
+		target = "💬 Comment on change: " + color.New(color.Bold, term.ColorHiMagenta).Sprint(m.selectionInfo.currentRep.StreamedChange.Summary)
+	}
+
+	prompt := color.New(color.Bold).Sprint(target) + "\n\n" +
+		m.commentInput.View() + "\n\n" +
+		color.New(term.ColorHiCyan, color.Bold).Sprintf("(enter) submit | (esc) cancel")
+
+	return style.Render(prompt)
+}
+
 func (m changesUIModel) renderIsRejectingFile() string {
 	style := lipgloss.NewStyle().Padding(1).BorderStyle(lipgloss.NormalBorder()).BorderForeground(lipgloss.Color(borderColor)).Width(m.width - 2).Height(m.height - 2)
 
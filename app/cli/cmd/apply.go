@@ -9,9 +9,11 @@ import (
 )
 
 var autoConfirm bool
+var blockOnReview bool
 
 func init() {
 	applyCmd.Flags().BoolVarP(&autoConfirm, "yes", "y", false, "Automatically confirm unless plan is outdated")
+	applyCmd.Flags().BoolVar(&blockOnReview, "block-on-review", false, "Refuse to apply if the latest review found high-severity issues")
 
 	RootCmd.AddCommand(applyCmd)
 }
@@ -31,5 +33,5 @@ func apply(cmd *cobra.Command, args []string) {
 		term.OutputNoCurrentPlanErrorAndExit()
 	}
 
-	lib.MustApplyPlan(lib.CurrentPlanId, lib.CurrentBranch, autoConfirm)
+	lib.MustApplyPlan(lib.CurrentPlanId, lib.CurrentBranch, autoConfirm, blockOnReview)
 }
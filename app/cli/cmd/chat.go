@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"plandex/auth"
+	"plandex/lib"
+	"plandex/plan_exec"
+	"plandex/term"
+
+	"github.com/plandex/plandex/shared"
+	"github.com/spf13/cobra"
+)
+
+var chatPromptFile string
+
+var chatCmd = &cobra.Command{
+	Use:   "chat [prompt]",
+	Short: "Ask a question or chat without making changes",
+	Long: `Ask a question about the plan or its context, or discuss an approach, without making changes.
+
+Replies never include file blocks and never start a build. The exchange is still stored in the plan's conversation, so later prompts sent with 'plandex tell' can refer to it.`,
+	Args: cobra.RangeArgs(0, 1),
+	Run:  doChat,
+}
+
+func init() {
+	RootCmd.AddCommand(chatCmd)
+
+	chatCmd.Flags().StringVarP(&chatPromptFile, "file", "f", "", "File containing prompt")
+}
+
+func doChat(cmd *cobra.Command, args []string) {
+	auth.MustResolveAuthWithOrg()
+	lib.MustResolveProject()
+
+	if lib.CurrentPlanId == "" {
+		term.OutputNoCurrentPlanErrorAndExit()
+	}
+
+	apiKeys := lib.MustVerifyApiKeys()
+
+	var prompt string
+
+	if len(args) > 0 {
+		prompt = args[0]
+	} else if chatPromptFile != "" {
+		bytes, err := os.ReadFile(chatPromptFile)
+		if err != nil {
+			term.OutputErrorAndExit("Error reading prompt file: %v", err)
+		}
+		prompt = string(bytes)
+	} else {
+		prompt = getEditorPrompt()
+	}
+
+	if prompt == "" {
+		fmt.Println("🤷‍♂️ No prompt to send")
+		return
+	}
+
+	plan_exec.TellPlan(plan_exec.ExecParams{
+		CurrentPlanId: lib.CurrentPlanId,
+		CurrentBranch: lib.CurrentBranch,
+		ApiKeys:       apiKeys,
+		CheckOutdatedContext: func(maybeContexts []*shared.Context) (bool, bool) {
+			return lib.MustCheckOutdatedContext(false, maybeContexts)
+		},
+	}, prompt, false, true, true, false, true)
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"plandex/api"
+	"plandex/auth"
+	"plandex/lib"
+	"plandex/term"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/plandex/plandex/shared"
+	"github.com/spf13/cobra"
+)
+
+var compareCmd = &cobra.Command{
+	Use:   "compare <branch-a> [branch-b]",
+	Short: "Compare the pending changes, context, and conversation of two branches",
+	Long: `Compare the pending changes, context, and conversation of two branches.
+
+The diff shows how the files would differ between applying the pending changes on branch-a and applying the pending changes on branch-b. If only one branch is given, it's compared with the current branch.`,
+	Args: cobra.RangeArgs(1, 2),
+	Run:  compare,
+}
+
+func init() {
+	RootCmd.AddCommand(compareCmd)
+}
+
+func compare(cmd *cobra.Command, args []string) {
+	auth.MustResolveAuthWithOrg()
+	lib.MustResolveProject()
+
+	if lib.CurrentPlanId == "" {
+		term.OutputNoCurrentPlanErrorAndExit()
+	}
+
+	branchA := lib.CurrentBranch
+	branchB := strings.TrimSpace(args[0])
+	if len(args) > 1 {
+		branchA = branchB
+		branchB = strings.TrimSpace(args[1])
+	}
+
+	if branchA == branchB {
+		term.OutputErrorAndExit("Can't compare a branch with itself")
+	}
+
+	term.StartSpinner("")
+	res, apiErr := api.Client.CompareBranches(lib.CurrentPlanId, branchA, branchB)
+	term.StopSpinner()
+
+	if apiErr != nil {
+		term.OutputErrorAndExit("Error comparing branches: %v", apiErr.Msg)
+	}
+
+	var builder strings.Builder
+
+	table := tablewriter.NewWriter(&builder)
+	table.SetAutoWrapText(false)
+	table.SetHeader([]string{"", res.A.Name, res.B.Name})
+	table.AppendBulk([][]string{
+		{"Pending files", strconv.Itoa(len(res.A.PendingPaths)), strconv.Itoa(len(res.B.PendingPaths))},
+		{"Context", strconv.Itoa(res.A.NumContexts), strconv.Itoa(res.B.NumContexts)},
+		{"Context tokens", strconv.Itoa(res.A.ContextTokens) + " 🪙", strconv.Itoa(res.B.ContextTokens) + " 🪙"},
+		{"Messages", strconv.Itoa(res.A.NumMessages), strconv.Itoa(res.B.NumMessages)},
+		{"Convo tokens", strconv.Itoa(res.A.ConvoTokens) + " 🪙", strconv.Itoa(res.B.ConvoTokens) + " 🪙"},
+	})
+	table.Render()
+
+	writeCompareList(&builder, fmt.Sprintf("Pending only on %s", res.A.Name), pathsOnlyIn(res.A, res.B))
+	writeCompareList(&builder, fmt.Sprintf("Pending only on %s", res.B.Name), pathsOnlyIn(res.B, res.A))
+	writeCompareList(&builder, fmt.Sprintf("Context only on %s", res.A.Name), res.ContextOnlyA)
+	writeCompareList(&builder, fmt.Sprintf("Context only on %s", res.B.Name), res.ContextOnlyB)
+	writeCompareList(&builder, "Context that differs", res.ContextChanged)
+
+	builder.WriteString("\n")
+	if strings.TrimSpace(res.Diffs) == "" {
+		builder.WriteString("🤷‍♂️ No differences in pending files\n")
+	} else {
+		builder.WriteString(color.New(color.Bold).Sprintf("Pending files on %s ➡️  %s\n\n", res.A.Name, res.B.Name))
+		builder.WriteString(res.Diffs)
+	}
+
+	term.PageOutput(builder.String())
+}
+
+func writeCompareList(builder *strings.Builder, label string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+
+	builder.WriteString("\n" + color.New(color.Bold).Sprint(label) + "\n")
+	for _, item := range items {
+		builder.WriteString(fmt.Sprintf("  • %s\n", item))
+	}
+}
+
+func pathsOnlyIn(summary, other shared.BranchComparisonSummary) []string {
+	otherPaths := map[string]bool{}
+	for _, path := range other.PendingPaths {
+		otherPaths[path] = true
+	}
+
+	var paths []string
+	for _, path := range summary.PendingPaths {
+		if !otherPaths[path] {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"plandex/api"
+	"plandex/auth"
+	"plandex/lib"
+	"plandex/term"
+
+	"github.com/plandex/plandex/shared"
+	"github.com/spf13/cobra"
+)
+
+var contextGroupsCmd = &cobra.Command{
+	Use:   "context",
+	Short: "Manage context groups",
+	Long: `Disable or enable a group of context loaded with 'plandex load --group'.
+
+Disabled context stays loaded and is kept up to date, but is left out of the prompt and the token total.
+
+	plandex load app/api -r --group api
+	plandex context disable api
+	plandex context enable api
+	`,
+}
+
+var disableContextGroupCmd = &cobra.Command{
+	Use:   "disable [group]",
+	Short: "Leave a context group out of the prompt without removing it",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		updateContextGroup(args[0], true)
+	},
+}
+
+var enableContextGroupCmd = &cobra.Command{
+	Use:   "enable [group]",
+	Short: "Include a disabled context group in the prompt again",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		updateContextGroup(args[0], false)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(contextGroupsCmd)
+	contextGroupsCmd.AddCommand(disableContextGroupCmd)
+	contextGroupsCmd.AddCommand(enableContextGroupCmd)
+}
+
+func updateContextGroup(group string, disabled bool) {
+	auth.MustResolveAuthWithOrg()
+	lib.MustResolveProject()
+
+	if lib.CurrentPlanId == "" {
+		term.OutputNoCurrentPlanErrorAndExit()
+	}
+
+	term.StartSpinner("")
+	res, apiErr := api.Client.UpdateContextGroup(lib.CurrentPlanId, lib.CurrentBranch, shared.UpdateContextGroupRequest{
+		Group:    group,
+		Disabled: disabled,
+	})
+	term.StopSpinner()
+
+	if apiErr != nil {
+		term.OutputErrorAndExit("Error updating context group: %v", apiErr.Msg)
+	}
+
+	if res.MaxTokensExceeded {
+		overage := res.TotalTokens - res.MaxTokens
+		term.OutputErrorAndExit("Enabling '%s' would add %d 🪙 and exceed token limit (%d) by %d 🪙\n", group, res.TokensChanged, res.MaxTokens, overage)
+	}
+
+	if res.NumContexts == 0 {
+		state := "enabled"
+		if disabled {
+			state = "disabled"
+		}
+		fmt.Printf("🤷‍♂️ Context group '%s' is already %s\n", group, state)
+		return
+	}
+
+	fmt.Println("✅ " + res.Msg)
+	fmt.Println()
+	term.PrintCmds("", "ls", "tell")
+}
@@ -42,5 +42,5 @@ func doContinue(cmd *cobra.Command, args []string) {
 		CheckOutdatedContext: func(maybeContexts []*shared.Context) (bool, bool) {
 			return lib.MustCheckOutdatedContext(false, maybeContexts)
 		},
-	}, "", tellBg, tellStop, tellNoBuild, true)
+	}, "", tellBg, tellStop, tellNoBuild, true, false)
 }
@@ -49,31 +49,10 @@ func convo(cmd *cobra.Command, args []string) {
 	}
 
 	var msgRange string
-	var msgRangeStart, msgRangeEnd int
 	if len(args) > 0 {
 		msgRange = args[0]
 	}
-	if msgRange != "" {
-		// validate either a number or a range of numbers
-		if strings.Contains(msgRange, "-") {
-			_, err := fmt.Sscanf(msgRange, "%d-%d", &msgRangeStart, &msgRangeEnd)
-			if err != nil {
-				_, err := fmt.Sscanf(msgRange, "%d-", &msgRangeStart)
-
-				if err != nil {
-					term.OutputErrorAndExit("Invalid message range: %s", msgRange)
-				}
-
-				msgRangeEnd = len(conversation)
-			}
-		} else {
-			_, err := fmt.Sscanf(msgRange, "%d", &msgRangeStart)
-			if err != nil {
-				term.OutputErrorAndExit("Invalid message number: %s", msgRange)
-			}
-			msgRangeEnd = msgRangeStart
-		}
-	}
+	msgRangeStart, msgRangeEnd := mustParseMsgRange(msgRange, len(conversation))
 
 	var convo string
 	var totalTokens int
@@ -113,6 +92,14 @@ func convo(cmd *cobra.Command, args []string) {
 		header := fmt.Sprintf("#### %d | %s | %s | %d 🪙 ", i+1,
 			author, formattedTs, msg.Tokens)
 
+		if msg.IsChat {
+			header += "| chat "
+		}
+
+		if msg.PromptVersion != "" {
+			header += fmt.Sprintf("| prompt %s ", msg.PromptVersion)
+		}
+
 		if plainTextOutput {
 			convo += header + "\n" + msg.Message + "\n\n"
 		} else {
@@ -155,3 +142,33 @@ func convo(cmd *cobra.Command, args []string) {
 		term.PrintCmds("", "convo 1", "convo 2-5", "convo --plain", "log")
 	}
 }
+
+// mustParseMsgRange parses a message number or range of messages (e.g. '1' or '1-5' or '5-'). Both bounds are 0 if msgRange is empty.
+func mustParseMsgRange(msgRange string, numMessages int) (int, int) {
+	var msgRangeStart, msgRangeEnd int
+	if msgRange == "" {
+		return 0, 0
+	}
+
+	// validate either a number or a range of numbers
+	if strings.Contains(msgRange, "-") {
+		_, err := fmt.Sscanf(msgRange, "%d-%d", &msgRangeStart, &msgRangeEnd)
+		if err != nil {
+			_, err := fmt.Sscanf(msgRange, "%d-", &msgRangeStart)
+
+			if err != nil {
+				term.OutputErrorAndExit("Invalid message range: %s", msgRange)
+			}
+
+			msgRangeEnd = numMessages
+		}
+	} else {
+		_, err := fmt.Sscanf(msgRange, "%d", &msgRangeStart)
+		if err != nil {
+			term.OutputErrorAndExit("Invalid message number: %s", msgRange)
+		}
+		msgRangeEnd = msgRangeStart
+	}
+
+	return msgRangeStart, msgRangeEnd
+}
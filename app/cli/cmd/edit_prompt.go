@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"fmt"
+	"plandex/api"
+	"plandex/auth"
+	"plandex/lib"
+	"plandex/plan_exec"
+	"plandex/term"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/plandex/plandex/shared"
+	"github.com/spf13/cobra"
+)
+
+var editConvoPromptBranch string
+
+var editConvoPromptCmd = &cobra.Command{
+	Use:   "edit-prompt <msg-num>",
+	Short: "Edit an earlier prompt and send it on a new branch",
+	Long: `Edit an earlier prompt and send it on a new branch.
+
+The prompt opens in your editor. A new branch is then created from the current branch as it was just before the prompt was sent, and the edited prompt is sent on it. The current branch isn't changed.`,
+	Args: cobra.ExactArgs(1),
+	Run:  editConvoPrompt,
+}
+
+func init() {
+	RootCmd.AddCommand(editConvoPromptCmd)
+
+	editConvoPromptCmd.Flags().StringVar(&editConvoPromptBranch, "branch", "", "Name of the new branch (defaults to <current branch>-edit-<msg-num>)")
+	editConvoPromptCmd.Flags().BoolVarP(&tellStop, "stop", "s", false, "Stop after a single reply")
+	editConvoPromptCmd.Flags().BoolVarP(&tellNoBuild, "no-build", "n", false, "Don't build files")
+	editConvoPromptCmd.Flags().BoolVar(&tellBg, "bg", false, "Execute autonomously in the background")
+}
+
+func editConvoPrompt(cmd *cobra.Command, args []string) {
+	auth.MustResolveAuthWithOrg()
+	lib.MustResolveProject()
+
+	if lib.CurrentPlanId == "" {
+		term.OutputNoCurrentPlanErrorAndExit()
+	}
+
+	msgNum, err := strconv.Atoi(args[0])
+
+	if err != nil {
+		term.OutputErrorAndExit("Invalid message number: %s", args[0])
+	}
+
+	apiKeys := lib.MustVerifyApiKeys()
+
+	term.StartSpinner("")
+	convo, apiErr := api.Client.ListConvo(lib.CurrentPlanId, lib.CurrentBranch)
+	term.StopSpinner()
+
+	if apiErr != nil {
+		term.OutputErrorAndExit("Error getting conversation: %v", apiErr.Msg)
+	}
+
+	var msg *shared.ConvoMessage
+	for _, m := range convo {
+		if m.Num == msgNum {
+			msg = m
+			break
+		}
+	}
+
+	if msg == nil {
+		term.OutputErrorAndExit("Message %d not found", msgNum)
+	}
+
+	if msg.Role != "user" {
+		term.OutputErrorAndExit("Message %d is a reply from Plandex. Only your prompts can be edited.", msgNum)
+	}
+
+	branch := editConvoPromptBranch
+	if branch == "" {
+		branch = fmt.Sprintf("%s-edit-%d", lib.CurrentBranch, msgNum)
+	}
+
+	term.StartSpinner("")
+	branches, apiErr := api.Client.ListBranches(lib.CurrentPlanId)
+	term.StopSpinner()
+
+	if apiErr != nil {
+		term.OutputErrorAndExit("Error getting branches: %v", apiErr.Msg)
+	}
+
+	for _, b := range branches {
+		if b.Name == branch {
+			term.OutputErrorAndExit("Branch %s already exists. Pass --branch to use another name.", branch)
+		}
+	}
+
+	prompt := getEditorPromptWithText(msg.Message)
+
+	if prompt == "" {
+		fmt.Println("🤷‍♂️ No prompt to send")
+		return
+	}
+
+	if prompt == strings.TrimSpace(msg.Message) {
+		res, err := term.ConfirmYesNo("The prompt wasn't changed. Send it again on a new branch anyway?")
+
+		if err != nil {
+			term.OutputErrorAndExit("Error getting user input: %v", err)
+		}
+
+		if !res {
+			return
+		}
+	}
+
+	term.StartSpinner("")
+	apiErr = api.Client.CreateBranch(lib.CurrentPlanId, lib.CurrentBranch, shared.CreateBranchRequest{Name: branch})
+
+	if apiErr != nil {
+		term.OutputErrorAndExit("Error creating branch: %v", apiErr.Msg)
+	}
+
+	_, apiErr = api.Client.RewindPlan(lib.CurrentPlanId, branch, shared.RewindPlanRequest{BeforeMessageId: msg.Id})
+
+	if apiErr != nil {
+		// otherwise the new branch is left as a full copy of the current branch, and retrying fails because it already exists
+		deleteErr := api.Client.DeleteBranch(lib.CurrentPlanId, branch)
+		term.StopSpinner()
+
+		if deleteErr != nil {
+			term.OutputErrorAndExit("Error rewinding branch %s: %v. The branch couldn't be removed either: %v", branch, apiErr.Msg, deleteErr.Msg)
+		}
+
+		term.OutputErrorAndExit("Error rewinding branch %s: %v", branch, apiErr.Msg)
+	}
+
+	term.StopSpinner()
+
+	err = lib.WriteCurrentBranch(branch)
+
+	if err != nil {
+		term.OutputErrorAndExit("Error setting current branch: %v", err)
+	}
+
+	fmt.Printf("✅ Checked out new branch %s from before message %d\n\n", color.New(color.Bold, term.ColorHiGreen).Sprint(branch), msgNum)
+
+	plan_exec.TellPlan(plan_exec.ExecParams{
+		CurrentPlanId: lib.CurrentPlanId,
+		CurrentBranch: branch,
+		ApiKeys:       apiKeys,
+		CheckOutdatedContext: func(maybeContexts []*shared.Context) (bool, bool) {
+			return lib.MustCheckOutdatedContext(false, maybeContexts)
+		},
+	}, prompt, tellBg, tellStop, tellNoBuild, false, msg.IsChat)
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"plandex/api"
+	"plandex/auth"
+	"plandex/lib"
+	"plandex/term"
+	"strings"
+
+	"github.com/plandex/plandex/shared"
+	"github.com/spf13/cobra"
+)
+
+var exportFormat string
+var exportDiffs bool
+var exportOutput string
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export plan data",
+}
+
+var exportConvoCmd = &cobra.Command{
+	Use:   "convo [msg-range]",
+	Short: "Export the conversation as markdown, html, or json",
+	Long:  `Export the conversation as markdown, html, or json, including each message's role, tokens, timestamp, and the files each reply built. Optionally specify a message number or range of messages (e.g. '1' or '5' or '1-5' or '5-')`,
+	Args:  cobra.MaximumNArgs(1),
+	Run:   exportConvo,
+}
+
+func init() {
+	RootCmd.AddCommand(exportCmd)
+	exportCmd.AddCommand(exportConvoCmd)
+
+	exportConvoCmd.Flags().StringVarP(&exportFormat, "format", "f", string(lib.ConvoExportFormatMarkdown), "Export format: md, html, or json")
+	exportConvoCmd.Flags().BoolVar(&exportDiffs, "diffs", false, "Include the diff of the files each reply built")
+	exportConvoCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Write the export to a file instead of stdout")
+}
+
+func exportConvo(cmd *cobra.Command, args []string) {
+	auth.MustResolveAuthWithOrg()
+	lib.MustResolveProject()
+
+	if lib.CurrentPlanId == "" {
+		term.OutputNoCurrentPlanErrorAndExit()
+	}
+
+	format := lib.ConvoExportFormat(strings.ToLower(exportFormat))
+	if format == "markdown" {
+		format = lib.ConvoExportFormatMarkdown
+	}
+
+	switch format {
+	case lib.ConvoExportFormatMarkdown, lib.ConvoExportFormatHTML, lib.ConvoExportFormatJSON:
+	default:
+		term.OutputErrorAndExit("Unsupported format '%s' -- use md, html, or json", exportFormat)
+	}
+
+	term.StartSpinner("")
+
+	plan, apiErr := api.Client.GetPlan(lib.CurrentPlanId)
+
+	if apiErr != nil {
+		term.OutputErrorAndExit("Error getting plan: %v", apiErr.Msg)
+	}
+
+	conversation, apiErr := api.Client.ListConvo(lib.CurrentPlanId, lib.CurrentBranch)
+
+	if apiErr != nil {
+		term.OutputErrorAndExit("Error loading conversation: %v", apiErr.Msg)
+	}
+
+	state, apiErr := api.Client.GetCurrentPlanState(lib.CurrentPlanId, lib.CurrentBranch)
+
+	if apiErr != nil {
+		term.OutputErrorAndExit("Error getting current plan state: %v", apiErr.Msg)
+	}
+
+	var diffsByMessageId map[string]string
+	if exportDiffs {
+		diffsByMessageId, apiErr = api.Client.ListConvoDiffs(lib.CurrentPlanId, lib.CurrentBranch)
+
+		if apiErr != nil {
+			term.OutputErrorAndExit("Error getting diffs: %v", apiErr.Msg)
+		}
+	}
+
+	term.StopSpinner()
+
+	if len(conversation) == 0 {
+		fmt.Println("🤷‍♂️ No conversation history")
+		return
+	}
+
+	var msgRange string
+	if len(args) > 0 {
+		msgRange = args[0]
+	}
+	msgRangeStart, msgRangeEnd := mustParseMsgRange(msgRange, len(conversation))
+
+	var messages []*shared.ConvoMessage
+	for _, msg := range conversation {
+		if msgRangeStart > 0 && msg.Num < msgRangeStart {
+			continue
+		}
+		if msgRangeEnd > 0 && msg.Num > msgRangeEnd {
+			break
+		}
+		messages = append(messages, msg)
+	}
+
+	if len(messages) == 0 {
+		term.OutputErrorAndExit("No messages in range %s", msgRange)
+	}
+
+	export := lib.NewConvoExport(plan.Name, lib.CurrentBranch, messages, state.ConvoMessageDescriptions, diffsByMessageId)
+
+	res, err := export.Render(format)
+
+	if err != nil {
+		term.OutputErrorAndExit("Error exporting conversation: %v", err)
+	}
+
+	if exportOutput == "" {
+		fmt.Println(res)
+		return
+	}
+
+	err = os.WriteFile(exportOutput, []byte(res), 0644)
+
+	if err != nil {
+		term.OutputErrorAndExit("Error writing %s: %v", exportOutput, err)
+	}
+
+	fmt.Printf("✅ Exported conversation to %s\n", exportOutput)
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"plandex/api"
+	"plandex/auth"
+	"plandex/lib"
+	"plandex/term"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/plandex/plandex/shared"
+	"github.com/spf13/cobra"
+)
+
+var instructionsCmd = &cobra.Command{
+	Use:   "instructions",
+	Short: "Show the instructions included in every prompt",
+	Long: `Show the standing instructions, like coding standards, that are included in the planner, builder, and reviewer prompts of every plan.
+
+Project instructions are read from .plandex/instructions.md in the project root. Org instructions are part of the org's default settings and apply to every project in the org.
+
+	plandex instructions set-org standards.md
+	plandex instructions clear-org
+	`,
+	Args: cobra.NoArgs,
+	Run:  showInstructions,
+}
+
+var setOrgInstructionsCmd = &cobra.Command{
+	Use:   "set-org [file]",
+	Short: "Set org-wide instructions from a file or piped data",
+	Args:  cobra.MaximumNArgs(1),
+	Run:   setOrgInstructions,
+}
+
+var clearOrgInstructionsCmd = &cobra.Command{
+	Use:   "clear-org",
+	Short: "Remove org-wide instructions",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		updateOrgInstructions("")
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(instructionsCmd)
+	instructionsCmd.AddCommand(setOrgInstructionsCmd)
+	instructionsCmd.AddCommand(clearOrgInstructionsCmd)
+}
+
+func showInstructions(cmd *cobra.Command, args []string) {
+	auth.MustResolveAuthWithOrg()
+	lib.MaybeResolveProject()
+
+	term.StartSpinner("")
+	instructions, err := lib.GetInstructions()
+	term.StopSpinner()
+
+	if err != nil {
+		term.OutputErrorAndExit("Error getting instructions: %v", err)
+	}
+
+	if instructions.Project == "" && instructions.Org == "" {
+		fmt.Println("🤷‍♂️ No instructions")
+		fmt.Println()
+		fmt.Printf("Add project instructions to %s, or set org instructions with:\n", color.New(color.Bold).Sprint(".plandex/"+lib.InstructionsFile))
+		fmt.Println()
+		term.PrintCmds("", "instructions set-org")
+		return
+	}
+
+	if instructions.Org != "" {
+		color.New(color.Bold, term.ColorHiCyan).Printf("🏢 Org instructions | %d 🪙\n\n", instructions.OrgTokens)
+		fmt.Println(instructions.Org)
+		fmt.Println()
+	}
+
+	if instructions.Project != "" {
+		color.New(color.Bold, term.ColorHiCyan).Printf("📐 Project instructions (.plandex/%s) | %d 🪙\n\n", lib.InstructionsFile, instructions.ProjectTokens)
+		fmt.Println(instructions.Project)
+		fmt.Println()
+	}
+}
+
+func setOrgInstructions(cmd *cobra.Command, args []string) {
+	var bytes []byte
+	var err error
+
+	if len(args) == 1 {
+		bytes, err = os.ReadFile(args[0])
+		if err != nil {
+			term.OutputErrorAndExit("Error reading %s: %v", args[0], err)
+		}
+	} else {
+		fileInfo, err := os.Stdin.Stat()
+		if err != nil {
+			term.OutputErrorAndExit("Error reading stdin: %v", err)
+		}
+		if fileInfo.Mode()&os.ModeNamedPipe == 0 {
+			term.OutputErrorAndExit("Pass a file or pipe in the instructions")
+		}
+		bytes, err = io.ReadAll(os.Stdin)
+		if err != nil {
+			term.OutputErrorAndExit("Error reading stdin: %v", err)
+		}
+	}
+
+	instructions := strings.TrimSpace(string(bytes))
+	if instructions == "" {
+		term.OutputErrorAndExit("Instructions are empty. Use 'plandex instructions clear-org' to remove them.")
+	}
+
+	updateOrgInstructions(instructions)
+}
+
+func updateOrgInstructions(instructions string) {
+	auth.MustResolveAuthWithOrg()
+
+	term.StartSpinner("")
+	settings, apiErr := api.Client.GetOrgDefaultSettings()
+	if apiErr != nil {
+		term.StopSpinner()
+		term.OutputErrorAndExit("Error getting current settings: %v", apiErr)
+	}
+
+	if settings.Instructions == instructions {
+		term.StopSpinner()
+		fmt.Println("No changes to org instructions")
+		return
+	}
+
+	settings.Instructions = instructions
+
+	res, apiErr := api.Client.UpdateOrgDefaultSettings(shared.UpdateSettingsRequest{
+		Settings: settings,
+	})
+	term.StopSpinner()
+
+	if apiErr != nil {
+		term.OutputErrorAndExit("Error updating settings: %v", apiErr)
+	}
+
+	fmt.Println(res.Msg)
+	fmt.Println()
+	term.PrintCmds("", "instructions", "ls")
+}
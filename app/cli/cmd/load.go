@@ -17,14 +17,32 @@ var (
 	note            string
 	forceSkipIgnore bool
 	imageDetail     string
+	contextGroup    string
+	gitDiff         bool
+	gitCommit       string
+	gitStaged       bool
+	commands        []string
+	crawl           bool
+	crawlDepth      int
+	crawlMaxPages   int
+	crawlMaxTokens  int
 )
 
 var contextLoadCmd = &cobra.Command{
 	Use:     "load [files-or-urls...]",
 	Aliases: []string{"l", "add"},
 	Short:   "Load context from various inputs",
-	Long:    `Load context from a file path, a directory, a URL, an image, a note, or piped data.`,
-	Run:     contextLoad,
+	Long: `Load context from a file path, a directory, a URL, an image, a note, piped data, or the output of a command.
+Text is extracted from PDF, DOCX, and ODT documents.
+Context is scanned for secrets before it's sent. By default they're listed as a warning. Set "mode" to "redact" or "block" in .plandex/secrets.json to keep them on this machine.
+
+	plandex load server/db/result_helpers.go:340-575 # Load a range of lines
+	plandex load 'app/**/*.go' '!**/*_test.go' # Load a glob, excluding matches of a '!' pattern
+	plandex load --git-diff main # Load everything changed since branching from main
+	plandex load --crawl --depth 2 https://docs.example.com/guide # Load a page and the same-site pages it links to
+	plandex load --cmd "go test ./server/..." # Load a command's output, re-run on 'plandex update'
+	`,
+	Run: contextLoad,
 }
 
 func init() {
@@ -33,6 +51,15 @@ func init() {
 	contextLoadCmd.Flags().BoolVar(&namesOnly, "tree", false, "Load directory tree with file names only")
 	contextLoadCmd.Flags().BoolVarP(&forceSkipIgnore, "force", "f", false, "Load files even when ignored by .gitignore or .plandexignore")
 	contextLoadCmd.Flags().StringVarP(&imageDetail, "detail", "d", "high", "Image detail level (high or low)")
+	contextLoadCmd.Flags().StringVarP(&contextGroup, "group", "g", "", "Add the loaded context to a named group that can be disabled and enabled as a whole")
+	contextLoadCmd.Flags().BoolVar(&gitDiff, "git-diff", false, "Load the diff of uncommitted changes, or of all changes since branching from a base branch passed as an argument")
+	contextLoadCmd.Flags().StringVar(&gitCommit, "git-commit", "", "Load the message and diff of a commit")
+	contextLoadCmd.Flags().BoolVar(&gitStaged, "git-staged", false, "Load the diff of staged changes")
+	contextLoadCmd.Flags().StringArrayVar(&commands, "cmd", nil, "Run a command in the project root and load its output, which is refreshed by re-running the command when context is updated")
+	contextLoadCmd.Flags().BoolVar(&crawl, "crawl", false, "Load URLs along with the pages they link to on the same host, respecting robots.txt")
+	contextLoadCmd.Flags().IntVar(&crawlDepth, "depth", 1, "With --crawl, how many links away from each URL to follow")
+	contextLoadCmd.Flags().IntVar(&crawlMaxPages, "crawl-max-pages", 25, "With --crawl, the maximum number of pages to load per URL")
+	contextLoadCmd.Flags().IntVar(&crawlMaxTokens, "crawl-max-tokens", 50000, "With --crawl, the maximum number of tokens to load per URL")
 	RootCmd.AddCommand(contextLoadCmd)
 }
 
@@ -45,12 +72,38 @@ func contextLoad(cmd *cobra.Command, args []string) {
 		return
 	}
 
+	if !crawl && (cmd.Flags().Changed("depth") || cmd.Flags().Changed("crawl-max-pages") || cmd.Flags().Changed("crawl-max-tokens")) {
+		term.OutputErrorAndExit("--depth, --crawl-max-pages, and --crawl-max-tokens can only be used with --crawl")
+	}
+
+	var gitDiffBase string
+	if gitDiff {
+		// with --git-diff, the only argument is the base branch or commit
+		if len(args) > 1 {
+			term.OutputErrorAndExit("--git-diff takes at most one base branch or commit")
+		}
+		if len(args) == 1 {
+			gitDiffBase = args[0]
+			args = nil
+		}
+	}
+
 	lib.MustLoadContext(args, &types.LoadContextParams{
 		Note:            note,
 		Recursive:       recursive,
 		NamesOnly:       namesOnly,
 		ForceSkipIgnore: forceSkipIgnore,
 		ImageDetail:     openai.ImageURLDetail(imageDetail),
+		Group:           contextGroup,
+		GitDiff:         gitDiff,
+		GitDiffBase:     gitDiffBase,
+		GitCommit:       gitCommit,
+		GitStaged:       gitStaged,
+		Commands:        commands,
+		Crawl:           crawl,
+		CrawlDepth:      crawlDepth,
+		CrawlMaxPages:   crawlMaxPages,
+		CrawlMaxTokens:  crawlMaxTokens,
 	})
 
 	fmt.Println()
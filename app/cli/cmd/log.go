@@ -22,9 +22,13 @@ var logCmd = &cobra.Command{
 	Run:     runLog,
 }
 
+var logTags bool
+
 func init() {
 	// Add log command
 	RootCmd.AddCommand(logCmd)
+
+	logCmd.Flags().BoolVar(&logTags, "tags", false, "List tagged checkpoints")
 }
 
 func runLog(cmd *cobra.Command, args []string) {
@@ -35,6 +39,11 @@ func runLog(cmd *cobra.Command, args []string) {
 		term.OutputNoCurrentPlanErrorAndExit()
 	}
 
+	if logTags {
+		listPlanTags()
+		return
+	}
+
 	term.StartSpinner("")
 	res, apiErr := api.Client.ListLogs(lib.CurrentPlanId, lib.CurrentBranch)
 	term.StopSpinner()
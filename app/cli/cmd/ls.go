@@ -27,27 +27,52 @@ func listContext(cmd *cobra.Command, args []string) {
 	lib.MustResolveProject()
 
 	term.StartSpinner("")
-	contexts, err := api.Client.ListContext(lib.CurrentPlanId, lib.CurrentBranch)
+	contexts, apiErr := api.Client.ListContext(lib.CurrentPlanId, lib.CurrentBranch)
+	if apiErr != nil {
+		term.StopSpinner()
+		term.OutputErrorAndExit("Error listing context: %v", apiErr)
+	}
+
+	instructions, err := lib.GetInstructions()
 	term.StopSpinner()
 
 	if err != nil {
-		term.OutputErrorAndExit("Error listing context: %v", err)
+		term.OutputErrorAndExit("Error getting instructions: %v", err)
 	}
 
-	totalTokens := 0
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"#", "Name", "Type", "🪙", "Added", "Updated"})
-	table.SetAutoWrapText(false)
-
 	if len(contexts) == 0 {
 		fmt.Println("🤷‍♂️ No context")
 		fmt.Println()
+		renderInstructions(instructions)
 		term.PrintCmds("", "load")
 		return
 	}
 
+	hasGroups := false
+	for _, context := range contexts {
+		if context.Group != "" {
+			hasGroups = true
+			break
+		}
+	}
+
+	header := []string{"#", "Name", "Type", "🪙", "Added", "Updated"}
+	if hasGroups {
+		header = []string{"#", "Name", "Type", "Group", "🪙", "Added", "Updated"}
+	}
+
+	totalTokens := 0
+	disabledTokens := 0
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(header)
+	table.SetAutoWrapText(false)
+
 	for i, context := range contexts {
-		totalTokens += context.NumTokens
+		if context.Disabled {
+			disabledTokens += context.NumTokens
+		} else {
+			totalTokens += context.NumTokens
+		}
 
 		t, icon := context.TypeAndIcon()
 
@@ -60,13 +85,30 @@ func listContext(cmd *cobra.Command, args []string) {
 			strconv.Itoa(i + 1),
 			" " + icon + " " + name,
 			t,
+		}
+
+		if hasGroups {
+			group := context.Group
+			if context.Disabled {
+				group += " (disabled)"
+			}
+			row = append(row, group)
+		}
+
+		row = append(row,
 			strconv.Itoa(context.NumTokens), //+ " 🪙",
 			format.Time(context.CreatedAt),
 			format.Time(context.UpdatedAt),
+		)
+
+		nameColors := tablewriter.Colors{tablewriter.FgHiGreenColor, tablewriter.Bold}
+		if context.Disabled {
+			nameColors = tablewriter.Colors{tablewriter.FgHiBlackColor}
 		}
+
 		table.Rich(row, []tablewriter.Colors{
 			{tablewriter.Bold},
-			{tablewriter.FgHiGreenColor, tablewriter.Bold},
+			nameColors,
 		})
 	}
 
@@ -75,12 +117,41 @@ func listContext(cmd *cobra.Command, args []string) {
 	tokensTbl := tablewriter.NewWriter(os.Stdout)
 	tokensTbl.SetAutoWrapText(false)
 	tokensTbl.Append([]string{color.New(term.ColorHiCyan, color.Bold).Sprintf("Total tokens →") + color.New(color.Bold).Sprintf(" %d 🪙", totalTokens)})
+	if disabledTokens > 0 {
+		tokensTbl.Append([]string{color.New(color.FgHiBlack).Sprintf("Disabled → %d 🪙", disabledTokens)})
+	}
 
 	tokensTbl.Render()
 
 	fmt.Println()
-	term.PrintCmds("", "load", "rm", "clear")
+	renderInstructions(instructions)
+	if hasGroups {
+		term.PrintCmds("", "load", "rm", "clear", "context disable", "context enable")
+	} else {
+		term.PrintCmds("", "load", "rm", "clear")
+	}
+
+}
+
+// renderInstructions lists instructions separately from context, since they're always included and can't be removed with 'plandex rm'
+func renderInstructions(instructions *lib.Instructions) {
+	if instructions.Project == "" && instructions.Org == "" {
+		return
+	}
 
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Instructions", "🪙"})
+	table.SetAutoWrapText(false)
+
+	if instructions.Project != "" {
+		table.Append([]string{" 📐 .plandex/" + lib.InstructionsFile, strconv.Itoa(instructions.ProjectTokens)})
+	}
+	if instructions.Org != "" {
+		table.Append([]string{" 🏢 org default settings", strconv.Itoa(instructions.OrgTokens)})
+	}
+
+	table.Render()
+	fmt.Println()
 }
 
 func init() {
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"fmt"
+	"plandex/api"
+	"plandex/auth"
+	"plandex/lib"
+	"plandex/term"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/plandex/plandex/shared"
+	"github.com/spf13/cobra"
+)
+
+var mergeForce bool
+
+var mergeCmd = &cobra.Command{
+	Use:   "merge [name-or-index]",
+	Short: "Merge a branch's context, conversation, and pending changes into the current branch",
+	Long: `Merge a branch's context, conversation, and pending changes into the current branch.
+
+The branch's messages are added after the current branch's conversation. Where both branches loaded the same file, the most recently updated version is kept.
+
+If both branches have pending changes to the same file, nothing is merged until you confirm that the branch's pending changes should replace the current branch's changes to those files. Pass --force to skip the confirmation.`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  merge,
+}
+
+func init() {
+	RootCmd.AddCommand(mergeCmd)
+
+	mergeCmd.Flags().BoolVarP(&mergeForce, "force", "f", false, "Replace pending changes on the current branch where both branches have pending changes to the same file")
+}
+
+func merge(cmd *cobra.Command, args []string) {
+	auth.MustResolveAuthWithOrg()
+	lib.MustResolveProject()
+
+	if lib.CurrentPlanId == "" {
+		term.OutputNoCurrentPlanErrorAndExit()
+	}
+
+	var nameOrIdx string
+	if len(args) > 0 {
+		nameOrIdx = strings.TrimSpace(args[0])
+	}
+
+	term.StartSpinner("")
+	branches, apiErr := api.Client.ListBranches(lib.CurrentPlanId)
+	term.StopSpinner()
+
+	if apiErr != nil {
+		term.OutputErrorAndExit("Error getting branches: %v", apiErr.Msg)
+	}
+
+	var branch string
+
+	if nameOrIdx == "" {
+		var opts []string
+		for _, b := range branches {
+			if b.Name != lib.CurrentBranch {
+				opts = append(opts, b.Name)
+			}
+		}
+
+		if len(opts) == 0 {
+			fmt.Println("🤷‍♂️ No other branches to merge")
+			return
+		}
+
+		sel, err := term.SelectFromList("Select a branch to merge", opts)
+
+		if err != nil {
+			term.OutputErrorAndExit("Error selecting branch: %v", err)
+		}
+
+		branch = sel
+	} else if idx, err := strconv.Atoi(nameOrIdx); err == nil {
+		if idx > 0 && idx <= len(branches) {
+			branch = branches[idx-1].Name
+		} else {
+			term.OutputErrorAndExit("Branch index out of range")
+		}
+	} else {
+		for _, b := range branches {
+			if b.Name == nameOrIdx {
+				branch = b.Name
+				break
+			}
+		}
+
+		if branch == "" {
+			fmt.Printf("🤷‍♂️ Branch %s does not exist\n", color.New(color.Bold, term.ColorHiCyan).Sprint(nameOrIdx))
+			return
+		}
+	}
+
+	if branch == lib.CurrentBranch {
+		term.OutputErrorAndExit("Can't merge a branch into itself")
+	}
+
+	term.StartSpinner("")
+	res, apiErr := api.Client.MergeBranch(lib.CurrentPlanId, lib.CurrentBranch, shared.MergeBranchRequest{
+		Branch: branch,
+		Force:  mergeForce,
+	})
+	term.StopSpinner()
+
+	if apiErr != nil {
+		term.OutputErrorAndExit("Error merging branch: %v", apiErr.Msg)
+	}
+
+	if !res.Merged && len(res.ConflictedPaths) > 0 {
+		fmt.Printf("⚠️  Both %s and %s have pending changes to:\n", color.New(color.Bold, term.ColorHiCyan).Sprint(lib.CurrentBranch), color.New(color.Bold, term.ColorHiCyan).Sprint(branch))
+		for _, path := range res.ConflictedPaths {
+			fmt.Printf("  • %s\n", path)
+		}
+		fmt.Println()
+
+		shouldContinue, err := term.ConfirmYesNo("Replace the pending changes to these files on %s with the changes from %s?", lib.CurrentBranch, branch)
+
+		if err != nil {
+			term.OutputErrorAndExit("failed to get confirmation user input: %s", err)
+		}
+
+		if !shouldContinue {
+			fmt.Println("Merge canceled")
+			return
+		}
+
+		term.StartSpinner("")
+		res, apiErr = api.Client.MergeBranch(lib.CurrentPlanId, lib.CurrentBranch, shared.MergeBranchRequest{
+			Branch: branch,
+			Force:  true,
+		})
+		term.StopSpinner()
+
+		if apiErr != nil {
+			term.OutputErrorAndExit("Error merging branch: %v", apiErr.Msg)
+		}
+	}
+
+	if !res.Merged {
+		fmt.Printf("🤷‍♂️ %s has nothing to merge into %s\n", color.New(color.Bold, term.ColorHiCyan).Sprint(branch), color.New(color.Bold, term.ColorHiCyan).Sprint(lib.CurrentBranch))
+		return
+	}
+
+	fmt.Println(res.Msg)
+	fmt.Println()
+	term.PrintCmds("", "convo", "changes", "log")
+}
@@ -211,6 +211,8 @@ func createModelPack(cmd *cobra.Command, args []string) {
 	mp.Verifier = &verifier
 	autoFix := getModelRoleConfig(customModels, shared.ModelRoleAutoFix)
 	mp.AutoFix = &autoFix
+	reviewer := getModelRoleConfig(customModels, shared.ModelRoleReviewer)
+	mp.Reviewer = &reviewer
 
 	term.StartSpinner("")
 	apiErr = api.Client.CreateModelPack(mp)
@@ -453,6 +453,7 @@ func renderSettings(settings *shared.PlanSettings) {
 	addModelRow(string(shared.ModelRoleExecStatus), modelPack.ExecStatus)
 	addModelRow(string(shared.ModelRoleVerifier), modelPack.GetVerifier())
 	addModelRow(string(shared.ModelRoleAutoFix), modelPack.GetAutoFix())
+	addModelRow(string(shared.ModelRoleReviewer), modelPack.GetReviewer())
 	table.Render()
 
 	fmt.Println()
@@ -205,6 +205,10 @@ func listActive() {
 				name = p.Name
 			}
 
+			if p.OwnerId != auth.Current.UserId {
+				name += " 👥"
+			}
+
 			currentBranch := currentBranchesByPlanId[p.Id]
 
 			row := []string{
@@ -0,0 +1,259 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"plandex/api"
+	"plandex/auth"
+	"plandex/lib"
+	"plandex/term"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/plandex/plandex/shared"
+	"github.com/spf13/cobra"
+)
+
+var promptsOrg bool
+var promptsVersion string
+
+var promptsCmd = &cobra.Command{
+	Use:   "prompts",
+	Short: "List the prompt templates in effect",
+	Long: `List the prompt templates in effect for the current plan, or for the org with --org.
+
+Prompts can be overridden for the org or for a single plan. Plan overrides take precedence over org overrides, which take precedence over the defaults. Templates use Go template syntax and can include these variables:
+
+	{{.Identity}}  the assistant's identity statement
+
+Every reply in the conversation records the version of the planner prompt that produced it.`,
+	Args: cobra.NoArgs,
+	Run:  listPrompts,
+}
+
+var showPromptCmd = &cobra.Command{
+	Use:   "show [name]",
+	Short: "Show a prompt template, or a past version with --version",
+	Args:  cobra.MaximumNArgs(1),
+	Run:   showPrompt,
+}
+
+var editPromptCmd = &cobra.Command{
+	Use:   "edit <name>",
+	Short: "Override a prompt template in your editor",
+	Args:  cobra.ExactArgs(1),
+	Run:   editPrompt,
+}
+
+var resetPromptCmd = &cobra.Command{
+	Use:   "reset <name>",
+	Short: "Remove an override of a prompt template",
+	Args:  cobra.ExactArgs(1),
+	Run:   resetPrompt,
+}
+
+func init() {
+	RootCmd.AddCommand(promptsCmd)
+	promptsCmd.AddCommand(showPromptCmd)
+	promptsCmd.AddCommand(editPromptCmd)
+	promptsCmd.AddCommand(resetPromptCmd)
+
+	promptsCmd.PersistentFlags().BoolVarP(&promptsOrg, "org", "o", false, "Use the org's prompts instead of the current plan's")
+	showPromptCmd.Flags().StringVar(&promptsVersion, "version", "", "Show the template with this version")
+}
+
+// resolvePromptsPlanId returns the plan prompts apply to, or an empty string for the org
+func resolvePromptsPlanId() string {
+	auth.MustResolveAuthWithOrg()
+
+	if promptsOrg {
+		return ""
+	}
+
+	lib.MaybeResolveProject()
+
+	if lib.CurrentPlanId == "" {
+		fmt.Println("🤷‍♂️ No current plan, so using the org's prompts")
+		fmt.Println()
+		return ""
+	}
+
+	return lib.CurrentPlanId
+}
+
+func mustGetPromptName(arg string) shared.PromptName {
+	if !shared.IsValidPromptName(arg) {
+		var names []string
+		for _, name := range shared.AllPromptNames {
+			names = append(names, string(name))
+		}
+		term.OutputErrorAndExit("Unknown prompt '%s'. Prompts are: %s", arg, strings.Join(names, ", "))
+	}
+	return shared.PromptName(arg)
+}
+
+func mustGetPrompt(planId string, name shared.PromptName) *shared.PromptTemplate {
+	term.StartSpinner("")
+	templates, apiErr := api.Client.ListPrompts(planId)
+	term.StopSpinner()
+
+	if apiErr != nil {
+		term.OutputErrorAndExit("Error getting prompts: %v", apiErr.Msg)
+	}
+
+	for _, tmpl := range templates {
+		if tmpl.Name == name {
+			return tmpl
+		}
+	}
+
+	term.OutputErrorAndExit("Prompt '%s' not found", name)
+	return nil
+}
+
+func listPrompts(cmd *cobra.Command, args []string) {
+	planId := resolvePromptsPlanId()
+
+	term.StartSpinner("")
+	templates, apiErr := api.Client.ListPrompts(planId)
+	term.StopSpinner()
+
+	if apiErr != nil {
+		term.OutputErrorAndExit("Error getting prompts: %v", apiErr.Msg)
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetAutoWrapText(false)
+	table.SetHeader([]string{"Name", "Source", "Version", "Description"})
+
+	for _, tmpl := range templates {
+		source := string(tmpl.Scope)
+		if tmpl.Scope != shared.PromptScopeDefault {
+			source = color.New(color.Bold, term.ColorHiGreen).Sprint(source)
+		}
+		table.Append([]string{string(tmpl.Name), source, tmpl.Version, shared.PromptDescriptions[tmpl.Name]})
+	}
+
+	table.Render()
+	fmt.Println()
+
+	term.PrintCmds("", "prompts show", "prompts edit", "prompts reset")
+}
+
+func showPrompt(cmd *cobra.Command, args []string) {
+	var tmpl *shared.PromptTemplate
+
+	if promptsVersion != "" {
+		auth.MustResolveAuthWithOrg()
+
+		// the same template can be set for more than one prompt, so a name narrows it down
+		var name shared.PromptName
+		if len(args) > 0 {
+			name = mustGetPromptName(args[0])
+		}
+
+		term.StartSpinner("")
+		var apiErr *shared.ApiError
+		tmpl, apiErr = api.Client.GetPromptVersion(name, promptsVersion)
+		term.StopSpinner()
+
+		if apiErr != nil {
+			term.OutputErrorAndExit("Error getting prompt version: %v", apiErr.Msg)
+		}
+	} else {
+		if len(args) == 0 {
+			term.OutputErrorAndExit("Pass a prompt name or --version")
+		}
+		name := mustGetPromptName(args[0])
+		tmpl = mustGetPrompt(resolvePromptsPlanId(), name)
+	}
+
+	color.New(color.Bold, term.ColorHiCyan).Printf("📝 %s | %s | version %s\n\n", tmpl.Name, tmpl.Scope, tmpl.Version)
+	fmt.Println(tmpl.Template)
+}
+
+func editPrompt(cmd *cobra.Command, args []string) {
+	name := mustGetPromptName(args[0])
+	planId := resolvePromptsPlanId()
+	current := mustGetPrompt(planId, name)
+
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = os.Getenv("VISUAL")
+		if editor == "" {
+			editor = defaultEditor
+		}
+	}
+
+	tempFile, err := os.CreateTemp(os.TempDir(), fmt.Sprintf("plandex_prompt_%s_*.tmpl", name))
+	if err != nil {
+		term.OutputErrorAndExit("Failed to create temporary file: %v", err)
+	}
+	filename := tempFile.Name()
+	tempFile.Close()
+	defer os.Remove(filename)
+
+	err = os.WriteFile(filename, []byte(current.Template), 0644)
+	if err != nil {
+		term.OutputErrorAndExit("Failed to write template to temporary file: %v", err)
+	}
+
+	editorCmd := prepareEditorCommand(editor, filename)
+	editorCmd.Stdin = os.Stdin
+	editorCmd.Stdout = os.Stdout
+	editorCmd.Stderr = os.Stderr
+	err = editorCmd.Run()
+	if err != nil {
+		term.OutputErrorAndExit("Error opening editor: %v", err)
+	}
+
+	bytes, err := os.ReadFile(filename)
+	if err != nil {
+		term.OutputErrorAndExit("Error reading temporary file: %v", err)
+	}
+	updated := string(bytes)
+
+	if updated == current.Template {
+		fmt.Println("🤷‍♂️ No changes to the prompt")
+		return
+	}
+
+	if strings.TrimSpace(updated) == "" {
+		term.OutputErrorAndExit("The prompt is empty. Use 'plandex prompts reset %s' to go back to the default.", name)
+	}
+
+	term.StartSpinner("")
+	tmpl, apiErr := api.Client.UpdatePrompt(planId, name, shared.UpdatePromptTemplateRequest{Template: updated})
+	term.StopSpinner()
+
+	if apiErr != nil {
+		term.OutputErrorAndExit("Error updating prompt: %v", apiErr.Msg)
+	}
+
+	fmt.Printf("✅ Updated the %s prompt for the %s | version %s\n", color.New(color.Bold).Sprint(name), tmpl.Scope, tmpl.Version)
+	fmt.Println()
+	term.PrintCmds("", "prompts", "prompts reset")
+}
+
+func resetPrompt(cmd *cobra.Command, args []string) {
+	name := mustGetPromptName(args[0])
+	planId := resolvePromptsPlanId()
+
+	term.StartSpinner("")
+	apiErr := api.Client.DeletePrompt(planId, name)
+	term.StopSpinner()
+
+	if apiErr != nil {
+		term.OutputErrorAndExit("Error resetting prompt: %v", apiErr.Msg)
+	}
+
+	scope := shared.PromptScopePlan
+	if planId == "" {
+		scope = shared.PromptScopeOrg
+	}
+
+	fmt.Printf("✅ Removed the %s's override of the %s prompt\n", scope, color.New(color.Bold).Sprint(name))
+	fmt.Println()
+	term.PrintCmds("", "prompts")
+}
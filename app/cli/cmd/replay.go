@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"plandex/auth"
+	"plandex/lib"
+	"plandex/plan_exec"
+	"plandex/term"
+
+	"github.com/spf13/cobra"
+)
+
+var replayPack string
+var replayFrom int
+var replayBranch string
+
+var replayCmd = &cobra.Command{
+	Use:   "replay",
+	Short: "Replay the plan's prompts on a new branch with a different model pack",
+	Long: `Replay the plan's prompts on a new branch with a different model pack.
+
+A branch is created from the current branch and rewound to before message --from. Each prompt from there on is then sent again in order with the model pack, building files as it goes. Context loaded, updated, or removed between prompts in the original run is changed the same way before each prompt is sent. After each prompt, any files it built that differ from the files built by the original run are listed.`,
+	Args: cobra.NoArgs,
+	Run:  replay,
+}
+
+func init() {
+	RootCmd.AddCommand(replayCmd)
+
+	replayCmd.Flags().StringVar(&replayPack, "pack", "", "Model pack to replay the prompts with")
+	replayCmd.Flags().IntVar(&replayFrom, "from", 1, "Number of the conversation message to replay from")
+	replayCmd.Flags().StringVar(&replayBranch, "branch", "", "Name of the branch to replay on (defaults to <current branch>-replay-<pack>)")
+	replayCmd.MarkFlagRequired("pack")
+}
+
+func replay(cmd *cobra.Command, args []string) {
+	auth.MustResolveAuthWithOrg()
+	lib.MustResolveProject()
+
+	if lib.CurrentPlanId == "" {
+		term.OutputNoCurrentPlanErrorAndExit()
+	}
+
+	apiKeys := lib.MustVerifyApiKeys()
+
+	plan_exec.ReplayPlan(plan_exec.ExecParams{
+		CurrentPlanId: lib.CurrentPlanId,
+		CurrentBranch: lib.CurrentBranch,
+		ApiKeys:       apiKeys,
+		// context is checked on the replay branch once it's created
+	}, plan_exec.ReplayParams{
+		PackName: replayPack,
+		FromNum:  replayFrom,
+		Branch:   replayBranch,
+	})
+}
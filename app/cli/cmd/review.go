@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"plandex/api"
+	"plandex/auth"
+	"plandex/lib"
+	"plandex/term"
+
+	"github.com/plandex/plandex/shared"
+	"github.com/spf13/cobra"
+)
+
+var reviewShowOnly bool
+
+var reviewCmd = &cobra.Command{
+	Use:   "review",
+	Short: "Review pending changes for bugs and security issues",
+	Run:   review,
+}
+
+func init() {
+	RootCmd.AddCommand(reviewCmd)
+
+	reviewCmd.Flags().BoolVarP(&reviewShowOnly, "show", "s", false, "Show the latest review of the pending changes without running a new one")
+}
+
+func review(cmd *cobra.Command, args []string) {
+	auth.MustResolveAuthWithOrg()
+	lib.MustResolveProject()
+
+	if lib.CurrentPlanId == "" {
+		term.OutputNoCurrentPlanErrorAndExit()
+	}
+
+	term.StartSpinner("")
+	currentPlanState, apiErr := api.Client.GetCurrentPlanState(lib.CurrentPlanId, lib.CurrentBranch)
+	term.StopSpinner()
+
+	if apiErr != nil {
+		term.OutputErrorAndExit("Error getting current plan state: %v", apiErr.Msg)
+	}
+
+	if len(currentPlanState.PlanResult.SortedPaths) == 0 {
+		fmt.Println("🤷‍♂️ No pending changes to review")
+		return
+	}
+
+	if reviewShowOnly {
+		if currentPlanState.Review == nil {
+			fmt.Println("🤷‍♂️ The pending changes haven't been reviewed yet")
+			fmt.Println()
+			term.PrintCmds("", "review")
+			return
+		}
+
+		lib.DisplayReview(currentPlanState.Review)
+		return
+	}
+
+	apiKeys := lib.MustVerifyApiKeys()
+
+	openAIBase := os.Getenv("OPENAI_API_BASE")
+	if openAIBase == "" {
+		openAIBase = os.Getenv("OPENAI_ENDPOINT")
+	}
+
+	projectInstructions, err := lib.GetProjectInstructions()
+	if err != nil {
+		term.OutputErrorAndExit("Error loading project instructions: %v", err)
+	}
+
+	term.StartSpinner("🔎 Reviewing pending changes...")
+	review, apiErr := api.Client.ReviewPlan(lib.CurrentPlanId, lib.CurrentBranch, shared.ReviewPlanRequest{
+		ApiKeys:             apiKeys,
+		OpenAIBase:          openAIBase,
+		OpenAIOrgId:         os.Getenv("OPENAI_ORG_ID"),
+		ProjectInstructions: projectInstructions,
+	})
+	term.StopSpinner()
+
+	if apiErr != nil {
+		term.OutputErrorAndExit("Error reviewing pending changes: %v", apiErr.Msg)
+	}
+
+	lib.DisplayReview(review)
+
+	fmt.Println()
+	term.PrintCmds("", "changes", "apply", "reject")
+}
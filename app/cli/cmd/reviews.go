@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"plandex/api"
+	"plandex/auth"
+	"plandex/lib"
+	"plandex/term"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/plandex/plandex/shared"
+	"github.com/spf13/cobra"
+)
+
+var requestReviewCmd = &cobra.Command{
+	Use:   "request-review <reviewer> [reviewers...]",
+	Short: "Ask teammates to approve pending changes before they're applied",
+	Long: `Ask teammates to approve pending changes before they're applied. Reviewers can be given by email, name, or the part of their email before the @, with or without a leading @.
+
+Reviewers approve or comment on files and individual changes with 'plandex changes'. Until every reviewer has approved every pending file, only org owners and admins can apply the changes.`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  requestReview,
+}
+
+var reviewsCmd = &cobra.Command{
+	Use:   "reviews",
+	Short: "List requested reviews, approvals, and comments on pending changes",
+	Args:  cobra.NoArgs,
+	Run:   reviews,
+}
+
+func init() {
+	RootCmd.AddCommand(requestReviewCmd)
+	RootCmd.AddCommand(reviewsCmd)
+}
+
+func requestReview(cmd *cobra.Command, args []string) {
+	auth.MustResolveAuthWithOrg()
+	lib.MustResolveProject()
+
+	if lib.CurrentPlanId == "" {
+		term.OutputNoCurrentPlanErrorAndExit()
+	}
+
+	term.StartSpinner("")
+	reviewState, apiErr := api.Client.RequestReview(lib.CurrentPlanId, lib.CurrentBranch, shared.RequestReviewRequest{
+		Reviewers: args,
+	})
+	term.StopSpinner()
+
+	if apiErr != nil {
+		term.OutputErrorAndExit("Error requesting review: %v", apiErr.Msg)
+	}
+
+	var reviewers []string
+	for _, request := range reviewState.Requests {
+		reviewers = append(reviewers, request.ReviewerEmail)
+	}
+
+	fmt.Printf("👀 Waiting on review from %s\n", color.New(color.Bold).Sprint(strings.Join(reviewers, ", ")))
+	fmt.Println()
+	term.PrintCmds("", "reviews")
+}
+
+func reviews(cmd *cobra.Command, args []string) {
+	auth.MustResolveAuthWithOrg()
+	lib.MustResolveProject()
+
+	if lib.CurrentPlanId == "" {
+		term.OutputNoCurrentPlanErrorAndExit()
+	}
+
+	term.StartSpinner("")
+	currentPlanState, apiErr := api.Client.GetCurrentPlanState(lib.CurrentPlanId, lib.CurrentBranch)
+	term.StopSpinner()
+
+	if apiErr != nil {
+		term.OutputErrorAndExit("Error getting current plan state: %v", apiErr.Msg)
+	}
+
+	reviewState := currentPlanState.ReviewState
+	if reviewState == nil || len(reviewState.Requests) == 0 {
+		fmt.Println("🤷‍♂️ No reviews requested for the pending changes")
+		fmt.Println()
+		term.PrintCmds("", "request-review")
+		return
+	}
+
+	lib.DisplayReviewState(reviewState, currentPlanState.PlanResult)
+	fmt.Println()
+
+	if len(reviewState.PendingRequests(currentPlanState.PlanResult)) == 0 {
+		fmt.Println("✅ All requested reviews are approved")
+		fmt.Println()
+		term.PrintCmds("", "apply")
+	} else {
+		term.PrintCmds("", "changes")
+	}
+}
@@ -14,12 +14,12 @@ import (
 
 // rewindCmd represents the rewind command
 var rewindCmd = &cobra.Command{
-	Use:     "rewind [steps-or-sha]",
+	Use:     "rewind [steps-sha-or-tag]",
 	Aliases: []string{"rw"},
 	Short:   "Rewind the plan to an earlier state",
 	Long: `Rewind the plan to an earlier state.
 	
-	You can pass a "steps" number or a commit sha. If a steps number is passed, the plan will be rewound that many steps. If a commit sha is passed, the plan will be rewound to that commit. If neither a steps number nor a commit sha is passed, the target scope will be rewound by 1 step.
+	You can pass a "steps" number, a commit sha, or a tag. If a steps number is passed, the plan will be rewound that many steps. If a commit sha is passed, the plan will be rewound to that commit. If a tag is passed, the plan will be rewound to the checkpoint tagged with 'plandex tag'. If none of these is passed, the target scope will be rewound by 1 step.
 	`,
 	Args: cobra.MaximumNArgs(1),
 	Run:  rewind,
@@ -79,8 +79,30 @@ func rewind(cmd *cobra.Command, args []string) {
 
 	// log.Println("Rewinding to", targetSha)
 
+	req := shared.RewindPlanRequest{Sha: targetSha}
+	var targetTag string
+
+	if isSha {
+		tags, apiErr := api.Client.ListPlanTags(lib.CurrentPlanId, lib.CurrentBranch)
+
+		if apiErr != nil {
+			term.OutputErrorAndExit("Error getting tags: %v", apiErr)
+		}
+
+		for _, tag := range tags {
+			if tag.Name == stepsOrSha {
+				if !tag.OnBranch {
+					term.OutputErrorAndExit("Tag %s isn't in the history of branch %s. Check out the branch it was created on to rewind to it.", tag.Name, lib.CurrentBranch)
+				}
+				targetTag = tag.Name
+				req = shared.RewindPlanRequest{Tag: tag.Name}
+				break
+			}
+		}
+	}
+
 	// Rewind to the target sha
-	_, apiErr = api.Client.RewindPlan(lib.CurrentPlanId, lib.CurrentBranch, shared.RewindPlanRequest{Sha: targetSha})
+	rwRes, apiErr := api.Client.RewindPlan(lib.CurrentPlanId, lib.CurrentBranch, req)
 	term.StopSpinner()
 
 	if apiErr != nil {
@@ -88,7 +110,9 @@ func rewind(cmd *cobra.Command, args []string) {
 	}
 
 	var msg string
-	if isSha {
+	if targetTag != "" {
+		msg = fmt.Sprintf("✅ Rewound to tag %s at %s", targetTag, rwRes.LatestSha)
+	} else if isSha {
 		msg = "✅ Rewound to " + targetSha
 	} else {
 		postfix := "s"
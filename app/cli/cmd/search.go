@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"fmt"
+	"plandex/api"
+	"plandex/auth"
+	"plandex/lib"
+	"plandex/term"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/plandex/plandex/shared"
+	"github.com/spf13/cobra"
+)
+
+var searchAllProjects bool
+
+const searchOptDontJump = "Don't jump"
+
+var searchSnippetMatchRegex = regexp.MustCompile(regexp.QuoteMeta(shared.PlanSearchMatchStart) + `(.*?)` + regexp.QuoteMeta(shared.PlanSearchMatchStop))
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search plan names, conversations, and commit messages",
+	Long:  `Search plan names, conversations, and commit messages. Supports quoted phrases, 'or', and '-' to exclude words (e.g. '"refactor locks" -test'). Select a result to jump straight to its plan and branch.`,
+	Args:  cobra.MinimumNArgs(1),
+	Run:   search,
+}
+
+func init() {
+	RootCmd.AddCommand(searchCmd)
+
+	searchCmd.Flags().BoolVarP(&searchAllProjects, "all-projects", "a", false, "Search plans in all projects")
+}
+
+func search(cmd *cobra.Command, args []string) {
+	auth.MustResolveAuthWithOrg()
+	lib.MustResolveProject()
+
+	query := strings.TrimSpace(strings.Join(args, " "))
+
+	if query == "" {
+		term.OutputErrorAndExit("Search query is empty")
+	}
+
+	term.StartSpinner("")
+
+	projectIds := []string{lib.CurrentProjectId}
+	projectNamesById := map[string]string{}
+
+	if searchAllProjects {
+		projects, apiErr := api.Client.ListProjects()
+
+		if apiErr != nil {
+			term.OutputErrorAndExit("Error getting projects: %v", apiErr.Msg)
+		}
+
+		projectIds = nil
+		for _, project := range projects {
+			projectIds = append(projectIds, project.Id)
+			projectNamesById[project.Id] = project.Name
+		}
+	}
+
+	res, apiErr := api.Client.SearchPlans(query, projectIds)
+
+	term.StopSpinner()
+
+	if apiErr != nil {
+		term.OutputErrorAndExit("Error searching plans: %v", apiErr.Msg)
+	}
+
+	if len(res.Results) == 0 {
+		fmt.Printf("🤷‍♂️ No results for %s\n", color.New(color.Bold).Sprint(query))
+		if !searchAllProjects {
+			fmt.Println()
+			fmt.Printf("Use %s to search plans in all projects\n", color.New(color.Bold, term.ColorHiCyan).Sprint("plandex search --all-projects"))
+		}
+		return
+	}
+
+	resultsLabel := "results"
+	if len(res.Results) == 1 {
+		resultsLabel = "result"
+	}
+	fmt.Printf("🔎 %d %s for %s\n\n", len(res.Results), resultsLabel, color.New(color.Bold).Sprint(query))
+
+	var jumpOpts []string
+	jumpResultsByOpt := map[string]*shared.PlanSearchResult{}
+
+	for i, result := range res.Results {
+		header := fmt.Sprintf("%d. %s", i+1, color.New(color.Bold, term.ColorHiCyan).Sprint(result.PlanName))
+		if result.ProjectId != lib.CurrentProjectId {
+			projectName := projectNamesById[result.ProjectId]
+			if projectName == "" {
+				projectName = "another project"
+			}
+			header += color.New(color.FgHiBlack).Sprintf(" · in %s", projectName)
+		}
+		fmt.Println(header)
+
+		switch result.Kind {
+		case shared.PlanSearchResultKindPlan:
+			fmt.Println("   📋 plan name")
+		case shared.PlanSearchResultKindDescription:
+			fmt.Printf("   ✏️  commit message for #%d on %s\n", result.MessageNum, strings.Join(result.Branches, ", "))
+		default:
+			icon := "💬"
+			if result.Role == "assistant" {
+				icon = "🤖"
+			}
+			fmt.Printf("   %s message #%d on %s\n", icon, result.MessageNum, strings.Join(result.Branches, ", "))
+		}
+
+		if result.Kind != shared.PlanSearchResultKindPlan {
+			fmt.Printf("   %s\n", highlightSearchSnippet(result.Snippet))
+		}
+
+		fmt.Println()
+
+		// plans in other projects can't be set as current from here
+		if result.ProjectId == lib.CurrentProjectId {
+			opt := fmt.Sprintf("%d. %s", i+1, result.PlanName)
+			if result.Kind != shared.PlanSearchResultKindPlan {
+				opt += fmt.Sprintf(" · #%d on %s", result.MessageNum, searchResultBranch(result))
+			}
+			jumpOpts = append(jumpOpts, opt)
+			jumpResultsByOpt[opt] = result
+		}
+	}
+
+	if len(jumpOpts) == 0 {
+		fmt.Println("To jump to a result in another project, cd into the project's directory and run the search there")
+		return
+	}
+
+	selected, err := term.SelectFromList("Jump to a result?", append(jumpOpts, searchOptDontJump))
+
+	if err != nil {
+		term.OutputErrorAndExit("Error selecting result: %v", err)
+	}
+
+	result, ok := jumpResultsByOpt[selected]
+	if !ok {
+		return
+	}
+
+	if result.PlanId != lib.CurrentPlanId {
+		err = lib.WriteCurrentPlan(result.PlanId)
+
+		if err != nil {
+			term.OutputErrorAndExit("Error setting current plan: %v", err)
+		}
+
+		// reload current plan, which will also handle setting the right branch
+		lib.MustLoadCurrentPlan()
+
+		// fire and forget, as in 'plandex cd'
+		go api.Client.SetProjectPlan(lib.CurrentProjectId, shared.SetProjectPlanRequest{PlanId: result.PlanId})
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	branch := lib.CurrentBranch
+	if result.Kind != shared.PlanSearchResultKindPlan {
+		branch = searchResultBranch(result)
+
+		if branch != lib.CurrentBranch {
+			err = lib.WriteCurrentBranch(branch)
+
+			if err != nil {
+				term.OutputErrorAndExit("Error setting current branch: %v", err)
+			}
+		}
+	}
+
+	fmt.Printf("✅ Changed current plan to %s on branch %s\n", color.New(term.ColorHiGreen, color.Bold).Sprint(result.PlanName), color.New(color.Bold, term.ColorHiCyan).Sprint(branch))
+
+	if result.Kind != shared.PlanSearchResultKindPlan {
+		fmt.Println()
+		fmt.Printf("Use %s to see the message\n", color.New(color.Bold, term.ColorHiCyan).Sprintf("plandex convo %d", result.MessageNum))
+	}
+}
+
+// searchResultBranch prefers the current branch if the result is on it
+func searchResultBranch(result *shared.PlanSearchResult) string {
+	if result.PlanId == lib.CurrentPlanId {
+		for _, branch := range result.Branches {
+			if branch == lib.CurrentBranch {
+				return branch
+			}
+		}
+	}
+	return result.Branches[0]
+}
+
+func highlightSearchSnippet(snippet string) string {
+	highlighted := searchSnippetMatchRegex.ReplaceAllStringFunc(snippet, func(match string) string {
+		return color.New(color.Bold, term.ColorHiYellow).Sprint(searchSnippetMatchRegex.FindStringSubmatch(match)[1])
+	})
+
+	// drop any unpaired delimiters so they aren't written to the terminal
+	return strings.NewReplacer(shared.PlanSearchMatchStart, "", shared.PlanSearchMatchStop, "").Replace(highlighted)
+}
@@ -499,6 +499,18 @@ func updateModelSettings(args []string, originalSettings *shared.PlanSettings) *
 				} else if topP != nil {
 					settings.ModelPack.ExecStatus.TopP = float32(*topP)
 				}
+
+			case shared.ModelRoleReviewer:
+				reviewer := settings.ModelPack.GetReviewer()
+				reviewer.Role = shared.ModelRoleReviewer
+				if selectedModel != nil {
+					reviewer.BaseModelConfig = selectedModel.BaseModelConfig
+				} else if temperature != nil {
+					reviewer.Temperature = float32(*temperature)
+				} else if topP != nil {
+					reviewer.TopP = float32(*topP)
+				}
+				settings.ModelPack.Reviewer = &reviewer
 			}
 		}
 	} else {
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"plandex/api"
+	"plandex/auth"
+	"plandex/lib"
+	"plandex/term"
+
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/plandex/plandex/shared"
+	"github.com/spf13/cobra"
+)
+
+var shareUser string
+var shareRead bool
+var shareWrite bool
+
+var shareCmd = &cobra.Command{
+	Use:   "share",
+	Short: "Share the current plan with your org or a teammate",
+	Long: `Share the current plan with everyone in your org, or with a single teammate with --user.
+
+Read access lets teammates see the plan's context, conversation, and pending changes. Write access also lets them send prompts, load and remove context, and apply or reject changes. Sharing is read-only unless you pass --write.
+
+Only the plan's owner can change who it's shared with.`,
+	Args: cobra.NoArgs,
+	Run:  share,
+}
+
+var unshareCmd = &cobra.Command{
+	Use:   "unshare",
+	Short: "Stop sharing the current plan with your org or a teammate",
+	Args:  cobra.NoArgs,
+	Run:   unshare,
+}
+
+func init() {
+	RootCmd.AddCommand(shareCmd)
+	RootCmd.AddCommand(unshareCmd)
+
+	shareCmd.Flags().StringVarP(&shareUser, "user", "u", "", "Email of the teammate to share with")
+	shareCmd.Flags().BoolVarP(&shareRead, "read", "r", false, "Share with read access (default)")
+	shareCmd.Flags().BoolVarP(&shareWrite, "write", "w", false, "Share with write access")
+	shareCmd.MarkFlagsMutuallyExclusive("read", "write")
+
+	unshareCmd.Flags().StringVarP(&shareUser, "user", "u", "", "Email of the teammate to stop sharing with")
+}
+
+func share(cmd *cobra.Command, args []string) {
+	auth.MustResolveAuthWithOrg()
+	lib.MustResolveProject()
+
+	if lib.CurrentPlanId == "" {
+		term.OutputNoCurrentPlanErrorAndExit()
+	}
+
+	access := shared.PlanAccessRead
+	if shareWrite {
+		access = shared.PlanAccessWrite
+	}
+
+	term.StartSpinner("")
+	apiErr := api.Client.SharePlan(lib.CurrentPlanId, shared.SharePlanRequest{
+		Email:  shareUser,
+		Access: access,
+	})
+	term.StopSpinner()
+
+	if apiErr != nil {
+		term.OutputErrorAndExit("Error sharing plan: %v", apiErr.Msg)
+	}
+
+	with := "everyone in the org"
+	if shareUser != "" {
+		with = shareUser
+	}
+	fmt.Printf("✅ Shared plan with %s | %s access\n", color.New(color.Bold).Sprint(with), access)
+	fmt.Println()
+
+	printPlanSharing()
+}
+
+func unshare(cmd *cobra.Command, args []string) {
+	auth.MustResolveAuthWithOrg()
+	lib.MustResolveProject()
+
+	if lib.CurrentPlanId == "" {
+		term.OutputNoCurrentPlanErrorAndExit()
+	}
+
+	term.StartSpinner("")
+	apiErr := api.Client.UnsharePlan(lib.CurrentPlanId, shared.UnsharePlanRequest{
+		Email: shareUser,
+	})
+	term.StopSpinner()
+
+	if apiErr != nil {
+		term.OutputErrorAndExit("Error unsharing plan: %v", apiErr.Msg)
+	}
+
+	with := "the org"
+	if shareUser != "" {
+		with = shareUser
+	}
+	fmt.Printf("✅ Stopped sharing plan with %s\n", color.New(color.Bold).Sprint(with))
+	fmt.Println()
+
+	printPlanSharing()
+}
+
+func printPlanSharing() {
+	term.StartSpinner("")
+	res, apiErr := api.Client.ListPlanCollaborators(lib.CurrentPlanId)
+	term.StopSpinner()
+
+	if apiErr != nil {
+		term.OutputErrorAndExit("Error getting plan collaborators: %v", apiErr.Msg)
+	}
+
+	if res.SharedWithOrgAccess == "" && len(res.Collaborators) == 0 {
+		fmt.Println("🔒 Plan isn't shared")
+		fmt.Println()
+		term.PrintCmds("", "share", "share --user")
+		return
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetAutoWrapText(false)
+	table.SetHeader([]string{"Shared With", "Access"})
+
+	if res.SharedWithOrgAccess != "" {
+		table.Append([]string{"🏢 everyone in the org", string(res.SharedWithOrgAccess)})
+	}
+	for _, collaborator := range res.Collaborators {
+		table.Append([]string{fmt.Sprintf("👤 %s <%s>", collaborator.UserName, collaborator.UserEmail), string(collaborator.Access)})
+	}
+
+	table.Render()
+	fmt.Println()
+}
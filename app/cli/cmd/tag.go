@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"plandex/api"
+	"plandex/auth"
+	"plandex/format"
+	"plandex/lib"
+	"plandex/term"
+	"regexp"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/plandex/plandex/shared"
+	"github.com/spf13/cobra"
+)
+
+var tagMessage string
+var tagDelete bool
+var tagForce bool
+
+var tagCmd = &cobra.Command{
+	Use:   "tag [name]",
+	Short: "Tag the current state of the plan as a named checkpoint",
+	Long: `Tag the current state of the plan as a named checkpoint.
+
+Tags show up in 'plandex log', and you can return to one with 'plandex rewind <name>'. Tags are kept when the plan is rewound, and can only be deleted with --delete after confirming.
+
+With no name, lists the plan's tags.`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  tag,
+}
+
+func init() {
+	RootCmd.AddCommand(tagCmd)
+
+	tagCmd.Flags().StringVarP(&tagMessage, "message", "m", "", "Describe the checkpoint")
+	tagCmd.Flags().BoolVarP(&tagDelete, "delete", "d", false, "Delete the tag")
+	tagCmd.Flags().BoolVar(&tagForce, "force", false, "Delete the tag without confirming")
+}
+
+var tagWhitespaceRegex = regexp.MustCompile(`\s+`)
+
+func tag(cmd *cobra.Command, args []string) {
+	auth.MustResolveAuthWithOrg()
+	lib.MustResolveProject()
+
+	if lib.CurrentPlanId == "" {
+		term.OutputNoCurrentPlanErrorAndExit()
+	}
+
+	if len(args) == 0 {
+		if tagDelete {
+			term.OutputErrorAndExit("Pass the name of the tag to delete")
+		}
+		listPlanTags()
+		return
+	}
+
+	name := tagWhitespaceRegex.ReplaceAllString(strings.TrimSpace(args[0]), "-")
+
+	if tagDelete {
+		if !tagForce {
+			res, err := term.ConfirmYesNo("Tag %s is protected. Delete it anyway?", name)
+
+			if err != nil {
+				term.OutputErrorAndExit("Error getting user input: %v", err)
+			}
+
+			if !res {
+				return
+			}
+		}
+
+		term.StartSpinner("")
+		apiErr := api.Client.DeletePlanTag(lib.CurrentPlanId, lib.CurrentBranch, name, shared.DeletePlanTagRequest{Force: true})
+		term.StopSpinner()
+
+		if apiErr != nil {
+			term.OutputErrorAndExit("Error deleting tag: %v", apiErr.Msg)
+		}
+
+		fmt.Printf("✅ Deleted tag %s\n", color.New(color.Bold, term.ColorHiYellow).Sprint(name))
+		return
+	}
+
+	term.StartSpinner("")
+	res, apiErr := api.Client.CreatePlanTag(lib.CurrentPlanId, lib.CurrentBranch, shared.CreatePlanTagRequest{
+		Name:    name,
+		Message: tagMessage,
+	})
+	term.StopSpinner()
+
+	if apiErr != nil {
+		term.OutputErrorAndExit("Error creating tag: %v", apiErr.Msg)
+	}
+
+	fmt.Printf("🏷️  Tagged %s as %s\n", res.Sha, color.New(color.Bold, term.ColorHiYellow).Sprint(res.Name))
+	fmt.Println()
+	term.PrintCmds("", "log --tags", "rewind")
+}
+
+func listPlanTags() {
+	term.StartSpinner("")
+	tags, apiErr := api.Client.ListPlanTags(lib.CurrentPlanId, lib.CurrentBranch)
+	term.StopSpinner()
+
+	if apiErr != nil {
+		term.OutputErrorAndExit("Error getting tags: %v", apiErr.Msg)
+	}
+
+	if len(tags) == 0 {
+		fmt.Println("🤷‍♂️ No tags")
+		fmt.Println()
+		term.PrintCmds("", "tag")
+		return
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetAutoWrapText(false)
+	table.SetHeader([]string{"Tag", "Sha", "Created", "On Branch", "Message"})
+
+	for _, t := range tags {
+		onBranch := ""
+		if t.OnBranch {
+			onBranch = "✓"
+		}
+
+		message := t.Message
+		if message == t.Name {
+			message = ""
+		}
+
+		table.Append([]string{
+			color.New(color.Bold, term.ColorHiYellow).Sprint(t.Name),
+			t.Sha,
+			format.Time(t.CreatedAt),
+			onBranch,
+			message,
+		})
+	}
+
+	table.Render()
+	fmt.Println()
+	term.PrintCmds("", "rewind", "tag")
+}
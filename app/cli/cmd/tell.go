@@ -22,6 +22,9 @@ var tellPromptFile string
 var tellBg bool
 var tellStop bool
 var tellNoBuild bool
+var tellAutoContext bool
+var tellAutoContextMax int
+var tellComparePacks string
 
 // tellCmd represents the prompt command
 var tellCmd = &cobra.Command{
@@ -40,6 +43,9 @@ func init() {
 	tellCmd.Flags().BoolVarP(&tellStop, "stop", "s", false, "Stop after a single reply")
 	tellCmd.Flags().BoolVarP(&tellNoBuild, "no-build", "n", false, "Don't build files")
 	tellCmd.Flags().BoolVar(&tellBg, "bg", false, "Execute autonomously in the background")
+	tellCmd.Flags().BoolVar(&tellAutoContext, "auto-context", false, "Load the project files most relevant to the prompt before sending it")
+	tellCmd.Flags().IntVar(&tellAutoContextMax, "auto-context-max", 8, "Max number of files to load with --auto-context")
+	tellCmd.Flags().StringVar(&tellComparePacks, "compare-packs", "", "Comma-separated model packs to run the prompt with, each on a new branch")
 }
 
 func doTell(cmd *cobra.Command, args []string) {
@@ -72,14 +78,25 @@ func doTell(cmd *cobra.Command, args []string) {
 		return
 	}
 
-	plan_exec.TellPlan(plan_exec.ExecParams{
+	if tellAutoContext {
+		lib.MustAutoLoadContext(prompt, tellAutoContextMax)
+	}
+
+	params := plan_exec.ExecParams{
 		CurrentPlanId: lib.CurrentPlanId,
 		CurrentBranch: lib.CurrentBranch,
 		ApiKeys:       apiKeys,
 		CheckOutdatedContext: func(maybeContexts []*shared.Context) (bool, bool) {
 			return lib.MustCheckOutdatedContext(false, maybeContexts)
 		},
-	}, prompt, tellBg, tellStop, tellNoBuild, false)
+	}
+
+	if tellComparePacks != "" {
+		plan_exec.TellComparePacks(params, prompt, strings.Split(tellComparePacks, ","), tellBg, tellStop, tellNoBuild)
+		return
+	}
+
+	plan_exec.TellPlan(params, prompt, tellBg, tellStop, tellNoBuild, false, false)
 }
 
 func prepareEditorCommand(editor string, filename string) *exec.Cmd {
@@ -98,6 +115,11 @@ func getEditorInstructions(editor string) string {
 }
 
 func getEditorPrompt() string {
+	return getEditorPromptWithText("")
+}
+
+// getEditorPromptWithText opens the editor with text below the instructions for the user to edit
+func getEditorPromptWithText(text string) string {
 	editor := os.Getenv("EDITOR")
 	if editor == "" {
 		editor = os.Getenv("VISUAL")
@@ -113,7 +135,7 @@ func getEditorPrompt() string {
 
 	instructions := getEditorInstructions(editor)
 	filename := tempFile.Name()
-	err = os.WriteFile(filename, []byte(instructions), 0644)
+	err = os.WriteFile(filename, []byte(instructions+text), 0644)
 	if err != nil {
 		term.OutputErrorAndExit("Failed to write instructions to temporary file: %v", err)
 	}
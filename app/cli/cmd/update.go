@@ -26,6 +26,9 @@ func update(cmd *cobra.Command, args []string) {
 	auth.MustResolveAuthWithOrg()
 	lib.MustResolveProject()
 
+	// context from collaborators is only refreshed once confirmed here, never by the automatic check before tell or build
+	lib.MustConfirmUntrustedContexts()
+
 	term.StartSpinner("")
 	outdated, err := lib.CheckOutdatedContext(nil)
 
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"plandex/auth"
+	"plandex/lib"
+	"plandex/term"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var watchDebounce time.Duration
+var watchStatus bool
+var watchApply bool
+var watchAutoConfirm bool
+var watchBlockOnReview bool
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Keep context in sync with the project as files change",
+	Long: `Keep context in sync with the project as files change, until interrupted.
+
+Changes to loaded files and directory trees are pushed as context updates. New files are loaded when they match a directory or glob that loaded context came from, like 'plandex load src -r' or 'plandex load "src/**/*.go"'.
+
+With --status, status changes for the current branch are shown as well. With --apply, you'll be asked to apply pending changes whenever the plan finishes -- add --yes to apply without asking. Applying is skipped while a requested review is still open, and with --block-on-review, while the latest review has high-severity issues.`,
+	Args: cobra.NoArgs,
+	Run:  watch,
+}
+
+func init() {
+	RootCmd.AddCommand(watchCmd)
+
+	watchCmd.Flags().DurationVarP(&watchDebounce, "debounce", "d", 500*time.Millisecond, "How long to wait after the last file change before updating context")
+	watchCmd.Flags().BoolVarP(&watchStatus, "status", "s", false, "Show status changes for the current branch")
+	watchCmd.Flags().BoolVarP(&watchApply, "apply", "a", false, "Apply pending changes whenever the plan finishes (implies --status)")
+	watchCmd.Flags().BoolVarP(&watchAutoConfirm, "yes", "y", false, "With --apply, apply without confirmation")
+	watchCmd.Flags().BoolVar(&watchBlockOnReview, "block-on-review", false, "With --apply, skip applying while the latest review has high-severity issues")
+}
+
+func watch(cmd *cobra.Command, args []string) {
+	auth.MustResolveAuthWithOrg()
+	lib.MustResolveProject()
+
+	if lib.CurrentPlanId == "" {
+		term.OutputNoCurrentPlanErrorAndExit()
+	}
+
+	if watchDebounce <= 0 {
+		term.OutputErrorAndExit("--debounce must be greater than 0")
+	}
+
+	if (watchAutoConfirm || watchBlockOnReview) && !watchApply {
+		term.OutputErrorAndExit("--yes and --block-on-review can only be used with --apply")
+	}
+
+	lib.Watch(lib.WatchParams{
+		Debounce:      watchDebounce,
+		Status:        watchStatus,
+		Apply:         watchApply,
+		AutoConfirm:   watchAutoConfirm,
+		BlockOnReview: watchBlockOnReview,
+	})
+}
@@ -0,0 +1,210 @@
+package context_index
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+
+	"github.com/plandex/plandex/shared"
+)
+
+// the index lives in the project's plandex dir and is refreshed from file mtimes before each search
+const indexFile = "index.json"
+const indexVersion = 1
+
+const maxIndexedFileSize = 1024 * 1024 // 1MB
+
+type indexedFile struct {
+	ModTime int64          `json:"modTime"`
+	Size    int64          `json:"size"`
+	Length  int            `json:"length"`
+	Terms   map[string]int `json:"terms"`
+	Symbols []string       `json:"symbols"`
+}
+
+type Index struct {
+	Version int                     `json:"version"`
+	Files   map[string]*indexedFile `json:"files"`
+
+	plandexDir string
+}
+
+func Load(plandexDir string) (*Index, error) {
+	idx := &Index{
+		Version:    indexVersion,
+		Files:      map[string]*indexedFile{},
+		plandexDir: plandexDir,
+	}
+
+	bytes, err := os.ReadFile(filepath.Join(plandexDir, indexFile))
+
+	if os.IsNotExist(err) {
+		return idx, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error reading %s: %v", indexFile, err)
+	}
+
+	var loaded Index
+	err = json.Unmarshal(bytes, &loaded)
+
+	// a corrupt or outdated index is just rebuilt
+	if err != nil || loaded.Version != indexVersion || loaded.Files == nil {
+		return idx, nil
+	}
+
+	loaded.plandexDir = plandexDir
+	return &loaded, nil
+}
+
+func (idx *Index) Save() error {
+	bytes, err := json.Marshal(idx)
+	if err != nil {
+		return fmt.Errorf("error marshalling index: %v", err)
+	}
+
+	err = os.WriteFile(filepath.Join(idx.plandexDir, indexFile), bytes, 0644)
+	if err != nil {
+		return fmt.Errorf("error writing %s: %v", indexFile, err)
+	}
+
+	return nil
+}
+
+// Refresh re-indexes any of the given paths (relative to rootDir, the project root) that were added or modified since the last refresh, and drops paths that no longer exist. Directories, binary files, images, and files over 1MB aren't indexed, and are dropped if they were indexed before.
+func (idx *Index) Refresh(rootDir string, paths map[string]bool) (int, error) {
+	numUpdated := 0
+
+	for path := range idx.Files {
+		if !paths[path] {
+			delete(idx.Files, path)
+			numUpdated++
+		}
+	}
+
+	pathsCh := make(chan string)
+	errCh := make(chan error, 1)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for i := 0; i < runtime.NumCPU(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range pathsCh {
+				updated, err := idx.refreshFile(rootDir, path, &mu)
+				if err != nil {
+					select {
+					case errCh <- err:
+					default:
+					}
+					continue
+				}
+				if updated {
+					mu.Lock()
+					numUpdated++
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for path := range paths {
+		if shared.IsImageFile(path) {
+			if idx.Files[path] != nil {
+				delete(idx.Files, path)
+				numUpdated++
+			}
+			continue
+		}
+		pathsCh <- path
+	}
+	close(pathsCh)
+	wg.Wait()
+
+	select {
+	case err := <-errCh:
+		return numUpdated, err
+	default:
+	}
+
+	return numUpdated, nil
+}
+
+func (idx *Index) refreshFile(rootDir, path string, mu *sync.Mutex) (bool, error) {
+	// drop is called for paths that can't be indexed, returning whether they were indexed before
+	drop := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		if idx.Files[path] == nil {
+			return false
+		}
+		delete(idx.Files, path)
+		return true
+	}
+
+	info, err := os.Stat(filepath.Join(rootDir, path))
+	if os.IsNotExist(err) {
+		return drop(), nil
+	} else if err != nil {
+		return false, fmt.Errorf("error getting file info for %s: %v", path, err)
+	}
+
+	if info.IsDir() || info.Size() > maxIndexedFileSize {
+		return drop(), nil
+	}
+
+	mu.Lock()
+	existing := idx.Files[path]
+	mu.Unlock()
+
+	if existing != nil && existing.ModTime == info.ModTime().UnixNano() && existing.Size == info.Size() {
+		return false, nil
+	}
+
+	content, err := os.ReadFile(filepath.Join(rootDir, path))
+	if err != nil {
+		return false, fmt.Errorf("error reading %s: %v", path, err)
+	}
+
+	if isBinary(content) {
+		return drop(), nil
+	}
+
+	text := string(content)
+	if shared.IsNotebookFile(path) {
+		converted, err := shared.NotebookToText(text)
+		if err == nil {
+			text = converted
+		}
+	}
+
+	terms := tokenize(text)
+	termFreqs := make(map[string]int, len(terms)/4)
+	for _, term := range terms {
+		termFreqs[term]++
+	}
+
+	mu.Lock()
+	idx.Files[path] = &indexedFile{
+		ModTime: info.ModTime().UnixNano(),
+		Size:    info.Size(),
+		Length:  len(terms),
+		Terms:   termFreqs,
+		Symbols: extractSymbols(text),
+	}
+	mu.Unlock()
+
+	return true, nil
+}
+
+func isBinary(content []byte) bool {
+	sample := content
+	if len(sample) > 8000 {
+		sample = sample[:8000]
+	}
+	return bytes.IndexByte(sample, 0) != -1
+}
@@ -0,0 +1,224 @@
+package context_index
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := map[string][]string{
+		"parseHTTPRequest":        {"parsehttprequest", "parse", "http", "request"},
+		"load_context_file":       {"load", "context", "file"},
+		"the user's 2 OAuth2 ids": {"user", "oauth2", "auth", "ids"},
+		"func (x) return nil":     nil,
+	}
+
+	for text, expected := range tests {
+		got := tokenize(text)
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("tokenize(%q) = %v, expected %v", text, got, expected)
+		}
+	}
+}
+
+func TestExtractSymbols(t *testing.T) {
+	text := `package lib
+
+func LoadContext() {}
+func (idx *Index) Search(query string) {}
+type Result struct {}
+export const DEFAULT_LIMIT = 10
+  def parse_args(self):
+class TokenCounter:
+`
+
+	got := extractSymbols(text)
+	expected := []string{"LoadContext", "Search", "Result", "parse_args", "TokenCounter", "DEFAULT_LIMIT"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("extractSymbols = %v, expected %v", got, expected)
+	}
+}
+
+func newTestIndex(files map[string]string) *Index {
+	idx := &Index{Version: indexVersion, Files: map[string]*indexedFile{}}
+	for path, text := range files {
+		terms := tokenize(text)
+		termFreqs := map[string]int{}
+		for _, term := range terms {
+			termFreqs[term]++
+		}
+		idx.Files[path] = &indexedFile{Length: len(terms), Terms: termFreqs, Symbols: extractSymbols(text)}
+	}
+	return idx
+}
+
+func resultPaths(results []*Result) []string {
+	var paths []string
+	for _, result := range results {
+		paths = append(paths, result.Path)
+	}
+	return paths
+}
+
+func TestSearchRanking(t *testing.T) {
+	idx := newTestIndex(map[string]string{
+		"lib/locks.go":     "func AcquireLock() {}\n// waits for the repo lock before writing",
+		"lib/apply.go":     "func ApplyPlan() {}\n// takes the lock, then writes files, mentions lock and lock again",
+		"docs/readme.md":   "Plandex helps you build features across many files",
+		"lib/lock_util.go": "func retry() {}",
+	})
+
+	results := idx.Search("acquire the lock", 10)
+	paths := resultPaths(results)
+
+	// the declared symbol outweighs repeated mentions in the body
+	if len(paths) < 3 || paths[0] != "lib/locks.go" {
+		t.Fatalf("expected lib/locks.go to rank first, got %v", paths)
+	}
+	if len(results[0].MatchedSymbols) == 0 || results[0].MatchedSymbols[0] != "AcquireLock" {
+		t.Errorf("expected AcquireLock to be a matched symbol, got %v", results[0].MatchedSymbols)
+	}
+
+	var pathMatch *Result
+	for _, result := range results {
+		if result.Path == "lib/lock_util.go" {
+			pathMatch = result
+		}
+		if result.Path == "docs/readme.md" {
+			t.Errorf("expected file without matching terms not to be returned")
+		}
+	}
+	if pathMatch == nil || !pathMatch.PathMatch {
+		t.Errorf("expected lib/lock_util.go to match on its path")
+	}
+
+	for i := 1; i < len(results); i++ {
+		if results[i].Score > results[i-1].Score {
+			t.Errorf("expected results to be sorted by score: %v", paths)
+		}
+	}
+
+	if got := idx.Search("acquire the lock", 1); len(got) != 1 {
+		t.Errorf("expected limit to be applied, got %d results", len(got))
+	}
+
+	if got := idx.Search("the and of", 10); got != nil {
+		t.Errorf("expected no results for a query of stop words, got %v", resultPaths(got))
+	}
+}
+
+func TestSearchRareTermsScoreHigher(t *testing.T) {
+	idx := newTestIndex(map[string]string{
+		"a.go": "config value",
+		"b.go": "config tokenizer",
+		"c.go": "config",
+		"d.go": "config",
+	})
+
+	results := idx.Search("config tokenizer value", 10)
+	if len(results) != 4 {
+		t.Fatalf("expected 4 results, got %v", resultPaths(results))
+	}
+
+	// tokenizer and value are each in only one file, so a.go and b.go tie and are sorted by path
+	if results[0].Path != "a.go" || results[1].Path != "b.go" {
+		t.Errorf("expected files with rare terms first, got %v", resultPaths(results))
+	}
+	if results[0].Score <= results[2].Score*1.5 {
+		t.Errorf("expected rare terms to add more than the common term: %v vs %v", results[0].Score, results[2].Score)
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	root := t.TempDir()
+
+	write := func(path, content string) {
+		err := os.MkdirAll(filepath.Dir(filepath.Join(root, path)), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(filepath.Join(root, path), []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("src/main.go", "func main() {}")
+	write("src/data.bin", "binary\x00data")
+	write("logo.png", "not really a png")
+
+	idx := &Index{Version: indexVersion, Files: map[string]*indexedFile{}, plandexDir: t.TempDir()}
+	paths := map[string]bool{"src/main.go": true, "src/data.bin": true, "logo.png": true}
+
+	numUpdated, err := idx.Refresh(root, paths)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if numUpdated != 1 || idx.Files["src/main.go"] == nil || len(idx.Files) != 1 {
+		t.Fatalf("expected only src/main.go to be indexed, got %d updated, %d files", numUpdated, len(idx.Files))
+	}
+
+	// unchanged files aren't re-indexed
+	numUpdated, err = idx.Refresh(root, paths)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if numUpdated != 0 {
+		t.Errorf("expected no updates, got %d", numUpdated)
+	}
+
+	// files that become binary or too large are dropped
+	write("src/main.go", "now\x00binary")
+	write("src/big.go", "func big() {}")
+	paths["src/big.go"] = true
+
+	_, err = idx.Refresh(root, paths)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if idx.Files["src/main.go"] != nil {
+		t.Errorf("expected file that became binary to be dropped")
+	}
+	if idx.Files["src/big.go"] == nil {
+		t.Fatalf("expected src/big.go to be indexed")
+	}
+
+	write("src/big.go", strings.Repeat("x", maxIndexedFileSize+1))
+	numUpdated, err = idx.Refresh(root, paths)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if numUpdated != 1 || idx.Files["src/big.go"] != nil {
+		t.Errorf("expected file over the size limit to be dropped, got %d updated", numUpdated)
+	}
+
+	// paths that are no longer in the project are dropped
+	write("src/main.go", "func main() {}")
+	_, err = idx.Refresh(root, paths)
+	if err != nil {
+		t.Fatal(err)
+	}
+	delete(paths, "src/main.go")
+	numUpdated, err = idx.Refresh(root, paths)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if numUpdated != 1 || len(idx.Files) != 0 {
+		t.Errorf("expected removed paths to be dropped, got %d updated, %d files", numUpdated, len(idx.Files))
+	}
+
+	err = idx.Save()
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(idx.plandexDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Version != indexVersion || len(loaded.Files) != 0 {
+		t.Errorf("expected saved index to load, got version %d with %d files", loaded.Version, len(loaded.Files))
+	}
+}
@@ -0,0 +1,179 @@
+package context_index
+
+import (
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// standard BM25 parameters
+const bm25K1 = 1.2
+const bm25B = 0.75
+
+// matches in declared symbol names and in the file path count for more than matches in the body
+const symbolWeight = 3.0
+const pathWeight = 2.0
+
+type Result struct {
+	Path           string
+	Score          float64
+	MatchedTerms   []string
+	MatchedSymbols []string
+	PathMatch      bool
+}
+
+// Search ranks indexed files against the query with BM25, returning up to limit results with a positive score
+func (idx *Index) Search(query string, limit int) []*Result {
+	queryTerms := uniqueTerms(tokenize(query))
+	if len(queryTerms) == 0 || len(idx.Files) == 0 {
+		return nil
+	}
+
+	numDocs := float64(len(idx.Files))
+	totalLength := 0
+	for _, file := range idx.Files {
+		totalLength += file.Length
+	}
+	avgLength := math.Max(float64(totalLength)/numDocs, 1)
+
+	type fileFields struct {
+		pathTerms   map[string]int
+		symbolTerms map[string][]string
+	}
+	fieldsByPath := make(map[string]*fileFields, len(idx.Files))
+
+	docFreqs := map[string]int{}
+	for path, file := range idx.Files {
+		fields := &fileFields{
+			pathTerms:   map[string]int{},
+			symbolTerms: map[string][]string{},
+		}
+		for _, term := range tokenize(filepath.ToSlash(path)) {
+			fields.pathTerms[term]++
+		}
+		for _, symbol := range file.Symbols {
+			for _, term := range uniqueTerms(tokenize(symbol)) {
+				fields.symbolTerms[term] = append(fields.symbolTerms[term], symbol)
+			}
+		}
+		fieldsByPath[path] = fields
+
+		for _, term := range queryTerms {
+			if file.Terms[term] > 0 || fields.pathTerms[term] > 0 || len(fields.symbolTerms[term]) > 0 {
+				docFreqs[term]++
+			}
+		}
+	}
+
+	var results []*Result
+	for path, file := range idx.Files {
+		fields := fieldsByPath[path]
+		result := &Result{Path: path}
+
+		type termScore struct {
+			term  string
+			score float64
+		}
+		var termScores []termScore
+		seenSymbols := map[string]bool{}
+
+		for _, term := range queryTerms {
+			df := docFreqs[term]
+			if df == 0 {
+				continue
+			}
+
+			symbols := fields.symbolTerms[term]
+			tf := float64(file.Terms[term]) +
+				symbolWeight*float64(len(symbols)) +
+				pathWeight*float64(fields.pathTerms[term])
+
+			if tf == 0 {
+				continue
+			}
+
+			idf := math.Log(1 + (numDocs-float64(df)+0.5)/(float64(df)+0.5))
+			norm := bm25K1 * (1 - bm25B + bm25B*float64(file.Length)/avgLength)
+			score := idf * (tf * (bm25K1 + 1)) / (tf + norm)
+
+			result.Score += score
+			termScores = append(termScores, termScore{term, score})
+
+			if fields.pathTerms[term] > 0 {
+				result.PathMatch = true
+			}
+			for _, symbol := range symbols {
+				if !seenSymbols[symbol] {
+					seenSymbols[symbol] = true
+					result.MatchedSymbols = append(result.MatchedSymbols, symbol)
+				}
+			}
+		}
+
+		if result.Score <= 0 {
+			continue
+		}
+
+		sort.Slice(termScores, func(i, j int) bool {
+			return termScores[i].score > termScores[j].score
+		})
+		for _, ts := range termScores {
+			result.MatchedTerms = append(result.MatchedTerms, ts.term)
+		}
+
+		results = append(results, result)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score == results[j].Score {
+			return results[i].Path < results[j].Path
+		}
+		return results[i].Score > results[j].Score
+	})
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results
+}
+
+// Reason summarizes why a result matched, for display
+func (r *Result) Reason() string {
+	var parts []string
+
+	if len(r.MatchedSymbols) > 0 {
+		symbols := r.MatchedSymbols
+		if len(symbols) > 3 {
+			symbols = symbols[:3]
+		}
+		parts = append(parts, "symbols: "+strings.Join(symbols, ", "))
+	}
+
+	if r.PathMatch {
+		parts = append(parts, "path match")
+	}
+
+	if len(r.MatchedTerms) > 0 {
+		terms := r.MatchedTerms
+		if len(terms) > 5 {
+			terms = terms[:5]
+		}
+		parts = append(parts, "terms: "+strings.Join(terms, ", "))
+	}
+
+	return strings.Join(parts, " | ")
+}
+
+func uniqueTerms(terms []string) []string {
+	seen := map[string]bool{}
+	var res []string
+	for _, term := range terms {
+		if !seen[term] {
+			seen[term] = true
+			res = append(res, term)
+		}
+	}
+	return res
+}
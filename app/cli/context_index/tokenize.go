@@ -0,0 +1,122 @@
+package context_index
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+const minTermLen = 2
+const maxTermLen = 48
+
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "that": true, "this": true, "with": true, "from": true,
+	"are": true, "was": true, "but": true, "not": true, "you": true, "all": true, "can": true,
+	"has": true, "have": true, "its": true, "into": true, "use": true, "any": true, "also": true,
+	"should": true, "would": true, "could": true, "when": true, "then": true, "there": true,
+	"what": true, "which": true, "will": true, "some": true, "make": true, "add": true, "new": true,
+	"please": true, "need": true, "want": true, "like": true, "so": true, "to": true, "of": true,
+	"in": true, "on": true, "it": true, "is": true, "be": true, "or": true, "an": true, "as": true,
+	"at": true, "by": true, "we": true, "do": true, "if": true, "my": true,
+	// common keywords that show up in nearly every file
+	"func": true, "return": true, "else": true, "var": true, "let": true, "const": true,
+	"import": true, "package": true, "nil": true, "null": true, "true": true, "false": true,
+	"self": true, "def": true, "end": true, "err": true, "string": true, "int": true,
+}
+
+// identifiers declared at the start of a line by common languages: functions, methods, classes, types, etc.
+var symbolRegexes = []*regexp.Regexp{
+	regexp.MustCompile(`(?m)^\s*(?:export\s+)?(?:default\s+)?(?:pub(?:\([a-z]+\))?\s+)?(?:public\s+|private\s+|protected\s+)?(?:static\s+)?(?:abstract\s+)?(?:async\s+)?(?:func|function|def|class|interface|type|struct|enum|trait|impl|fn|module|record|object)\s+(?:\([^)]*\)\s*)?([A-Za-z_$][A-Za-z0-9_$]*)`),
+	regexp.MustCompile(`(?m)^\s*(?:export\s+)?(?:const|let|var)\s+([A-Za-z_$][A-Za-z0-9_$]*)\s*(?::[^=]+)?=`),
+}
+
+// tokenize splits text into lowercased terms. Identifiers are split on camelCase and snake_case boundaries, and compound identifiers are also kept whole so exact matches score higher.
+func tokenize(text string) []string {
+	var terms []string
+
+	for _, word := range splitWords(text) {
+		parts := splitIdentifier(word)
+
+		if len(parts) > 1 {
+			if term, ok := normalizeTerm(strings.Join(parts, "")); ok {
+				terms = append(terms, term)
+			}
+		}
+
+		for _, part := range parts {
+			if term, ok := normalizeTerm(part); ok {
+				terms = append(terms, term)
+			}
+		}
+	}
+
+	return terms
+}
+
+func extractSymbols(text string) []string {
+	seen := map[string]bool{}
+	var symbols []string
+
+	for _, re := range symbolRegexes {
+		for _, match := range re.FindAllStringSubmatch(text, -1) {
+			symbol := match[1]
+			if len(symbol) < minTermLen || seen[symbol] {
+				continue
+			}
+			seen[symbol] = true
+			symbols = append(symbols, symbol)
+		}
+	}
+
+	return symbols
+}
+
+func normalizeTerm(s string) (string, bool) {
+	s = strings.ToLower(s)
+	if len(s) < minTermLen || len(s) > maxTermLen || stopWords[s] {
+		return "", false
+	}
+
+	// skip pure numbers
+	isNum := true
+	for _, r := range s {
+		if !unicode.IsDigit(r) {
+			isNum = false
+			break
+		}
+	}
+	if isNum {
+		return "", false
+	}
+
+	return s, true
+}
+
+// splitWords splits on anything that can't be part of an identifier
+func splitWords(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !(unicode.IsLetter(r) || unicode.IsDigit(r))
+	})
+}
+
+// splitIdentifier splits camelCase, PascalCase and acronyms, e.g. "parseHTTPRequest" => "parse", "HTTP", "Request". Underscores are already removed by splitWords.
+func splitIdentifier(word string) []string {
+	runes := []rune(word)
+	var parts []string
+	start := 0
+
+	for i := 1; i < len(runes); i++ {
+		prev, cur := runes[i-1], runes[i]
+
+		boundary := (unicode.IsLower(prev) && unicode.IsUpper(cur)) ||
+			(unicode.IsDigit(prev) != unicode.IsDigit(cur)) ||
+			(unicode.IsUpper(prev) && unicode.IsUpper(cur) && i+1 < len(runes) && unicode.IsLower(runes[i+1]))
+
+		if boundary {
+			parts = append(parts, string(runes[start:i]))
+			start = i
+		}
+	}
+
+	return append(parts, string(runes[start:]))
+}
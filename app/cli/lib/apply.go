@@ -6,13 +6,14 @@ import (
 	"path/filepath"
 	"plandex/api"
 	"plandex/fs"
+	"plandex/secrets"
 	"plandex/term"
 	"strings"
 
 	"github.com/plandex/plandex/shared"
 )
 
-func MustApplyPlan(planId, branch string, autoConfirm bool) {
+func MustApplyPlan(planId, branch string, autoConfirm, blockOnReview bool) {
 	term.StartSpinner("")
 
 	currentPlanState, apiErr := api.Client.GetCurrentPlanState(planId, branch)
@@ -81,6 +82,44 @@ func MustApplyPlan(planId, branch string, autoConfirm bool) {
 		return
 	}
 
+	if currentPlanState.Review != nil && currentPlanState.Review.NumHighSeverity() > 0 {
+		term.StopSpinner()
+
+		numHigh := currentPlanState.Review.NumHighSeverity()
+		suffix := "s"
+		if numHigh == 1 {
+			suffix = ""
+		}
+
+		if blockOnReview {
+			term.OutputErrorAndExit("The latest review found %d high-severity issue%s. Fix or reject the affected changes, then review again.", numHigh, suffix)
+		}
+
+		fmt.Printf("🔴 The latest review found %d high-severity issue%s\n", numHigh, suffix)
+		term.PrintCmds("", "review --show")
+		fmt.Println()
+		term.ResumeSpinner()
+	}
+
+	if currentPlanState.ReviewState != nil {
+		pending := currentPlanState.ReviewState.PendingRequests(currentPlanState.PlanResult)
+
+		if len(pending) > 0 {
+			term.StopSpinner()
+
+			var reviewers []string
+			for _, request := range pending {
+				reviewers = append(reviewers, request.ReviewerEmail)
+			}
+
+			fmt.Printf("👀 Still waiting on approval from %s\n", strings.Join(reviewers, ", "))
+			fmt.Println("Applying before then requires permission to apply without review")
+			term.PrintCmds("", "reviews")
+			fmt.Println()
+			term.ResumeSpinner()
+		}
+	}
+
 	if !autoConfirm {
 		term.StopSpinner()
 		numToApply := len(toApply)
@@ -119,6 +158,13 @@ func MustApplyPlan(planId, branch string, autoConfirm bool) {
 		openAIBase = os.Getenv("OPENAI_ENDPOINT")
 	}
 
+	// secrets that were redacted from context are restored wherever their placeholders appear in the results
+	redactions, err := secrets.LoadRedactions(fs.HomePlandexDir)
+	if err != nil {
+		onErr("failed to load redacted secrets: %v", err)
+		return
+	}
+
 	commitSummary, apiErr = api.Client.ApplyPlan(planId, branch, shared.ApplyPlanRequest{
 		ApiKeys:     apiKeys,
 		OpenAIBase:  openAIBase,
@@ -130,12 +176,183 @@ func MustApplyPlan(planId, branch string, autoConfirm bool) {
 		return
 	}
 
+	updatedFiles, err := writePlanFiles(toApply, redactions)
+	if err != nil {
+		onErr("%v", err)
+		return
+	}
+
+	term.StopSpinner()
+
+	if len(updatedFiles) == 0 {
+		fmt.Println("✅ Applied changes, but no files were updated")
+		return
+	} else {
+		if isRepo {
+			fmt.Println("✏️  Plandex can commit these updates with an automatically generated message.")
+			fmt.Println()
+			fmt.Println("ℹ️  Only the files that Plandex is updating will be included the commit. Any other changes, staged or unstaged, will remain exactly as they are.")
+			fmt.Println()
+
+			confirmed, err := term.ConfirmYesNo("Commit Plandex updates now?")
+
+			if err != nil {
+				onErr("failed to get confirmation user input: %s", err)
+			}
+
+			if confirmed {
+				// Commit the changes
+				msg := currentPlanState.PendingChangesSummaryForApply(commitSummary)
+
+				// log.Println("Committing changes with message:")
+				// log.Println(msg)
+
+				// spew.Dump(currentPlanState)
+
+				err := GitAddAndCommitPaths(fs.ProjectRoot, msg, updatedFiles, true)
+				if err != nil {
+					onGitErr("Failed to commit changes:", err.Error())
+				}
+			}
+		}
+
+		suffix := ""
+		if len(updatedFiles) > 1 {
+			suffix = "s"
+		}
+		fmt.Printf("✅ Applied changes, %d file%s updated\n", len(updatedFiles), suffix)
+	}
+
+}
+
+// ApplyPlanUnattended applies pending changes for `plandex watch --apply`. Unlike MustApplyPlan, it returns errors rather than exiting, and it skips applying (with a message) while builds are pending, context is outdated, or a requested review is still open. Changes are only applied without confirmation if autoConfirm is set. apiKeys should be verified up front since a missing key would otherwise exit.
+func ApplyPlanUnattended(planId, branch string, apiKeys map[string]string, autoConfirm, blockOnReview bool) error {
+	currentPlanState, apiErr := api.Client.GetCurrentPlanState(planId, branch)
+	if apiErr != nil {
+		return fmt.Errorf("error getting current plan state: %v", apiErr.Msg)
+	}
+
+	if currentPlanState.HasPendingBuilds() {
+		fmt.Println("⏭️  Skipping apply -- this plan has changes that still need to be built")
+		term.PrintCmds("", "build")
+		return nil
+	}
+
+	outdatedRes, err := CheckOutdatedContext(nil)
+	if err != nil {
+		return fmt.Errorf("error checking context: %v", err)
+	}
+
+	if len(outdatedRes.UpdatedContexts) > 0 || len(outdatedRes.RemovedContexts) > 0 {
+		fmt.Println("⏭️  Skipping apply -- context has changed since the plan ran")
+		term.PrintCmds("", "update", "apply")
+		return nil
+	}
+
+	toApply := currentPlanState.CurrentPlanFiles.Files
+
+	if len(toApply) == 0 {
+		fmt.Println("🤷‍♂️ No changes to apply")
+		return nil
+	}
+
+	if currentPlanState.Review != nil && currentPlanState.Review.NumHighSeverity() > 0 {
+		numHigh := currentPlanState.Review.NumHighSeverity()
+		suffix := "s"
+		if numHigh == 1 {
+			suffix = ""
+		}
+
+		if blockOnReview {
+			fmt.Printf("⏭️  Skipping apply -- the latest review found %d high-severity issue%s\n", numHigh, suffix)
+			term.PrintCmds("", "review --show")
+			return nil
+		}
+
+		fmt.Printf("🔴 The latest review found %d high-severity issue%s\n", numHigh, suffix)
+	}
+
+	if currentPlanState.ReviewState != nil {
+		pending := currentPlanState.ReviewState.PendingRequests(currentPlanState.PlanResult)
+
+		if len(pending) > 0 {
+			var reviewers []string
+			for _, request := range pending {
+				reviewers = append(reviewers, request.ReviewerEmail)
+			}
+
+			fmt.Printf("⏭️  Skipping apply -- still waiting on approval from %s\n", strings.Join(reviewers, ", "))
+			term.PrintCmds("", "reviews")
+			return nil
+		}
+	}
+
+	if !autoConfirm {
+		numToApply := len(toApply)
+		suffix := ""
+		if numToApply > 1 {
+			suffix = "s"
+		}
+		shouldContinue, err := term.ConfirmYesNo("Apply changes to %d file%s?", numToApply, suffix)
+
+		if err != nil {
+			return fmt.Errorf("failed to get confirmation user input: %s", err)
+		}
+
+		if !shouldContinue {
+			fmt.Println("Apply plan canceled")
+			return nil
+		}
+	}
+
+	openAIBase := os.Getenv("OPENAI_API_BASE")
+	if openAIBase == "" {
+		openAIBase = os.Getenv("OPENAI_ENDPOINT")
+	}
+
+	redactions, err := secrets.LoadRedactions(fs.HomePlandexDir)
+	if err != nil {
+		return fmt.Errorf("failed to load redacted secrets: %v", err)
+	}
+
+	_, apiErr = api.Client.ApplyPlan(planId, branch, shared.ApplyPlanRequest{
+		ApiKeys:     apiKeys,
+		OpenAIBase:  openAIBase,
+		OpenAIOrgId: os.Getenv("OPENAI_ORG_ID"),
+	})
+
+	if apiErr != nil {
+		return fmt.Errorf("failed to set pending results applied: %s", apiErr.Msg)
+	}
+
+	updatedFiles, err := writePlanFiles(toApply, redactions)
+	if err != nil {
+		return err
+	}
+
+	if len(updatedFiles) == 0 {
+		fmt.Println("✅ Applied changes, but no files were updated")
+		return nil
+	}
+
+	suffix := ""
+	if len(updatedFiles) > 1 {
+		suffix = "s"
+	}
+	fmt.Printf("✅ Applied changes, %d file%s updated\n", len(updatedFiles), suffix)
+
+	return nil
+}
+
+// writePlanFiles writes the plan's files to the project, returning the paths that changed
+func writePlanFiles(toApply map[string]string, redactions map[string]string) ([]string, error) {
 	var updatedFiles []string
 	for path, content := range toApply {
 		// Compute destination path
 		dstPath := filepath.Join(fs.ProjectRoot, path)
 
 		content = strings.ReplaceAll(content, "\\`\\`\\`", "```")
+		content = secrets.Restore(content, redactions)
 
 		// Check if the file exists
 		var exists bool
@@ -146,8 +363,7 @@ func MustApplyPlan(planId, branch string, autoConfirm bool) {
 			if os.IsNotExist(err) {
 				exists = false
 			} else {
-				onErr("failed to check if %s exists:", dstPath)
-				return
+				return nil, fmt.Errorf("failed to check if %s exists: %v", dstPath, err)
 			}
 		}
 
@@ -156,8 +372,15 @@ func MustApplyPlan(planId, branch string, autoConfirm bool) {
 			bytes, err := os.ReadFile(dstPath)
 
 			if err != nil {
-				onErr("failed to read %s:", dstPath)
-				return
+				return nil, fmt.Errorf("failed to read %s: %v", dstPath, err)
+			}
+
+			// notebooks are built as a text view of their cells -- merge back into the original nbformat json
+			if shared.IsNotebookFile(path) {
+				content, err = shared.NotebookFromText(string(bytes), content)
+				if err != nil {
+					return nil, fmt.Errorf("failed to write notebook %s: %v", path, err)
+				}
 			}
 
 			// Check if the file has changed
@@ -170,61 +393,26 @@ func MustApplyPlan(planId, branch string, autoConfirm bool) {
 		} else {
 			updatedFiles = append(updatedFiles, path)
 
+			if shared.IsNotebookFile(path) {
+				content, err = shared.NotebookFromText("", content)
+				if err != nil {
+					return nil, fmt.Errorf("failed to write notebook %s: %v", path, err)
+				}
+			}
+
 			// Create the directory if it doesn't exist
 			err := os.MkdirAll(filepath.Dir(dstPath), 0755)
 			if err != nil {
-				onErr("failed to create directory %s:", filepath.Dir(dstPath))
-				return
+				return nil, fmt.Errorf("failed to create directory %s: %v", filepath.Dir(dstPath), err)
 			}
 		}
 
 		// Write the file
 		err = os.WriteFile(dstPath, []byte(content), 0644)
 		if err != nil {
-			onErr("failed to write %s:", dstPath)
-			return
+			return nil, fmt.Errorf("failed to write %s: %v", dstPath, err)
 		}
 	}
 
-	term.StopSpinner()
-
-	if len(updatedFiles) == 0 {
-		fmt.Println("✅ Applied changes, but no files were updated")
-		return
-	} else {
-		if isRepo {
-			fmt.Println("✏️  Plandex can commit these updates with an automatically generated message.")
-			fmt.Println()
-			fmt.Println("ℹ️  Only the files that Plandex is updating will be included the commit. Any other changes, staged or unstaged, will remain exactly as they are.")
-			fmt.Println()
-
-			confirmed, err := term.ConfirmYesNo("Commit Plandex updates now?")
-
-			if err != nil {
-				onErr("failed to get confirmation user input: %s", err)
-			}
-
-			if confirmed {
-				// Commit the changes
-				msg := currentPlanState.PendingChangesSummaryForApply(commitSummary)
-
-				// log.Println("Committing changes with message:")
-				// log.Println(msg)
-
-				// spew.Dump(currentPlanState)
-
-				err := GitAddAndCommitPaths(fs.ProjectRoot, msg, updatedFiles, true)
-				if err != nil {
-					onGitErr("Failed to commit changes:", err.Error())
-				}
-			}
-		}
-
-		suffix := ""
-		if len(updatedFiles) > 1 {
-			suffix = "s"
-		}
-		fmt.Printf("✅ Applied changes, %d file%s updated\n", len(updatedFiles), suffix)
-	}
-
+	return updatedFiles, nil
 }
@@ -0,0 +1,206 @@
+package lib
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"plandex/api"
+	"plandex/context_index"
+	"plandex/fs"
+	"plandex/term"
+
+	"github.com/fatih/color"
+	"github.com/plandex/plandex/shared"
+)
+
+// MustAutoLoadContext searches the project index for files relevant to the prompt and loads the top maxFiles results that fit in the planner's remaining token budget
+func MustAutoLoadContext(prompt string, maxFiles int) {
+	term.StartSpinner("🔎 Finding relevant context...")
+
+	onErr := func(err error) {
+		term.StopSpinner()
+		term.OutputErrorAndExit("Failed to auto-load context: %v", err)
+	}
+
+	settings, apiErr := api.Client.GetSettings(CurrentPlanId, CurrentBranch)
+	if apiErr != nil {
+		onErr(fmt.Errorf("failed to get plan settings: %v", apiErr.Msg))
+	}
+
+	existingContexts, apiErr := api.Client.ListContext(CurrentPlanId, CurrentBranch)
+	if apiErr != nil {
+		onErr(fmt.Errorf("failed to list contexts: %v", apiErr.Msg))
+	}
+
+	promptTokens, err := shared.GetNumTokens(prompt)
+	if err != nil {
+		onErr(fmt.Errorf("failed to get num tokens for prompt: %v", err))
+	}
+
+	budget := settings.GetPlannerEffectiveMaxTokens() - promptTokens
+	loadedPaths := map[string]bool{}
+	for _, context := range existingContexts {
+		if !context.Disabled {
+			budget -= context.NumTokens
+		}
+		if context.FilePath != "" && context.LineRange == nil {
+			loadedPaths[context.FilePath] = true
+		}
+	}
+
+	if budget <= 0 {
+		term.StopSpinner()
+		fmt.Println("🤷‍♂️ No token budget left for auto-context")
+		return
+	}
+
+	paths, err := fs.GetProjectPaths(fs.ProjectRoot)
+	if err != nil {
+		onErr(fmt.Errorf("failed to get project paths: %v", err))
+	}
+
+	idx, err := context_index.Load(fs.PlandexDir)
+	if err != nil {
+		onErr(fmt.Errorf("failed to load project index: %v", err))
+	}
+
+	numUpdated, err := idx.Refresh(fs.ProjectRoot, paths.ActivePaths)
+	if err != nil {
+		onErr(fmt.Errorf("failed to refresh project index: %v", err))
+	}
+
+	if numUpdated > 0 {
+		err = idx.Save()
+		if err != nil {
+			onErr(fmt.Errorf("failed to save project index: %v", err))
+		}
+	}
+
+	// search deeper than maxFiles so that already loaded or oversized files can be passed over
+	results := idx.Search(prompt, maxFiles*3)
+
+	var loadContextReq shared.LoadContextRequest
+	var added []*context_index.Result
+	tokensByPath := map[string]int{}
+	var skippedForBudget int
+
+	for _, result := range results {
+		if len(added) >= maxFiles {
+			break
+		}
+
+		if loadedPaths[result.Path] {
+			continue
+		}
+
+		// index paths are relative to the project root
+		bytes, err := os.ReadFile(filepath.Join(fs.ProjectRoot, result.Path))
+		if err != nil {
+			onErr(fmt.Errorf("failed to read the file %s: %v", result.Path, err))
+		}
+
+		body := string(bytes)
+		if shared.IsNotebookFile(result.Path) {
+			body, err = shared.NotebookToText(body)
+			if err != nil {
+				onErr(fmt.Errorf("failed to read the notebook %s: %v", result.Path, err))
+			}
+		}
+
+		numTokens, err := shared.GetNumTokens(body)
+		if err != nil {
+			onErr(fmt.Errorf("failed to get num tokens for %s: %v", result.Path, err))
+		}
+
+		if numTokens > budget {
+			skippedForBudget++
+			continue
+		}
+		budget -= numTokens
+
+		tokensByPath[result.Path] = numTokens
+		added = append(added, result)
+		loadContextReq = append(loadContextReq, &shared.LoadContextParams{
+			ContextType: shared.ContextFileType,
+			Name:        result.Path,
+			Body:        body,
+			FilePath:    result.Path,
+		})
+	}
+
+	if len(loadContextReq) == 0 {
+		term.StopSpinner()
+		if skippedForBudget > 0 {
+			fmt.Println("🤷‍♂️ No relevant files fit in the remaining token budget")
+		} else {
+			fmt.Println("🤷‍♂️ No relevant files found for auto-context")
+		}
+		return
+	}
+
+	guard, err := newSecretGuard()
+	if err != nil {
+		onErr(err)
+	}
+	for _, context := range loadContextReq {
+		guard.scanLoadParams(context)
+	}
+	err = guard.resolve()
+	if err != nil {
+		onErr(err)
+	}
+
+	filesToLoad := map[string]string{}
+	for _, context := range loadContextReq {
+		filesToLoad[context.FilePath] = context.Body
+	}
+
+	hasConflicts, err := checkContextConflicts(filesToLoad)
+	if err != nil {
+		onErr(fmt.Errorf("failed to check context conflicts: %v", err))
+	}
+
+	res, apiErr := api.Client.LoadContext(CurrentPlanId, CurrentBranch, loadContextReq)
+	if apiErr != nil {
+		onErr(fmt.Errorf("failed to load context: %v", apiErr.Msg))
+	}
+
+	term.StopSpinner()
+
+	if res.MaxTokensExceeded {
+		overage := res.TotalTokens - res.MaxTokens
+		term.OutputErrorAndExit("Auto-context would add %d 🪙 and exceed token limit (%d) by %d 🪙\n", res.TokensAdded, res.MaxTokens, overage)
+	}
+
+	if hasConflicts {
+		term.StartSpinner("🏗️  Starting build...")
+		_, err := buildPlanInlineFn(nil)
+
+		if err != nil {
+			onErr(fmt.Errorf("failed to build plan: %v", err))
+		}
+
+		fmt.Println()
+	}
+
+	color.New(color.Bold, term.ColorHiCyan).Printf("📥 Auto-loaded %d relevant %s into context\n", len(added), pluralFiles(len(added)))
+	for _, result := range added {
+		fmt.Printf("  • 📄 %s %s\n", color.New(color.Bold).Sprint(result.Path), color.New(color.FgHiBlack).Sprintf("(%d 🪙)", tokensByPath[result.Path]))
+		fmt.Printf("    %s\n", color.New(color.FgHiBlack).Sprintf("score %.1f | %s", result.Score, result.Reason()))
+	}
+
+	guard.printSummary()
+
+	if skippedForBudget > 0 {
+		fmt.Printf("ℹ️  Skipped %d relevant %s that wouldn't fit in the remaining token budget\n", skippedForBudget, pluralFiles(skippedForBudget))
+	}
+
+	fmt.Println()
+}
+
+func pluralFiles(n int) string {
+	if n == 1 {
+		return "file"
+	}
+	return "files"
+}
@@ -0,0 +1,92 @@
+package lib
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"plandex/fs"
+	"plandex/url"
+	"sync"
+)
+
+// schemas.json in the project's plandex dir maps file globs to JSON Schemas, e.g.
+// {"k8s/*.yaml": "schemas/deployment.json", "package.json": "https://json.schemastore.org/package.json"}
+// Schema paths are relative to the project root. Matching build results are validated against the schema on the server.
+const configSchemasFile = "schemas.json"
+
+// schemas fetched from urls are kept for the rest of the run, since a run can load the config more than once (e.g. a tell followed by a build, or each prompt of a replay)
+var fetchedSchemasByUrl = map[string]string{}
+var fetchedSchemasMu sync.Mutex
+
+func GetConfigSchemas() (map[string]string, error) {
+	if fs.PlandexDir == "" {
+		return nil, nil
+	}
+
+	path := filepath.Join(fs.PlandexDir, configSchemasFile)
+	bytes, err := os.ReadFile(path)
+
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error reading %s: %v", configSchemasFile, err)
+	}
+
+	var refsByGlob map[string]string
+	err = json.Unmarshal(bytes, &refsByGlob)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling %s: %v", configSchemasFile, err)
+	}
+
+	schemasByGlob := map[string]string{}
+	for glob, ref := range refsByGlob {
+		_, err := filepath.Match(glob, "")
+		if err != nil {
+			return nil, fmt.Errorf("invalid glob '%s' in %s: %v", glob, configSchemasFile, err)
+		}
+
+		var schema string
+		if url.IsValidURL(ref) {
+			schema, err = fetchSchema(ref)
+			if err != nil {
+				return nil, fmt.Errorf("error fetching schema %s: %v", ref, err)
+			}
+		} else {
+			schemaPath := ref
+			if !filepath.IsAbs(schemaPath) {
+				schemaPath = filepath.Join(fs.ProjectRoot, schemaPath)
+			}
+			b, err := os.ReadFile(schemaPath)
+			if err != nil {
+				return nil, fmt.Errorf("error reading schema %s: %v", ref, err)
+			}
+			schema = string(b)
+		}
+
+		if !json.Valid([]byte(schema)) {
+			return nil, fmt.Errorf("schema %s for '%s' is not valid JSON", ref, glob)
+		}
+
+		schemasByGlob[glob] = schema
+	}
+
+	return schemasByGlob, nil
+}
+
+func fetchSchema(schemaUrl string) (string, error) {
+	fetchedSchemasMu.Lock()
+	defer fetchedSchemasMu.Unlock()
+
+	if schema, ok := fetchedSchemasByUrl[schemaUrl]; ok {
+		return schema, nil
+	}
+
+	schema, err := url.FetchURLContent(schemaUrl)
+	if err != nil {
+		return "", err
+	}
+
+	fetchedSchemasByUrl[schemaUrl] = schema
+	return schema, nil
+}
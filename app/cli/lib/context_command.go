@@ -0,0 +1,54 @@
+package lib
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"plandex/fs"
+	"runtime"
+	"strings"
+
+	"github.com/plandex/plandex/shared"
+)
+
+// getCommandContextBody runs a command in the project root and returns its combined output. A non-zero exit isn't an error since failing test or lint output is usually what's wanted in context, but the exit code is noted at the end of the output.
+func getCommandContextBody(command string) (string, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+	cmd.Dir = fs.ProjectRoot
+
+	output, err := cmd.CombinedOutput()
+	body := strings.TrimRight(string(output), "\n")
+
+	if err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return "", fmt.Errorf("failed to run '%s': %v", command, err)
+		}
+		body += fmt.Sprintf("\n\n(exited with code %d)", exitErr.ExitCode())
+	}
+
+	if strings.TrimSpace(body) == "" {
+		body = "(no output)"
+	}
+
+	return body, nil
+}
+
+func getCommandContextParams(command string) (*shared.LoadContextParams, error) {
+	body, err := getCommandContextBody(command)
+	if err != nil {
+		return nil, err
+	}
+
+	return &shared.LoadContextParams{
+		ContextType: shared.ContextCommandType,
+		Name:        command,
+		Body:        body,
+		Command:     command,
+	}, nil
+}
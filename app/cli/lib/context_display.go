@@ -24,6 +24,18 @@ func GetContextLabelAndIcon(contextType shared.ContextType) (string, string) {
 	case shared.ContextImageType:
 		icon = "🖼️ "
 		lbl = "image"
+	case shared.ContextGitDiffType:
+		icon = "🔀"
+		lbl = "diff"
+	case shared.ContextGitCommitType:
+		icon = "🔀"
+		lbl = "commit"
+	case shared.ContextGitStagedType:
+		icon = "🔀"
+		lbl = "staged"
+	case shared.ContextCommandType:
+		icon = "💻"
+		lbl = "command"
 	}
 
 	return lbl, icon
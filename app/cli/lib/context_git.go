@@ -0,0 +1,79 @@
+package lib
+
+import (
+	"fmt"
+	"plandex/fs"
+	"strings"
+
+	"github.com/plandex/plandex/shared"
+)
+
+type gitContextSource struct {
+	contextType shared.ContextType
+	ref         string
+}
+
+// getGitContextBody regenerates the diff text for a git context type and ref. Refs are stored on the server and can be set by anyone the plan is shared with, so they're checked to be commits before being passed to git.
+func getGitContextBody(contextType shared.ContextType, ref string) (string, error) {
+	if ref != "" {
+		_, err := GitResolveCommit(ref)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	switch contextType {
+	case shared.ContextGitDiffType:
+		return GitDiff(ref)
+	case shared.ContextGitStagedType:
+		return GitDiffStaged()
+	case shared.ContextGitCommitType:
+		return GitShowCommit(ref)
+	}
+
+	return "", fmt.Errorf("not a git context type: %s", contextType)
+}
+
+func getGitContextName(contextType shared.ContextType, ref string) string {
+	switch contextType {
+	case shared.ContextGitDiffType:
+		if ref == "" {
+			return "git diff HEAD"
+		}
+		return "git diff " + ref
+	case shared.ContextGitStagedType:
+		return "git staged"
+	case shared.ContextGitCommitType:
+		// commit refs are stored as full shas
+		if len(ref) == 40 {
+			return "git commit " + ref[:8]
+		}
+		return "git commit " + ref
+	}
+
+	return string(contextType)
+}
+
+func getGitContextParams(contextType shared.ContextType, ref string) (*shared.LoadContextParams, error) {
+	if !fs.ProjectRootIsGitRepo() {
+		return nil, fmt.Errorf("project isn't a git repository")
+	}
+
+	body, err := getGitContextBody(contextType, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	name := getGitContextName(contextType, ref)
+
+	if strings.TrimSpace(body) == "" {
+		return nil, fmt.Errorf("%s is empty", name)
+	}
+
+	return &shared.LoadContextParams{
+		ContextType: contextType,
+		Name:        name,
+		Body:        body,
+		GitRef:      ref,
+	}, nil
+}
@@ -7,6 +7,7 @@ import (
 
 	"io"
 	"os"
+	"path/filepath"
 	"plandex/api"
 	"plandex/fs"
 	"plandex/term"
@@ -17,6 +18,7 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/plandex/plandex/shared"
+	"github.com/sashabaranov/go-openai"
 )
 
 func MustLoadContext(resources []string, params *types.LoadContextParams) {
@@ -76,20 +78,56 @@ func MustLoadContext(resources []string, params *types.LoadContextParams) {
 
 	var inputUrls []string
 	var inputFilePaths []string
+	var inputGlobs []string
+	var inputRanges []*lineRangeArg
 
 	if len(resources) > 0 {
 		for _, resource := range resources {
-			// so far resources are either files or urls
+			// resources are urls, files or dirs, globs, '!' exclude patterns, or files with a line range
 			if url.IsValidURL(resource) {
 				inputUrls = append(inputUrls, resource)
+			} else if strings.HasPrefix(resource, "!") {
+				params.Excludes = append(params.Excludes, resource[1:])
 			} else {
 				if strings.HasPrefix(resource, "."+string(os.PathSeparator)) {
 					resource = resource[2:]
 				}
 
-				inputFilePaths = append(inputFilePaths, resource)
+				// context paths are stored relative to the project root, since they're read on the machine of anyone the plan is shared with
+				if filepath.IsAbs(resource) {
+					if rel, err := filepath.Rel(fs.ProjectRoot, resource); err == nil {
+						resource = rel
+					}
+				}
+				if err := shared.ValidateContextPath(resource); err != nil {
+					onErr(err)
+				}
+
+				if lineRange, ok := parseLineRangeArg(resource); ok {
+					inputRanges = append(inputRanges, lineRange)
+				} else if isGlobPattern(resource) {
+					inputGlobs = append(inputGlobs, resource)
+				} else {
+					inputFilePaths = append(inputFilePaths, resource)
+				}
+			}
+		}
+	}
+
+	// files loaded through a directory or glob remember the pattern, so that `plandex watch` can pick up new files that match it
+	loadedFromByPath := map[string]string{}
+
+	for _, glob := range inputGlobs {
+		matches, err := expandGlob(glob)
+		if err != nil {
+			onErr(err)
+		}
+		for _, match := range matches {
+			if _, ok := loadedFromByPath[match]; !ok {
+				loadedFromByPath[match] = glob
 			}
 		}
+		inputFilePaths = append(inputFilePaths, matches...)
 	}
 
 	var contextMu sync.Mutex
@@ -110,10 +148,76 @@ func MustLoadContext(resources []string, params *types.LoadContextParams) {
 	for _, context := range existingContexts {
 		switch context.ContextType {
 		case shared.ContextFileType, shared.ContextDirectoryTreeType:
-			existsByComposite[strings.Join([]string{string(context.ContextType), context.FilePath}, "|")] = context
+			path := context.FilePath
+			if context.LineRange != nil {
+				path += ":" + context.LineRange.Label()
+			}
+			existsByComposite[strings.Join([]string{string(context.ContextType), path}, "|")] = context
 		case shared.ContextURLType:
 			existsByComposite[strings.Join([]string{string(context.ContextType), context.Url}, "|")] = context
+		case shared.ContextGitDiffType, shared.ContextGitCommitType, shared.ContextGitStagedType:
+			existsByComposite[strings.Join([]string{string(context.ContextType), context.GitRef}, "|")] = context
+		case shared.ContextCommandType:
+			existsByComposite[strings.Join([]string{string(context.ContextType), context.Command}, "|")] = context
+		}
+	}
+
+	var gitSources []gitContextSource
+	if params.GitDiff {
+		gitSources = append(gitSources, gitContextSource{shared.ContextGitDiffType, params.GitDiffBase})
+	}
+	if params.GitStaged {
+		gitSources = append(gitSources, gitContextSource{shared.ContextGitStagedType, ""})
+	}
+	if params.GitCommit != "" {
+		if !fs.ProjectRootIsGitRepo() {
+			onErr(fmt.Errorf("project isn't a git repository"))
+		}
+
+		sha, err := GitResolveCommit(params.GitCommit)
+		if err != nil {
+			onErr(err)
+		}
+
+		gitSources = append(gitSources, gitContextSource{shared.ContextGitCommitType, sha})
+	}
+
+	for _, source := range gitSources {
+		contextType, ref := source.contextType, source.ref
+
+		composite := strings.Join([]string{string(contextType), ref}, "|")
+		if existsByComposite[composite] != nil {
+			alreadyLoadedByComposite[composite] = existsByComposite[composite]
+			continue
+		}
+
+		gitParams, err := getGitContextParams(contextType, ref)
+		if err != nil {
+			onErr(err)
 		}
+
+		loadContextReq = append(loadContextReq, gitParams)
+	}
+
+	for _, command := range params.Commands {
+		composite := strings.Join([]string{string(shared.ContextCommandType), command}, "|")
+		if existsByComposite[composite] != nil {
+			alreadyLoadedByComposite[composite] = existsByComposite[composite]
+			continue
+		}
+
+		term.StopSpinner()
+		term.StartSpinner(fmt.Sprintf("💻 Running '%s'...", command))
+
+		commandParams, err := getCommandContextParams(command)
+		if err != nil {
+			onErr(err)
+		}
+
+		loadContextReq = append(loadContextReq, commandParams)
+
+		term.StopSpinner()
+		term.StartSpinner("📥 Loading context...")
 	}
 
 	if len(inputFilePaths) > 0 {
@@ -210,11 +314,26 @@ func MustLoadContext(resources []string, params *types.LoadContextParams) {
 			}
 
 		} else {
+			var inputDirs []string
+			for _, inputFilePath := range inputFilePaths {
+				if info, err := os.Stat(inputFilePath); err == nil && info.IsDir() {
+					inputDirs = append(inputDirs, inputFilePath)
+				}
+			}
+
 			flattenedPaths, err := ParseInputPaths(inputFilePaths, params)
 			if err != nil {
 				onErr(fmt.Errorf("failed to parse input paths: %v", err))
 			}
 
+			for _, path := range flattenedPaths {
+				if _, ok := loadedFromByPath[path]; !ok {
+					if dir := containingDir(path, inputDirs); dir != "" {
+						loadedFromByPath[path] = dirGlob(dir)
+					}
+				}
+			}
+
 			if !params.ForceSkipIgnore {
 				var filteredPaths []string
 				for _, path := range flattenedPaths {
@@ -249,33 +368,16 @@ func MustLoadContext(resources []string, params *types.LoadContextParams) {
 
 				numRoutines++
 				go func(path string) {
-
-					fileContent, err := os.ReadFile(path)
+					fileParams, err := getFileContextParams(path, params.ImageDetail)
 					if err != nil {
-						errCh <- fmt.Errorf("failed to read the file %s: %v", path, err)
+						errCh <- err
 						return
 					}
+					fileParams.LoadedFrom = loadedFromByPath[path]
 
 					contextMu.Lock()
 					defer contextMu.Unlock()
-
-					if isImage {
-
-						loadContextReq = append(loadContextReq, &shared.LoadContextParams{
-							ContextType: shared.ContextImageType,
-							Name:        path,
-							Body:        base64.StdEncoding.EncodeToString(fileContent),
-							FilePath:    path,
-							ImageDetail: params.ImageDetail,
-						})
-					} else {
-						loadContextReq = append(loadContextReq, &shared.LoadContextParams{
-							ContextType: shared.ContextFileType,
-							Name:        path,
-							Body:        string(fileContent),
-							FilePath:    path,
-						})
-					}
+					loadContextReq = append(loadContextReq, fileParams)
 
 					errCh <- nil
 				}(path)
@@ -283,7 +385,118 @@ func MustLoadContext(resources []string, params *types.LoadContextParams) {
 		}
 	}
 
-	if len(inputUrls) > 0 {
+	if len(inputRanges) > 0 {
+		var rangePaths []string
+		for _, lineRange := range inputRanges {
+			rangePaths = append(rangePaths, lineRange.path)
+		}
+
+		paths, err := fs.GetProjectPaths(fs.GetBaseDirForFilePaths(rangePaths))
+		if err != nil {
+			onErr(fmt.Errorf("failed to get project paths: %v", err))
+		}
+
+		for _, lineRange := range inputRanges {
+			path := lineRange.path
+
+			if !params.ForceSkipIgnore && !paths.ActivePaths[path] {
+				if _, ok := paths.IgnoredPaths[path]; ok {
+					ignoredPaths[path] = paths.IgnoredPaths[path]
+				}
+				continue
+			}
+
+			if shared.IsImageFile(path) || shared.IsNotebookFile(path) || shared.IsDocumentFile(path) {
+				onErr(fmt.Errorf("line ranges aren't supported for %s", path))
+			}
+
+			label := (&shared.LineRange{Start: lineRange.start, End: lineRange.end}).Label()
+			composite := strings.Join([]string{string(shared.ContextFileType), path + ":" + label}, "|")
+
+			if existsByComposite[composite] != nil {
+				alreadyLoadedByComposite[composite] = existsByComposite[composite]
+				continue
+			}
+
+			numRoutines++
+			go func(lineRange *lineRangeArg) {
+				fileContent, err := os.ReadFile(lineRange.path)
+				if err != nil {
+					errCh <- fmt.Errorf("failed to read the file %s: %v", lineRange.path, err)
+					return
+				}
+
+				resolved, body, err := shared.NewLineRange(string(fileContent), lineRange.start, lineRange.end)
+				if err != nil {
+					errCh <- fmt.Errorf("failed to load %s: %v", lineRange.path, err)
+					return
+				}
+
+				contextMu.Lock()
+				defer contextMu.Unlock()
+
+				loadContextReq = append(loadContextReq, &shared.LoadContextParams{
+					ContextType: shared.ContextFileType,
+					Name:        lineRange.path + ":" + resolved.Label(),
+					Body:        body,
+					FilePath:    lineRange.path,
+					LineRange:   resolved,
+				})
+
+				errCh <- nil
+			}(lineRange)
+		}
+	}
+
+	var crawlResults map[string]*url.CrawlResult
+
+	if len(inputUrls) > 0 && params.Crawl {
+		crawlResults = map[string]*url.CrawlResult{}
+
+		for _, u := range inputUrls {
+			numRoutines++
+			go func(u string) {
+				res, err := url.Crawl(u, url.CrawlOptions{
+					Depth:     params.CrawlDepth,
+					MaxPages:  params.CrawlMaxPages,
+					MaxTokens: params.CrawlMaxTokens,
+					Skip: func(pageUrl string) bool {
+						composite := strings.Join([]string{string(shared.ContextURLType), pageUrl}, "|")
+						if existsByComposite[composite] != nil {
+							contextMu.Lock()
+							defer contextMu.Unlock()
+							alreadyLoadedByComposite[composite] = existsByComposite[composite]
+							return true
+						}
+						return false
+					},
+				})
+				if err != nil {
+					errCh <- fmt.Errorf("failed to crawl %s: %v", u, err)
+					return
+				}
+
+				contextMu.Lock()
+				defer contextMu.Unlock()
+
+				crawlResults[u] = res
+
+				// each page is its own url context, grouped under the root url so the whole crawl can be disabled at once
+				for _, page := range res.Pages {
+					loadContextReq = append(loadContextReq, &shared.LoadContextParams{
+						ContextType: shared.ContextURLType,
+						Name:        urlContextName(page.Url),
+						Body:        page.Body,
+						Url:         page.Url,
+						SourceSha:   page.SourceSha,
+						Group:       u,
+					})
+				}
+
+				errCh <- nil
+			}(u)
+		}
+	} else if len(inputUrls) > 0 {
 		for _, u := range inputUrls {
 			composite := strings.Join([]string{string(shared.ContextURLType), u}, "|")
 			if existsByComposite[composite] != nil {
@@ -293,26 +506,21 @@ func MustLoadContext(resources []string, params *types.LoadContextParams) {
 
 			numRoutines++
 			go func(u string) {
-				body, err := url.FetchURLContent(u)
+				body, sourceSha, err := url.FetchURLContentAndSha(u)
 				if err != nil {
 					errCh <- fmt.Errorf("failed to fetch content from URL %s: %v", u, err)
 					return
 				}
 
-				name := url.SanitizeURL(u)
-				// show the first 20 characters, then ellipsis then the last 20 characters of 'name'
-				if len(name) > 40 {
-					name = name[:20] + "⋯" + name[len(name)-20:]
-				}
-
 				contextMu.Lock()
 				defer contextMu.Unlock()
 
 				loadContextReq = append(loadContextReq, &shared.LoadContextParams{
 					ContextType: shared.ContextURLType,
-					Name:        name,
+					Name:        urlContextName(u),
 					Body:        body,
 					Url:         u,
+					SourceSha:   sourceSha,
 				})
 
 				errCh <- nil
@@ -327,9 +535,25 @@ func MustLoadContext(resources []string, params *types.LoadContextParams) {
 		}
 	}
 
+	guard, err := newSecretGuard()
+	if err != nil {
+		onErr(err)
+	}
+	for _, context := range loadContextReq {
+		guard.scanLoadParams(context)
+	}
+	err = guard.resolve()
+	if err != nil {
+		onErr(err)
+	}
+
 	filesToLoad := map[string]string{}
 	for _, context := range loadContextReq {
-		if context.ContextType == shared.ContextFileType {
+		if params.Group != "" {
+			context.Group = params.Group
+		}
+
+		if context.ContextType == shared.ContextFileType && context.LineRange == nil {
 			filesToLoad[context.FilePath] = context.Body
 		}
 	}
@@ -396,6 +620,11 @@ func MustLoadContext(resources []string, params *types.LoadContextParams) {
 		term.OutputErrorAndExit("Update would add %d 🪙 and exceed token limit (%d) by %d 🪙\n", res.TokensAdded, res.MaxTokens, overage)
 	}
 
+	err = trustLoadedCommands(params.Commands)
+	if err != nil {
+		onErr(fmt.Errorf("failed to trust loaded commands: %v", err))
+	}
+
 	if hasConflicts {
 		term.StartSpinner("🏗️  Starting build...")
 		_, err := buildPlanInlineFn(nil)
@@ -416,6 +645,77 @@ func MustLoadContext(resources []string, params *types.LoadContextParams) {
 	if len(ignoredPaths) > 0 {
 		printIgnoredMsg()
 	}
+
+	for u, crawlRes := range crawlResults {
+		printCrawlMsg(u, crawlRes, params)
+	}
+
+	guard.printSummary()
+}
+
+// getFileContextParams reads a file into load params, converting images, documents, and notebooks as needed
+func getFileContextParams(path string, imageDetail openai.ImageURLDetail) (*shared.LoadContextParams, error) {
+	fileContent, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the file %s: %v", path, err)
+	}
+
+	if shared.IsImageFile(path) {
+		return &shared.LoadContextParams{
+			ContextType: shared.ContextImageType,
+			Name:        path,
+			Body:        base64.StdEncoding.EncodeToString(fileContent),
+			FilePath:    path,
+			ImageDetail: imageDetail,
+		}, nil
+	}
+
+	body := string(fileContent)
+	var sourceSha string
+
+	if shared.IsDocumentFile(path) {
+		body, err = shared.DocumentToText(shared.GetDocumentType(path), fileContent)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract text from %s: %v", path, err)
+		}
+		sourceSha = shared.GetDocumentSha(fileContent)
+	} else if shared.IsNotebookFile(path) {
+		body, err = shared.NotebookToText(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the notebook %s: %v", path, err)
+		}
+	}
+
+	return &shared.LoadContextParams{
+		ContextType: shared.ContextFileType,
+		Name:        path,
+		Body:        body,
+		FilePath:    path,
+		SourceSha:   sourceSha,
+	}, nil
+}
+
+// containingDir returns the innermost of dirs that contains path, or an empty string if none do
+func containingDir(path string, dirs []string) string {
+	var res string
+	for _, dir := range dirs {
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(os.PathSeparator)) {
+			continue
+		}
+		if res == "" || len(dir) > len(res) {
+			res = dir
+		}
+	}
+	return res
+}
+
+// dirGlob is the pattern that matches every file under dir
+func dirGlob(dir string) string {
+	if dir == "." {
+		return "**"
+	}
+	return filepath.ToSlash(filepath.Clean(dir)) + "/**"
 }
 
 func printAlreadyLoadedMsg(alreadyLoadedByComposite map[string]*shared.Context) {
@@ -432,6 +732,40 @@ func printAlreadyLoadedMsg(alreadyLoadedByComposite map[string]*shared.Context)
 	}
 }
 
+func urlContextName(u string) string {
+	name := url.SanitizeURL(u)
+	// show the first 20 characters, then ellipsis then the last 20 characters of 'name'
+	if len(name) > 40 {
+		name = name[:20] + "⋯" + name[len(name)-20:]
+	}
+	return name
+}
+
+func printCrawlMsg(u string, res *url.CrawlResult, params *types.LoadContextParams) {
+	fmt.Println()
+	fmt.Printf("🕸️  Crawled %d %s from %s (%d 🪙)\n", len(res.Pages), pluralPages(len(res.Pages)), u, res.NumTokens)
+
+	if res.Truncated {
+		fmt.Printf("ℹ️  Stopped at the limit of %d pages or %d 🪙. Use --crawl-max-pages or --crawl-max-tokens to load more.\n", params.CrawlMaxPages, params.CrawlMaxTokens)
+	}
+	if res.NumDisallow > 0 {
+		fmt.Printf("ℹ️  Skipped %d %s disallowed by robots.txt\n", res.NumDisallow, pluralPages(res.NumDisallow))
+	}
+	if res.NumFailed > 0 {
+		fmt.Printf("ℹ️  Skipped %d %s that couldn't be fetched\n", res.NumFailed, pluralPages(res.NumFailed))
+	}
+	if params.Group == "" && len(res.Pages) > 0 {
+		fmt.Printf("ℹ️  Pages are grouped as '%s'\n", u)
+	}
+}
+
+func pluralPages(n int) string {
+	if n == 1 {
+		return "page"
+	}
+	return "pages"
+}
+
 func printIgnoredMsg() {
 	fmt.Println()
 	fmt.Println("ℹ️  " + color.New(color.FgWhite).Sprint("Due to .gitignore or .plandexignore, some paths weren't loaded.\nUse --force / -f to load ignored paths."))
@@ -15,6 +15,11 @@ func ParseInputPaths(fileOrDirPaths []string, params *types.LoadContextParams) (
 	var firstErr error
 	resPaths := []string{}
 
+	excludes, err := compileExcludes(params.Excludes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exclude pattern: %v", err)
+	}
+
 	for _, path := range fileOrDirPaths {
 		wg.Add(1)
 		go func(p string) {
@@ -36,6 +41,10 @@ func ParseInputPaths(fileOrDirPaths []string, params *types.LoadContextParams) (
 						return filepath.SkipDir
 					}
 
+					if path != p && isExcluded(path, excludes) {
+						return filepath.SkipDir
+					}
+
 					if !(params.Recursive || params.NamesOnly) {
 						// log.Println("path", path, "info.Name()", info.Name())
 
@@ -52,7 +61,7 @@ func ParseInputPaths(fileOrDirPaths []string, params *types.LoadContextParams) (
 						// add directory name to results
 						resPaths = append(resPaths, path)
 					}
-				} else {
+				} else if !isExcluded(path, excludes) {
 					// add file path to results
 					resPaths = append(resPaths, path)
 				}
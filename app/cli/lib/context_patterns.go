@@ -0,0 +1,182 @@
+package lib
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 'path/to/file.go:340-575' or 'path/to/file.go:42'
+var lineRangeArgRegex = regexp.MustCompile(`^(.+):(\d+)(?:-(\d+))?$`)
+
+type lineRangeArg struct {
+	path  string
+	start int
+	end   int
+}
+
+func parseLineRangeArg(arg string) (*lineRangeArg, bool) {
+	match := lineRangeArgRegex.FindStringSubmatch(arg)
+	if match == nil {
+		return nil, false
+	}
+
+	start, err := strconv.Atoi(match[2])
+	if err != nil {
+		return nil, false
+	}
+
+	end := start
+	if match[3] != "" {
+		end, err = strconv.Atoi(match[3])
+		if err != nil {
+			return nil, false
+		}
+	}
+
+	return &lineRangeArg{path: match[1], start: start, end: end}, true
+}
+
+func isGlobPattern(s string) bool {
+	return strings.ContainsAny(s, "*?[")
+}
+
+// globToRegexp converts a glob to a regexp. '**' matches any number of directories, '*' and '?' don't cross directory boundaries.
+func globToRegexp(pattern string) (*regexp.Regexp, error) {
+	pattern = filepath.ToSlash(pattern)
+
+	var sb strings.Builder
+	sb.WriteString("^")
+
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+				if i+1 < len(pattern) && pattern[i+1] == '/' {
+					i++
+					sb.WriteString("(?:.*/)?")
+				} else {
+					sb.WriteString(".*")
+				}
+			} else {
+				sb.WriteString("[^/]*")
+			}
+		case '?':
+			sb.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(pattern[i:], ']')
+			if end == -1 {
+				return nil, fmt.Errorf("unclosed '[' in pattern %s", pattern)
+			}
+			class := pattern[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + class + "]")
+			i += end
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	sb.WriteString("$")
+
+	return regexp.Compile(sb.String())
+}
+
+// excludePattern matches paths against a '!pattern' argument. Like .gitignore, a pattern without a slash matches the base name at any depth.
+type excludePattern struct {
+	re         *regexp.Regexp
+	isBaseName bool
+}
+
+func compileExcludes(patterns []string) ([]*excludePattern, error) {
+	var res []*excludePattern
+	for _, pattern := range patterns {
+		pattern = strings.TrimPrefix(filepath.ToSlash(pattern), "./")
+		re, err := globToRegexp(pattern)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, &excludePattern{
+			re:         re,
+			isBaseName: !strings.Contains(pattern, "/"),
+		})
+	}
+	return res, nil
+}
+
+func isExcluded(path string, excludes []*excludePattern) bool {
+	path = filepath.ToSlash(path)
+	for _, exclude := range excludes {
+		if exclude.isBaseName {
+			if exclude.re.MatchString(filepath.Base(path)) {
+				return true
+			}
+		} else if exclude.re.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// patternBaseDir is the part of a pattern before its first wildcard
+func patternBaseDir(pattern string) string {
+	var baseParts []string
+	for _, part := range strings.Split(filepath.ToSlash(pattern), "/") {
+		if isGlobPattern(part) {
+			break
+		}
+		baseParts = append(baseParts, part)
+	}
+
+	if len(baseParts) == 0 {
+		return "."
+	}
+	return filepath.FromSlash(strings.Join(baseParts, "/"))
+}
+
+// expandGlob returns the files matching pattern, walking from the part of the pattern before the first wildcard
+func expandGlob(pattern string) ([]string, error) {
+	re, err := globToRegexp(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	baseDir := patternBaseDir(pattern)
+
+	var res []string
+
+	err = filepath.Walk(baseDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == baseDir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+
+		if info.IsDir() {
+			if info.Name() == ".git" || strings.Index(info.Name(), ".plandex") == 0 {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if re.MatchString(filepath.ToSlash(path)) {
+			res = append(res, path)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("error expanding glob %s: %v", pattern, err)
+	}
+
+	return res, nil
+}
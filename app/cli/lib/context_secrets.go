@@ -0,0 +1,188 @@
+package lib
+
+import (
+	"fmt"
+	"plandex/fs"
+	"plandex/secrets"
+	"plandex/term"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+	"github.com/plandex/plandex/shared"
+)
+
+// secretGuard scans context for secrets before it's sent to the server, following the mode in .plandex/secrets.json
+type secretGuard struct {
+	config     *secrets.Config
+	scanner    *secrets.Scanner
+	ignore     []*excludePattern
+	mu         sync.Mutex
+	findings   map[string][]*secrets.Finding
+	redactions map[string]string
+}
+
+func newSecretGuard() (*secretGuard, error) {
+	config, err := secrets.LoadConfig(fs.PlandexDir)
+	if err != nil {
+		return nil, err
+	}
+
+	ignore, err := compileExcludes(config.Ignore)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ignore pattern in secrets config: %v", err)
+	}
+
+	return &secretGuard{
+		config:     config,
+		scanner:    secrets.NewScanner(config),
+		ignore:     ignore,
+		findings:   map[string][]*secrets.Finding{},
+		redactions: map[string]string{},
+	}, nil
+}
+
+// scan records any secrets in body, returning the body with secrets replaced by placeholders in redact mode
+func (g *secretGuard) scan(name, path, body string) string {
+	if path != "" && isExcluded(path, g.ignore) {
+		return body
+	}
+
+	findings := g.scanner.Scan(path, body)
+	if len(findings) == 0 {
+		return body
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.findings[name] = findings
+
+	if g.config.Mode != secrets.ModeRedact {
+		return body
+	}
+
+	redacted, redactions := secrets.Redact(body, findings)
+	for placeholder, value := range redactions {
+		g.redactions[placeholder] = value
+	}
+	return redacted
+}
+
+// redactLineRange redacts the anchor lines of a line range, which are sent to the server along with the body
+func (g *secretGuard) redactLineRange(lineRange *shared.LineRange) {
+	if lineRange == nil || g.config.Mode != secrets.ModeRedact {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for placeholder, value := range g.redactions {
+		lineRange.StartText = strings.ReplaceAll(lineRange.StartText, value, placeholder)
+		lineRange.EndText = strings.ReplaceAll(lineRange.EndText, value, placeholder)
+	}
+}
+
+func (g *secretGuard) scanLoadParams(params *shared.LoadContextParams) {
+	if params.ContextType == shared.ContextImageType {
+		return
+	}
+
+	name := params.Name
+	if params.FilePath != "" {
+		name = params.FilePath
+		if params.LineRange != nil {
+			name += ":" + params.LineRange.Label()
+		}
+	} else if params.Url != "" {
+		name = params.Url
+	} else if name == "" {
+		name = string(params.ContextType)
+	}
+
+	params.Body = g.scan(name, params.FilePath, params.Body)
+	g.redactLineRange(params.LineRange)
+}
+
+// contextScanName is the name secrets found in an existing context are listed under
+func contextScanName(context *shared.Context) string {
+	switch {
+	case context.FilePath != "" && context.LineRange != nil:
+		return context.FilePath + ":" + context.LineRange.Label()
+	case context.FilePath != "":
+		return context.FilePath
+	case context.Url != "":
+		return context.Url
+	case context.Command != "":
+		return context.Command
+	}
+	return context.Name
+}
+
+// retain drops findings for everything but the named contexts, so that context that's unchanged isn't reported again
+func (g *secretGuard) retain(names map[string]bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for name := range g.findings {
+		if !names[name] {
+			delete(g.findings, name)
+		}
+	}
+}
+
+// resolve returns an error listing the secrets found in block mode. In redact mode, it stores the placeholders so they can be restored when changes are applied.
+func (g *secretGuard) resolve() error {
+	if len(g.findings) == 0 {
+		return nil
+	}
+
+	switch g.config.Mode {
+	case secrets.ModeBlock:
+		return fmt.Errorf("possible secrets found in context:\n%s\nTo load it anyway, set \"mode\" to \"warn\" or \"redact\" in .plandex/secrets.json, or add false positives to \"allow\" or their paths to \"ignore\"", g.findingsList())
+	case secrets.ModeRedact:
+		return secrets.SaveRedactions(fs.HomePlandexDir, g.redactions)
+	}
+
+	return nil
+}
+
+func (g *secretGuard) printSummary() {
+	if summary := g.summary(); summary != "" {
+		fmt.Println()
+		fmt.Print(summary)
+	}
+}
+
+func (g *secretGuard) summary() string {
+	if len(g.findings) == 0 {
+		return ""
+	}
+
+	var heading string
+	switch g.config.Mode {
+	case secrets.ModeRedact:
+		heading = "🔒 Redacted possible secrets from context. They'll be restored in any changes that are applied."
+	default:
+		heading = "⚠️  Possible secrets were sent with context. Set \"mode\" to \"redact\" or \"block\" in .plandex/secrets.json to keep them on this machine."
+	}
+	return color.New(color.Bold, term.ColorHiYellow).Sprintln(heading) + g.findingsList()
+}
+
+func (g *secretGuard) findingsList() string {
+	var names []string
+	for name := range g.findings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	for _, name := range names {
+		for _, finding := range g.findings[name] {
+			sb.WriteString(fmt.Sprintf("  • %s line %d | %s\n", name, finding.Line, finding.Kind))
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,227 @@
+package lib
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"plandex/api"
+	"plandex/auth"
+	"plandex/fs"
+	"plandex/term"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+	"github.com/plandex/plandex/shared"
+)
+
+// Context is stored on the server, so anyone a plan is shared with can add context that's refreshed on everyone's machine -- a command that's run, a file or directory that's read, a url that's fetched, or a git ref that's diffed. When refreshing, context is only read from this machine if it's trusted: commands must be on this machine's allowlist, which holds the commands the user loaded or explicitly confirmed, and other context must have been loaded by the current user or be on the allowlist. Allowlist entries are keyed by context id and a hash of the context's source.
+var trustedContextsMu sync.Mutex
+
+func getTrustedContextsPath() string {
+	return filepath.Join(fs.HomePlandexDir, "trusted-contexts.json")
+}
+
+func readTrustedContexts() (map[string]string, error) {
+	trusted := map[string]string{}
+
+	bytes, err := os.ReadFile(getTrustedContextsPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return trusted, nil
+		}
+		return nil, fmt.Errorf("error reading trusted contexts: %v", err)
+	}
+
+	err = json.Unmarshal(bytes, &trusted)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling trusted contexts: %v", err)
+	}
+
+	return trusted, nil
+}
+
+func contextSourceHash(context *shared.Context) string {
+	source := strings.Join([]string{string(context.ContextType), context.FilePath, context.Url, context.GitRef, context.Command}, "|")
+	hash := sha256.Sum256([]byte(source))
+	return hex.EncodeToString(hash[:])
+}
+
+// isRefreshedFromMachine is true for context types that are refreshed by reading from this machine or running something on it
+func isRefreshedFromMachine(context *shared.Context) bool {
+	switch context.ContextType {
+	case shared.ContextFileType, shared.ContextDirectoryTreeType, shared.ContextURLType, shared.ContextCommandType:
+		return true
+	}
+	return context.IsGit()
+}
+
+func isTrustedContext(trusted map[string]string, context *shared.Context) bool {
+	if !isRefreshedFromMachine(context) || trusted[context.Id] == contextSourceHash(context) {
+		return true
+	}
+
+	// running a command has more at stake than reading, so commands are trusted per machine even when the current user loaded them
+	if context.ContextType == shared.ContextCommandType {
+		return false
+	}
+
+	return auth.Current != nil && context.OwnerId == auth.Current.UserId
+}
+
+func trustContexts(contexts []*shared.Context) error {
+	if len(contexts) == 0 {
+		return nil
+	}
+
+	trustedContextsMu.Lock()
+	defer trustedContextsMu.Unlock()
+
+	trusted, err := readTrustedContexts()
+	if err != nil {
+		return err
+	}
+
+	for _, context := range contexts {
+		trusted[context.Id] = contextSourceHash(context)
+	}
+
+	bytes, err := json.Marshal(trusted)
+	if err != nil {
+		return fmt.Errorf("error marshalling trusted contexts: %v", err)
+	}
+
+	err = os.WriteFile(getTrustedContextsPath(), bytes, 0600)
+	if err != nil {
+		return fmt.Errorf("error writing trusted contexts: %v", err)
+	}
+
+	return nil
+}
+
+// trustLoadedCommands adds the command contexts for commands the user just loaded to the allowlist
+func trustLoadedCommands(commands []string) error {
+	if len(commands) == 0 {
+		return nil
+	}
+
+	contexts, apiErr := api.Client.ListContext(CurrentPlanId, CurrentBranch)
+	if apiErr != nil {
+		return fmt.Errorf("error retrieving context: %v", apiErr.Msg)
+	}
+
+	loaded := map[string]bool{}
+	for _, command := range commands {
+		loaded[command] = true
+	}
+
+	var toTrust []*shared.Context
+	for _, context := range contexts {
+		if context.ContextType == shared.ContextCommandType && loaded[context.Command] {
+			toTrust = append(toTrust, context)
+		}
+	}
+
+	return trustContexts(toTrust)
+}
+
+// checkLocalContextPath checks a stored file or directory path before it's read on this machine. Along with shared.ValidateContextPath, symlinks are resolved so that a link inside the project can't point the read elsewhere.
+func checkLocalContextPath(filePath string) error {
+	err := shared.ValidateContextPath(filePath)
+	if err != nil {
+		return err
+	}
+
+	root, err := filepath.EvalSymlinks(fs.ProjectRoot)
+	if err != nil {
+		return fmt.Errorf("error resolving project root: %v", err)
+	}
+
+	resolved, err := filepath.EvalSymlinks(filepath.Join(fs.ProjectRoot, filePath))
+	if os.IsNotExist(err) {
+		// missing files are removed from context rather than read
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("error resolving %s: %v", filePath, err)
+	}
+
+	rel, err := filepath.Rel(root, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("%s resolves outside the project root", filePath)
+	}
+
+	return nil
+}
+
+// MustConfirmUntrustedContexts asks before trusting each context on the current branch that's refreshed from this machine but wasn't loaded by the current user (or, for commands, on this machine), so it can be refreshed when context is updated
+func MustConfirmUntrustedContexts() {
+	contexts, apiErr := api.Client.ListContext(CurrentPlanId, CurrentBranch)
+	if apiErr != nil {
+		term.OutputErrorAndExit("Error retrieving context: %v", apiErr.Msg)
+	}
+
+	trusted, err := readTrustedContexts()
+	if err != nil {
+		term.OutputErrorAndExit("%v", err)
+	}
+
+	var confirmed []*shared.Context
+	for _, context := range contexts {
+		if isTrustedContext(trusted, context) {
+			continue
+		}
+
+		// contexts with paths outside the project can't be trusted
+		if (context.ContextType == shared.ContextFileType || context.ContextType == shared.ContextDirectoryTreeType) && checkLocalContextPath(context.FilePath) != nil {
+			continue
+		}
+
+		term.StopSpinner()
+
+		var prompt string
+		if context.ContextType == shared.ContextCommandType {
+			fmt.Printf("⚠️  The command %s wasn't loaded on this machine. It may have been added by a collaborator.\n", color.New(color.Bold, term.ColorHiYellow).Sprint(context.Command))
+			prompt = "Run it to refresh its output?"
+		} else {
+			t, _ := context.TypeAndIcon()
+			fmt.Printf("⚠️  The %s %s was loaded by a collaborator.\n", t, color.New(color.Bold, term.ColorHiYellow).Sprint(context.Name))
+			prompt = "Refresh it from this machine?"
+		}
+
+		ok, err := term.ConfirmYesNo(prompt)
+		if err != nil {
+			term.OutputErrorAndExit("failed to get user input: %s", err)
+		}
+		fmt.Println()
+
+		if ok {
+			confirmed = append(confirmed, context)
+		}
+	}
+
+	err = trustContexts(confirmed)
+	if err != nil {
+		term.OutputErrorAndExit("%v", err)
+	}
+}
+
+func printUntrustedContextsMsg(contexts []*shared.Context) {
+	fmt.Println("⚠️  Skipped refreshing context loaded by a collaborator or, for commands, on another machine:")
+	for _, context := range contexts {
+		_, icon := context.TypeAndIcon()
+		fmt.Printf("  • %s %s\n", icon, context.Name)
+	}
+	fmt.Printf("Run %s to review and refresh it\n\n", color.New(color.Bold, term.ColorHiCyan).Sprint("plandex update"))
+}
+
+func printInvalidPathContextsMsg(contexts []*shared.Context) {
+	fmt.Println("⚠️  Skipped refreshing context with a path outside the project:")
+	for _, context := range contexts {
+		_, icon := context.TypeAndIcon()
+		fmt.Printf("  • %s %s\n", icon, context.FilePath)
+	}
+	fmt.Printf("Remove it with %s\n\n", color.New(color.Bold, term.ColorHiCyan).Sprint("plandex rm"))
+}
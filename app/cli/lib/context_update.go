@@ -33,6 +33,14 @@ func MustCheckOutdatedContext(quiet bool, maybeContexts []*shared.Context) (cont
 
 	term.StopSpinner()
 
+	if len(outdatedRes.UntrustedContexts) > 0 && !quiet {
+		printUntrustedContextsMsg(outdatedRes.UntrustedContexts)
+	}
+
+	if len(outdatedRes.InvalidPathContexts) > 0 && !quiet {
+		printInvalidPathContextsMsg(outdatedRes.InvalidPathContexts)
+	}
+
 	if len(outdatedRes.UpdatedContexts) == 0 && len(outdatedRes.RemovedContexts) == 0 {
 		if !quiet {
 			fmt.Println("✅ Context is up to date")
@@ -65,6 +73,22 @@ func MustCheckOutdatedContext(quiet bool, maybeContexts []*shared.Context) (cont
 			lbl = strconv.Itoa(outdatedRes.NumTrees) + " " + lbl
 			types = append(types, lbl)
 		}
+		if outdatedRes.NumGitDiffs > 0 {
+			lbl := "git diff"
+			if outdatedRes.NumGitDiffs > 1 {
+				lbl = "git diffs"
+			}
+			lbl = strconv.Itoa(outdatedRes.NumGitDiffs) + " " + lbl
+			types = append(types, lbl)
+		}
+		if outdatedRes.NumCommands > 0 {
+			lbl := "command output"
+			if outdatedRes.NumCommands > 1 {
+				lbl = "command outputs"
+			}
+			lbl = strconv.Itoa(outdatedRes.NumCommands) + " " + lbl
+			types = append(types, lbl)
+		}
 
 		var msg string
 		if len(types) <= 2 {
@@ -164,6 +188,11 @@ func MustUpdateContext(maybeUpdateContexts []*shared.Context) {
 
 	fmt.Println("✅ " + updateRes.Msg)
 
+	if updateRes.SecretsSummary != "" {
+		fmt.Println()
+		fmt.Print(updateRes.SecretsSummary)
+	}
+
 }
 
 func UpdateContext(maybeContexts []*shared.Context) (*types.ContextOutdatedResult, error) {
@@ -192,11 +221,15 @@ func checkOutdatedAndMaybeUpdateContext(doUpdate bool, maybeContexts []*shared.C
 	var errs []error
 
 	req := shared.UpdateContextRequest{}
+	// line ranges whose text only moved, which are stored whether or not context is being updated
+	movedReq := shared.UpdateContextRequest{}
 	var updatedContexts []*shared.Context
 	var tokenDiffsById = map[string]int{}
 	var numFiles int
 	var numUrls int
 	var numTrees int
+	var numGitDiffs int
+	var numCommands int
 	var numFilesRemoved int
 	var numTreesRemoved int
 	var mu sync.Mutex
@@ -223,9 +256,35 @@ func checkOutdatedAndMaybeUpdateContext(doUpdate bool, maybeContexts []*shared.C
 		}
 	}
 
+	// bodies are scanned before they're compared so that redacted context isn't always outdated
+	guard, err := newSecretGuard()
+	if err != nil {
+		return nil, err
+	}
+
+	trustedContexts, err := readTrustedContexts()
+	if err != nil {
+		return nil, err
+	}
+	var untrustedContexts []*shared.Context
+	var invalidPathContexts []*shared.Context
+
 	for _, context := range contexts {
 		contextsById[context.Id] = context
 
+		if context.ContextType == shared.ContextFileType || context.ContextType == shared.ContextDirectoryTreeType {
+			if err := checkLocalContextPath(context.FilePath); err != nil {
+				log.Printf("Skipping context %s: %v", context.Id, err)
+				invalidPathContexts = append(invalidPathContexts, context)
+				continue
+			}
+		}
+
+		if !isTrustedContext(trustedContexts, context) {
+			untrustedContexts = append(untrustedContexts, context)
+			continue
+		}
+
 		if context.ContextType == shared.ContextFileType {
 			wg.Add(1)
 			go func(context *shared.Context) {
@@ -248,11 +307,95 @@ func checkOutdatedAndMaybeUpdateContext(doUpdate bool, maybeContexts []*shared.C
 					return
 				}
 
-				hash := sha256.Sum256(fileContent)
+				body := string(fileContent)
+
+				// line range contexts are outdated when the text of their span changes. If it only moved, the new position is stored without counting the context as outdated.
+				if context.LineRange != nil {
+					lineRange, rangeBody, changed := shared.ResolveLineRange(body, context.LineRange, context.Sha)
+					moved := lineRange.Start != context.LineRange.Start || lineRange.End != context.LineRange.End
+
+					if !changed && !moved {
+						return
+					}
+
+					rangeBody = guard.scan(contextScanName(context), context.FilePath, rangeBody)
+					guard.redactLineRange(lineRange)
+
+					// the stored sha is of the redacted text, so a span with secrets is compared after scanning
+					hash := sha256.Sum256([]byte(rangeBody))
+					if !changed || hex.EncodeToString(hash[:]) == context.Sha {
+						if moved {
+							movedReq[context.Id] = &shared.UpdateContextParams{
+								Body:      rangeBody,
+								LineRange: lineRange,
+							}
+						}
+						return
+					}
+
+					numTokens, err := shared.GetNumTokens(rangeBody)
+					if err != nil {
+						errs = append(errs, fmt.Errorf("failed to get the number of tokens in the file %s: %v", context.FilePath, err))
+						return
+					}
+					tokenDiffsById[context.Id] = numTokens - context.NumTokens
+
+					numFiles++
+					updatedContexts = append(updatedContexts, context)
+
+					req[context.Id] = &shared.UpdateContextParams{
+						Body:      rangeBody,
+						LineRange: lineRange,
+					}
+					return
+				}
+
+				// documents are stored as extracted text, so compare the original document's sha and only re-extract when it has changed
+				if shared.IsDocumentFile(context.FilePath) {
+					sourceSha := shared.GetDocumentSha(fileContent)
+					if sourceSha == context.SourceSha {
+						return
+					}
+
+					body, err = shared.DocumentToText(shared.GetDocumentType(context.FilePath), fileContent)
+					if err != nil {
+						errs = append(errs, fmt.Errorf("failed to extract text from %s: %v", context.FilePath, err))
+						return
+					}
+					body = guard.scan(contextScanName(context), context.FilePath, body)
+
+					numTokens, err := shared.GetNumTokens(body)
+					if err != nil {
+						errs = append(errs, fmt.Errorf("failed to get the number of tokens in the file %s: %v", context.FilePath, err))
+						return
+					}
+					tokenDiffsById[context.Id] = numTokens - context.NumTokens
+
+					numFiles++
+					updatedContexts = append(updatedContexts, context)
+
+					req[context.Id] = &shared.UpdateContextParams{
+						Body:      body,
+						SourceSha: sourceSha,
+					}
+					return
+				}
+
+				// notebooks are stored as a text view, so compare against that rather than the raw file
+				if shared.IsNotebookFile(context.FilePath) {
+					body, err = shared.NotebookToText(body)
+					if err != nil {
+						errs = append(errs, fmt.Errorf("failed to read the notebook %s: %v", context.FilePath, err))
+						return
+					}
+				}
+
+				body = guard.scan(contextScanName(context), context.FilePath, body)
+
+				hash := sha256.Sum256([]byte(body))
 				sha := hex.EncodeToString(hash[:])
 
 				if sha != context.Sha {
-					body := string(fileContent)
 
 					numTokens, err := shared.GetNumTokens(body)
 					if err != nil {
@@ -335,24 +478,102 @@ func checkOutdatedAndMaybeUpdateContext(doUpdate bool, maybeContexts []*shared.C
 				}
 			}(context)
 
-		} else if context.ContextType == shared.ContextURLType {
+		} else if context.IsGit() {
 			wg.Add(1)
 			go func(context *shared.Context) {
 				defer wg.Done()
-				body, err := url.FetchURLContent(context.Url)
+				body, err := getGitContextBody(context.ContextType, context.GitRef)
 
 				mu.Lock()
 				defer mu.Unlock()
 
 				if err != nil {
-					errs = append(errs, fmt.Errorf("failed to fetch the URL %s: %v", context.Url, err))
+					errs = append(errs, fmt.Errorf("failed to get %s: %v", context.Name, err))
+					return
+				}
+
+				body = guard.scan(contextScanName(context), "", body)
+
+				hash := sha256.Sum256([]byte(body))
+				sha := hex.EncodeToString(hash[:])
+
+				if sha != context.Sha {
+					numTokens, err := shared.GetNumTokens(body)
+					if err != nil {
+						errs = append(errs, fmt.Errorf("failed to get the number of tokens in %s: %v", context.Name, err))
+						return
+					}
+					tokenDiffsById[context.Id] = numTokens - context.NumTokens
+
+					numGitDiffs++
+					updatedContexts = append(updatedContexts, context)
+					req[context.Id] = &shared.UpdateContextParams{
+						Body: body,
+					}
+				}
+			}(context)
+
+		} else if context.ContextType == shared.ContextCommandType {
+			wg.Add(1)
+			go func(context *shared.Context) {
+				defer wg.Done()
+				body, err := getCommandContextBody(context.Command)
+
+				mu.Lock()
+				defer mu.Unlock()
+
+				if err != nil {
+					errs = append(errs, err)
 					return
 				}
 
+				body = guard.scan(contextScanName(context), "", body)
+
 				hash := sha256.Sum256([]byte(body))
 				sha := hex.EncodeToString(hash[:])
 
 				if sha != context.Sha {
+					numTokens, err := shared.GetNumTokens(body)
+					if err != nil {
+						errs = append(errs, fmt.Errorf("failed to get the number of tokens in the output of '%s': %v", context.Command, err))
+						return
+					}
+					tokenDiffsById[context.Id] = numTokens - context.NumTokens
+
+					numCommands++
+					updatedContexts = append(updatedContexts, context)
+					req[context.Id] = &shared.UpdateContextParams{
+						Body: body,
+					}
+				}
+			}(context)
+
+		} else if context.ContextType == shared.ContextURLType {
+			wg.Add(1)
+			go func(context *shared.Context) {
+				defer wg.Done()
+				body, sourceSha, err := url.FetchURLContentAndSha(context.Url)
+
+				mu.Lock()
+				defer mu.Unlock()
+
+				if err != nil {
+					errs = append(errs, fmt.Errorf("failed to fetch the URL %s: %v", context.Url, err))
+					return
+				}
+
+				body = guard.scan(contextScanName(context), "", body)
+
+				// for documents, the original document's sha is compared rather than the extracted text's
+				var outdated bool
+				if sourceSha != "" && context.SourceSha != "" {
+					outdated = sourceSha != context.SourceSha
+				} else {
+					hash := sha256.Sum256([]byte(body))
+					outdated = hex.EncodeToString(hash[:]) != context.Sha
+				}
+
+				if outdated {
 					numTokens, err := shared.GetNumTokens(body)
 					if err != nil {
 						errs = append(errs, fmt.Errorf("failed to get the number of tokens in the file %s: %v", context.FilePath, err))
@@ -363,7 +584,8 @@ func checkOutdatedAndMaybeUpdateContext(doUpdate bool, maybeContexts []*shared.C
 					numUrls++
 					updatedContexts = append(updatedContexts, context)
 					req[context.Id] = &shared.UpdateContextParams{
-						Body: body,
+						Body:      body,
+						SourceSha: sourceSha,
 					}
 				}
 
@@ -377,30 +599,50 @@ func checkOutdatedAndMaybeUpdateContext(doUpdate bool, maybeContexts []*shared.C
 		return nil, fmt.Errorf("failed to check context outdated: %v", errs)
 	}
 
+	if len(movedReq) > 0 {
+		_, apiErr := api.Client.UpdateContext(CurrentPlanId, CurrentBranch, movedReq)
+		if apiErr != nil {
+			return nil, fmt.Errorf("failed to update moved line ranges: %v", apiErr)
+		}
+	}
+
 	var msg string
 	var hasConflicts bool
+	var secretsSummary string
 
 	if len(req) == 0 && len(deleteIds) == 0 {
 		log.Println("return context is up to date res")
 		return &types.ContextOutdatedResult{
-			Msg: "Context is up to date",
+			Msg:                 "Context is up to date",
+			UntrustedContexts:   untrustedContexts,
+			InvalidPathContexts: invalidPathContexts,
 		}, nil
 	} else if doUpdate {
+		updatedNames := map[string]bool{}
+		for id := range req {
+			updatedNames[contextScanName(contextsById[id])] = true
+		}
+		guard.retain(updatedNames)
+		err := guard.resolve()
+		if err != nil {
+			return nil, err
+		}
+		secretsSummary = guard.summary()
+
 		filesToLoad := map[string]string{}
 		for id := range req {
 			context := contextsById[id]
-			if context.ContextType == shared.ContextFileType {
+			if context.ContextType == shared.ContextFileType && context.LineRange == nil {
 				filesToLoad[context.FilePath] = context.Body
 			}
 		}
 		for id := range deleteIds {
 			context := contextsById[id]
-			if context.ContextType == shared.ContextFileType {
+			if context.ContextType == shared.ContextFileType && context.LineRange == nil {
 				filesToLoad[context.FilePath] = ""
 			}
 		}
 
-		var err error
 		hasConflicts, err = checkContextConflicts(filesToLoad)
 		if err != nil {
 			return nil, fmt.Errorf("failed to check context conflicts: %v", err)
@@ -449,8 +691,14 @@ func checkOutdatedAndMaybeUpdateContext(doUpdate bool, maybeContexts []*shared.C
 		NumFiles:        numFiles,
 		NumUrls:         numUrls,
 		NumTrees:        numTrees,
+		NumGitDiffs:     numGitDiffs,
+		NumCommands:     numCommands,
 		NumFilesRemoved: numFilesRemoved,
 		NumTreesRemoved: numTreesRemoved,
+		SecretsSummary:  secretsSummary,
+
+		UntrustedContexts:   untrustedContexts,
+		InvalidPathContexts: invalidPathContexts,
 	}, nil
 }
 
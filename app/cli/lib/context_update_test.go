@@ -0,0 +1,177 @@
+package lib
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"plandex/api"
+	"plandex/auth"
+	"plandex/fs"
+	"plandex/types"
+	"testing"
+
+	"github.com/plandex/plandex/shared"
+)
+
+// contextUpdateApi records context updates in place of the server
+type contextUpdateApi struct {
+	types.ApiClient
+	updates []shared.UpdateContextRequest
+}
+
+func (a *contextUpdateApi) UpdateContext(planId, branch string, req shared.UpdateContextRequest) (*shared.UpdateContextResponse, *shared.ApiError) {
+	a.updates = append(a.updates, req)
+	return &shared.UpdateContextResponse{}, nil
+}
+
+const testUserId = "user"
+
+// withTestProject runs the test from a temp project root with a fake api client, signed in as testUserId
+func withTestProject(t *testing.T) *contextUpdateApi {
+	root := t.TempDir()
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Chdir(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := &contextUpdateApi{}
+	prevClient, prevAuth, prevRoot, prevPlandexDir, prevHomeDir := api.Client, auth.Current, fs.ProjectRoot, fs.PlandexDir, fs.HomePlandexDir
+	api.Client, fs.ProjectRoot, fs.PlandexDir, fs.HomePlandexDir = client, root, "", t.TempDir()
+	auth.Current = &types.ClientAuth{ClientAccount: types.ClientAccount{UserId: testUserId}}
+
+	t.Cleanup(func() {
+		os.Chdir(cwd)
+		api.Client, auth.Current, fs.ProjectRoot, fs.PlandexDir, fs.HomePlandexDir = prevClient, prevAuth, prevRoot, prevPlandexDir, prevHomeDir
+	})
+
+	return client
+}
+
+func TestCheckOutdatedContextMovedLineRange(t *testing.T) {
+	client := withTestProject(t)
+
+	content := "a\nb\nfunc x() {\n\treturn 1\n}\nc"
+	err := os.WriteFile("main.go", []byte(content), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lineRange, body, err := shared.NewLineRange(content, 3, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := sha256.Sum256([]byte(body))
+
+	context := &shared.Context{
+		Id:          "ctx",
+		OwnerId:     testUserId,
+		ContextType: shared.ContextFileType,
+		Name:        "main.go:3-5",
+		FilePath:    "main.go",
+		LineRange:   lineRange,
+		Sha:         hex.EncodeToString(hash[:]),
+	}
+
+	res, err := CheckOutdatedContext([]*shared.Context{context})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.UpdatedContexts) != 0 || len(client.updates) != 0 {
+		t.Fatalf("expected span in place to be up to date, got %d updated, %d updates sent", len(res.UpdatedContexts), len(client.updates))
+	}
+
+	// lines added above the span move it without changing its text
+	err = os.WriteFile("main.go", []byte("new\nlines\n"+content), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err = CheckOutdatedContext([]*shared.Context{context})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.UpdatedContexts) != 0 || res.NumFiles != 0 {
+		t.Errorf("expected moved span not to be reported as outdated, got %d updated", len(res.UpdatedContexts))
+	}
+
+	if len(client.updates) != 1 {
+		t.Fatalf("expected the moved span's position to be stored, got %d updates", len(client.updates))
+	}
+	params := client.updates[0]["ctx"]
+	if params == nil || params.LineRange == nil || params.LineRange.Start != 5 || params.LineRange.End != 7 || params.Body != body {
+		t.Errorf("expected position-only update to 5-7 with the same body, got %+v", params)
+	}
+}
+
+func TestCheckOutdatedContextUntrusted(t *testing.T) {
+	client := withTestProject(t)
+
+	secret := filepath.Join(t.TempDir(), "id_rsa")
+	err := os.WriteFile(secret, []byte("secret"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile("main.go", []byte("func main() {}"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Symlink(secret, "link")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outside := []*shared.Context{
+		{Id: "abs", OwnerId: testUserId, ContextType: shared.ContextFileType, FilePath: secret},
+		{Id: "parent", OwnerId: testUserId, ContextType: shared.ContextFileType, FilePath: "../id_rsa"},
+		{Id: "link", OwnerId: testUserId, ContextType: shared.ContextFileType, FilePath: "link"},
+		{Id: "tree", OwnerId: testUserId, ContextType: shared.ContextDirectoryTreeType, FilePath: ".."},
+	}
+	// stale shas, so they'd be updated if they were trusted
+	untrusted := []*shared.Context{
+		{Id: "collab-file", OwnerId: "collaborator", ContextType: shared.ContextFileType, FilePath: "main.go", Sha: "stale"},
+		{Id: "collab-git", OwnerId: "collaborator", ContextType: shared.ContextGitCommitType, GitRef: "--output=x", Sha: "stale"},
+		{Id: "own-command", OwnerId: testUserId, ContextType: shared.ContextCommandType, Command: "touch ran", Sha: "stale"},
+	}
+
+	res, err := CheckOutdatedContext(append(append([]*shared.Context{}, outside...), untrusted...))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(res.InvalidPathContexts) != len(outside) {
+		t.Errorf("expected %d contexts with paths outside the project, got %d", len(outside), len(res.InvalidPathContexts))
+	}
+	if len(res.UntrustedContexts) != len(untrusted) {
+		t.Errorf("expected %d untrusted contexts, got %d", len(untrusted), len(res.UntrustedContexts))
+	}
+	if len(res.UpdatedContexts) != 0 || len(client.updates) != 0 {
+		t.Errorf("expected nothing to be refreshed, got %d updated", len(res.UpdatedContexts))
+	}
+	if _, err := os.Stat("ran"); err == nil {
+		t.Errorf("expected untrusted command not to be run")
+	}
+
+	// once confirmed on this machine, a collaborator's context is trusted until its source changes
+	err = trustContexts(untrusted[:1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	trusted, err := readTrustedContexts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isTrustedContext(trusted, untrusted[0]) {
+		t.Errorf("expected confirmed context to be trusted")
+	}
+	changed := *untrusted[0]
+	changed.FilePath = "other.go"
+	if isTrustedContext(trusted, &changed) {
+		t.Errorf("expected confirmed context with a changed path not to be trusted")
+	}
+}
@@ -0,0 +1,237 @@
+package lib
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/plandex/plandex/shared"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+type ConvoExportFormat string
+
+const (
+	ConvoExportFormatMarkdown ConvoExportFormat = "md"
+	ConvoExportFormatHTML     ConvoExportFormat = "html"
+	ConvoExportFormatJSON     ConvoExportFormat = "json"
+)
+
+type ConvoExport struct {
+	Plan        string                `json:"plan"`
+	Branch      string                `json:"branch"`
+	ExportedAt  time.Time             `json:"exportedAt"`
+	TotalTokens int                   `json:"totalTokens"`
+	Messages    []*ConvoExportMessage `json:"messages"`
+}
+
+type ConvoExportMessage struct {
+	Num           int       `json:"num"`
+	Role          string    `json:"role"`
+	Tokens        int       `json:"tokens"`
+	CreatedAt     time.Time `json:"createdAt"`
+	Message       string    `json:"message"`
+	Stopped       bool      `json:"stopped,omitempty"`
+	IsChat        bool      `json:"isChat,omitempty"`
+	PromptVersion string    `json:"promptVersion,omitempty"`
+
+	// from the reply's description
+	CommitMsg string   `json:"commitMsg,omitempty"`
+	Files     []string `json:"files,omitempty"`
+	DidBuild  bool     `json:"didBuild,omitempty"`
+	Error     string   `json:"error,omitempty"`
+
+	// Diff is the diff of the files the reply built, if diffs were included
+	Diff string `json:"diff,omitempty"`
+}
+
+// NewConvoExport combines messages with their descriptions and, if diffsByMessageId isn't nil, the diffs each reply built
+func NewConvoExport(planName, branch string, convo []*shared.ConvoMessage, descriptions []*shared.ConvoMessageDescription, diffsByMessageId map[string]string) *ConvoExport {
+	descByMessageId := map[string]*shared.ConvoMessageDescription{}
+	for _, desc := range descriptions {
+		descByMessageId[desc.ConvoMessageId] = desc
+	}
+
+	export := &ConvoExport{
+		Plan:       planName,
+		Branch:     branch,
+		ExportedAt: time.Now().UTC(),
+	}
+
+	for _, msg := range convo {
+		exportMsg := &ConvoExportMessage{
+			Num:           msg.Num,
+			Role:          msg.Role,
+			Tokens:        msg.Tokens,
+			CreatedAt:     msg.CreatedAt,
+			Message:       msg.Message,
+			Stopped:       msg.Stopped,
+			IsChat:        msg.IsChat,
+			PromptVersion: msg.PromptVersion,
+			Diff:          diffsByMessageId[msg.Id],
+		}
+
+		if desc, ok := descByMessageId[msg.Id]; ok {
+			exportMsg.CommitMsg = desc.CommitMsg
+			exportMsg.Files = desc.Files
+			exportMsg.DidBuild = desc.DidBuild
+			exportMsg.Error = desc.Error
+		}
+
+		export.TotalTokens += msg.Tokens
+		export.Messages = append(export.Messages, exportMsg)
+	}
+
+	return export
+}
+
+func (export *ConvoExport) Render(format ConvoExportFormat) (string, error) {
+	switch format {
+	case ConvoExportFormatMarkdown:
+		return export.Markdown(), nil
+	case ConvoExportFormatHTML:
+		return export.HTML()
+	case ConvoExportFormatJSON:
+		bytes, err := json.MarshalIndent(export, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("error marshalling convo: %v", err)
+		}
+		return string(bytes), nil
+	}
+
+	return "", fmt.Errorf("unsupported format '%s' -- use md, html, or json", format)
+}
+
+func (export *ConvoExport) Markdown() string {
+	var b strings.Builder
+
+	b.WriteString(fmt.Sprintf("# %s\n\n", export.Plan))
+	b.WriteString(fmt.Sprintf("Branch `%s` · %d messages · %d tokens · exported %s\n\n", export.Branch, len(export.Messages), export.TotalTokens, export.ExportedAt.Format(time.RFC1123)))
+
+	for _, msg := range export.Messages {
+		author := msg.Role
+		switch msg.Role {
+		case "user":
+			author = "💬 User"
+		case "assistant":
+			author = "🤖 Plandex"
+		}
+
+		b.WriteString("---\n\n")
+		b.WriteString(fmt.Sprintf("### %d · %s\n\n", msg.Num, author))
+
+		meta := []string{msg.CreatedAt.UTC().Format(time.RFC1123), fmt.Sprintf("%d tokens", msg.Tokens)}
+		if msg.IsChat {
+			meta = append(meta, "chat")
+		}
+		if msg.PromptVersion != "" {
+			meta = append(meta, "prompt "+msg.PromptVersion)
+		}
+		b.WriteString("_" + strings.Join(meta, " · ") + "_\n\n")
+
+		b.WriteString(closeOpenFence(strings.TrimSpace(msg.Message)) + "\n\n")
+
+		if msg.Stopped {
+			b.WriteString("🛑 _Stopped early_\n\n")
+		}
+
+		if len(msg.Files) > 0 {
+			label := "Files"
+			if msg.DidBuild {
+				label = "Files built"
+			}
+			b.WriteString(fmt.Sprintf("**%s:** ", label))
+			for i, file := range msg.Files {
+				if i > 0 {
+					b.WriteString(", ")
+				}
+				b.WriteString("`" + file + "`")
+			}
+			b.WriteString("\n\n")
+		}
+
+		if msg.Error != "" {
+			b.WriteString(fmt.Sprintf("🚨 **Error:** %s\n\n", msg.Error))
+		}
+
+		if strings.TrimSpace(msg.Diff) != "" {
+			fence := codeFence(msg.Diff)
+			b.WriteString(fence + "diff\n" + strings.TrimRight(msg.Diff, "\n") + "\n" + fence + "\n\n")
+		}
+	}
+
+	return b.String()
+}
+
+func (export *ConvoExport) HTML() (string, error) {
+	md := goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+	var body bytes.Buffer
+	err := md.Convert([]byte(export.Markdown()), &body)
+	if err != nil {
+		return "", fmt.Errorf("error converting convo to html: %v", err)
+	}
+
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>%s</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; max-width: 900px; margin: 2rem auto; padding: 0 1rem; line-height: 1.5; color: #1f2328; }
+pre { background: #f6f8fa; padding: 1rem; overflow-x: auto; border-radius: 6px; }
+code { font-family: ui-monospace, SFMono-Regular, Menlo, monospace; font-size: 0.9em; }
+hr { border: none; border-top: 1px solid #d0d7de; margin: 2rem 0; }
+</style>
+</head>
+<body>
+%s</body>
+</html>
+`, html.EscapeString(export.Plan), body.String()), nil
+}
+
+// codeFence returns a fence longer than any run of backticks in s so the fenced block can't be closed early
+func codeFence(s string) string {
+	longest, run := 0, 0
+	for _, c := range s {
+		if c == '`' {
+			run++
+			if run > longest {
+				longest = run
+			}
+		} else {
+			run = 0
+		}
+	}
+
+	if longest < 3 {
+		return "```"
+	}
+	return strings.Repeat("`", longest+1)
+}
+
+// closeOpenFence closes a code block left open, e.g. by a reply that was stopped early, so it doesn't swallow the rest of the export
+func closeOpenFence(s string) string {
+	var open string
+	for _, line := range strings.Split(s, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "```") {
+			continue
+		}
+		fence := trimmed[:len(trimmed)-len(strings.TrimLeft(trimmed, "`"))]
+		if open == "" {
+			open = fence
+		} else if strings.TrimRight(trimmed, "`") == "" && len(fence) >= len(open) {
+			open = ""
+		}
+	}
+
+	if open != "" {
+		return s + "\n" + open
+	}
+	return s
+}
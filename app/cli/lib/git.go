@@ -233,3 +233,63 @@ func parseConflictFiles(gitOutput string) []string {
 	}
 	return conflictFiles
 }
+
+// GitDiff returns the diff of the working tree against the point where the current branch diverged from base, or against HEAD if base is empty
+func GitDiff(base string) (string, error) {
+	gitMutex.Lock()
+	defer gitMutex.Unlock()
+
+	ref := "HEAD"
+	if base != "" {
+		res, err := exec.Command("git", "merge-base", "--end-of-options", base, "HEAD").CombinedOutput()
+		if err != nil {
+			return "", fmt.Errorf("error getting merge base for %s | err: %v, output: %s", base, err, string(res))
+		}
+		ref = strings.TrimSpace(string(res))
+	}
+
+	res, err := exec.Command("git", "diff", "--no-color", "--end-of-options", ref).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("error getting git diff against %s | err: %v, output: %s", ref, err, string(res))
+	}
+
+	return string(res), nil
+}
+
+func GitDiffStaged() (string, error) {
+	gitMutex.Lock()
+	defer gitMutex.Unlock()
+
+	res, err := exec.Command("git", "diff", "--no-color", "--cached").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("error getting staged git diff | err: %v, output: %s", err, string(res))
+	}
+
+	return string(res), nil
+}
+
+// GitResolveCommit resolves a ref like a branch, tag, or HEAD~2 to the full sha of the commit it points to now, so a commit context keeps showing the same commit after the ref moves
+func GitResolveCommit(ref string) (string, error) {
+	gitMutex.Lock()
+	defer gitMutex.Unlock()
+
+	res, err := exec.Command("git", "rev-parse", "--verify", "--quiet", "--end-of-options", ref+"^{commit}").Output()
+	if err != nil {
+		return "", fmt.Errorf("%s isn't a commit in this repository", ref)
+	}
+
+	return strings.TrimSpace(string(res)), nil
+}
+
+// GitShowCommit returns a commit's message and diff
+func GitShowCommit(ref string) (string, error) {
+	gitMutex.Lock()
+	defer gitMutex.Unlock()
+
+	res, err := exec.Command("git", "show", "--no-color", "--format=commit %H%nAuthor: %an <%ae>%nDate: %ad%n%n%B", "--end-of-options", ref).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("error showing git commit %s | err: %v, output: %s", ref, err, string(res))
+	}
+
+	return string(res), nil
+}
@@ -0,0 +1,72 @@
+package lib
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"plandex/api"
+	"plandex/fs"
+	"strings"
+
+	"github.com/plandex/plandex/shared"
+)
+
+// instructions.md in the project's plandex dir holds standing instructions, like coding standards, that are included in the planner, builder, and reviewer prompts of every plan in the project
+const InstructionsFile = "instructions.md"
+
+func GetProjectInstructions() (string, error) {
+	if fs.PlandexDir == "" {
+		return "", nil
+	}
+
+	bytes, err := os.ReadFile(filepath.Join(fs.PlandexDir, InstructionsFile))
+
+	if os.IsNotExist(err) {
+		return "", nil
+	} else if err != nil {
+		return "", fmt.Errorf("error reading %s: %v", InstructionsFile, err)
+	}
+
+	return strings.TrimSpace(string(bytes)), nil
+}
+
+type Instructions struct {
+	Project       string
+	ProjectTokens int
+	Org           string
+	OrgTokens     int
+}
+
+// GetInstructions returns the project's instructions and the org's instructions from its default settings, with their token counts
+func GetInstructions() (*Instructions, error) {
+	project, err := GetProjectInstructions()
+	if err != nil {
+		return nil, err
+	}
+
+	settings, apiErr := api.Client.GetOrgDefaultSettings()
+	if apiErr != nil {
+		return nil, fmt.Errorf("error getting org default settings: %v", apiErr.Msg)
+	}
+
+	res := &Instructions{
+		Project: project,
+		Org:     strings.TrimSpace(settings.Instructions),
+	}
+
+	if res.Project != "" {
+		res.ProjectTokens, err = shared.GetNumTokens(res.Project)
+		if err != nil {
+			return nil, fmt.Errorf("error getting num tokens for project instructions: %v", err)
+		}
+	}
+
+	if res.Org != "" {
+		res.OrgTokens, err = shared.GetNumTokens(res.Org)
+		if err != nil {
+			return nil, fmt.Errorf("error getting num tokens for org instructions: %v", err)
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,46 @@
+package lib
+
+import (
+	"fmt"
+	"plandex/term"
+
+	"github.com/fatih/color"
+	"github.com/plandex/plandex/shared"
+)
+
+func DisplayReview(review *shared.PlanReview) {
+	color.New(color.Bold, term.ColorHiCyan).Println("🔎 Review of pending changes")
+	fmt.Println()
+
+	if review.Summary != "" {
+		fmt.Println(review.Summary)
+		fmt.Println()
+	}
+
+	if len(review.Findings) == 0 {
+		fmt.Println("✅ No issues found")
+		return
+	}
+
+	for _, finding := range review.Findings {
+		location := finding.Path
+		if finding.Line > 0 {
+			location = fmt.Sprintf("%s:%d", finding.Path, finding.Line)
+		}
+
+		severityColor := term.ColorHiYellow
+		switch finding.Severity {
+		case shared.ReviewSeverityHigh:
+			severityColor = term.ColorHiRed
+		case shared.ReviewSeverityMedium:
+			severityColor = term.ColorHiMagenta
+		}
+
+		fmt.Printf("%s %s %s\n",
+			finding.Severity.Icon(),
+			color.New(color.Bold, severityColor).Sprint(finding.Severity),
+			color.New(color.Bold).Sprint(location),
+		)
+		fmt.Printf("   %s\n\n", finding.Message)
+	}
+}
@@ -0,0 +1,96 @@
+package lib
+
+import (
+	"fmt"
+	"os"
+	"plandex/format"
+	"plandex/term"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/plandex/plandex/shared"
+)
+
+func DisplayReviewState(reviewState *shared.PlanReviewState, planResult *shared.PlanResult) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetAutoWrapText(false)
+	table.SetHeader([]string{"Reviewer", "Requested By", "Requested", "Status"})
+
+	for _, request := range reviewState.Requests {
+		status := "✅ approved"
+		unapproved := reviewState.UnapprovedPaths(request.ReviewerId, planResult)
+		if len(unapproved) > 0 {
+			suffix := "s"
+			if len(unapproved) == 1 {
+				suffix = ""
+			}
+			status = fmt.Sprintf("⏳ %d file%s left", len(unapproved), suffix)
+		}
+
+		table.Append([]string{
+			fmt.Sprintf("%s <%s>", request.ReviewerName, request.ReviewerEmail),
+			request.RequestedByName,
+			format.Time(request.CreatedAt),
+			status,
+		})
+	}
+
+	table.Render()
+
+	if len(reviewState.Feedback) == 0 {
+		return
+	}
+
+	fmt.Println()
+
+	for _, path := range planResult.SortedPaths {
+		feedback := reviewState.FeedbackForPath(path)
+		if len(feedback) == 0 {
+			continue
+		}
+
+		color.New(color.Bold, term.ColorHiCyan).Println(path)
+
+		for _, f := range feedback {
+			icon := "💬"
+			verb := "commented"
+			if f.Approved {
+				icon = "✅"
+				verb = "approved"
+			}
+
+			target := "the file"
+			if f.ReplacementId != "" {
+				target = "a change"
+				if num := replacementNum(planResult, path, f.ReplacementId); num > 0 {
+					target = fmt.Sprintf("change %d", num)
+				}
+			}
+
+			fmt.Printf("  %s %s %s %s | %s\n", icon, color.New(color.Bold).Sprint(f.UserName), verb, target, format.Time(f.CreatedAt))
+			if f.Comment != "" {
+				fmt.Println("     " + strings.ReplaceAll(f.Comment, "\n", "\n     "))
+			}
+		}
+		fmt.Println()
+	}
+}
+
+// replacementNum is the 1-based position of a replacement among a path's changes, matching the numbering in `plandex changes`
+func replacementNum(planResult *shared.PlanResult, path, replacementId string) int {
+	num := 0
+	for i, result := range planResult.FileResultsByPath[path] {
+		if i == 0 && len(result.Replacements) == 0 && result.Content != "" {
+			num++
+			continue
+		}
+		for _, rep := range result.Replacements {
+			num++
+			if rep.Id == replacementId {
+				return num
+			}
+		}
+	}
+	return 0
+}
@@ -0,0 +1,519 @@
+package lib
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"plandex/api"
+	"plandex/fs"
+	"plandex/term"
+	"plandex/types"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/fsnotify/fsnotify"
+	"github.com/plandex/plandex/shared"
+)
+
+const watchStatusInterval = 2 * time.Second
+
+// status is still checked occasionally while streaming in case the stream stalls
+const watchStreamingStatusInterval = 30 * time.Second
+
+type WatchParams struct {
+	// Debounce is how long to wait after the last file change before syncing context
+	Debounce time.Duration
+	// Status streams status changes for the current branch
+	Status bool
+	// Apply applies the plan's changes whenever the current branch finishes
+	Apply bool
+	// AutoConfirm applies without asking first
+	AutoConfirm bool
+	// BlockOnReview skips applying while the latest review has high-severity issues
+	BlockOnReview bool
+}
+
+type contextWatcher struct {
+	fsw     *fsnotify.Watcher
+	dirs    map[string]bool
+	pending map[string]bool
+}
+
+// Watch keeps loaded context in sync with the project until it's interrupted. Changes to loaded files and directory trees are pushed as context updates, and new files that match a directory or glob they were loaded through are loaded too.
+func Watch(params WatchParams) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		term.OutputErrorAndExit("Error starting file watcher: %v", err)
+	}
+	defer fsw.Close()
+
+	w := &contextWatcher{
+		fsw:     fsw,
+		dirs:    map[string]bool{},
+		pending: map[string]bool{},
+	}
+
+	contexts, apiErr := api.Client.ListContext(CurrentPlanId, CurrentBranch)
+	if apiErr != nil {
+		term.OutputErrorAndExit("Error getting context: %v", apiErr)
+	}
+
+	err = w.watchContexts(contexts)
+	if err != nil {
+		term.OutputErrorAndExit("Error watching context: %v", err)
+	}
+
+	var apiKeys map[string]string
+	if params.Apply {
+		apiKeys = MustVerifyApiKeysSilent()
+	}
+
+	var statusCh <-chan time.Time
+	var lastStatus shared.PlanStatus
+	if params.Status || params.Apply {
+		ticker := time.NewTicker(watchStatusInterval)
+		defer ticker.Stop()
+		statusCh = ticker.C
+
+		lastStatus, err = getBranchStatus()
+		if err != nil {
+			term.OutputErrorAndExit("Error getting plan status: %v", err)
+		}
+	}
+
+	// while the plan is active, its stream tells us when it's done, so status is only polled to catch it starting
+	streamDoneCh := make(chan struct{}, 1)
+	streaming := false
+	var lastChecked time.Time
+
+	checkStatus := func() {
+		lastChecked = time.Now()
+		status, err := getBranchStatus()
+		if err != nil {
+			log.Printf("Error getting plan status: %v\n", err)
+			return
+		}
+
+		if !streaming && isActiveStatus(status) {
+			streaming = connectWatchStream(streamDoneCh)
+		}
+
+		if status == lastStatus {
+			return
+		}
+
+		fmt.Println()
+		fmt.Printf("📋 Plan status changed from %s to %s\n", lastStatus, color.New(color.Bold).Sprint(status))
+		lastStatus = status
+
+		if params.Apply && status == shared.PlanStatusFinished {
+			fmt.Println()
+			err := ApplyPlanUnattended(CurrentPlanId, CurrentBranch, apiKeys, params.AutoConfirm, params.BlockOnReview)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "🚨 Error applying changes: %v\n", err)
+			}
+		}
+	}
+
+	if isActiveStatus(lastStatus) {
+		streaming = connectWatchStream(streamDoneCh)
+	}
+
+	fmt.Printf("👀 Watching %d director%s for changes to context on branch %s\n", len(w.dirs), pluralY(len(w.dirs)), color.New(color.Bold, term.ColorHiCyan).Sprint(CurrentBranch))
+	if lastStatus != "" {
+		fmt.Printf("📋 Plan status is %s\n", color.New(color.Bold).Sprint(lastStatus))
+	}
+	fmt.Println("Press ctrl+c to stop")
+
+	var debounce *time.Timer
+	var debounceCh <-chan time.Time
+
+	for {
+		select {
+		case event, ok := <-fsw.Events:
+			if !ok {
+				return
+			}
+			if !w.onEvent(event) {
+				continue
+			}
+			if debounce == nil {
+				debounce = time.NewTimer(params.Debounce)
+			} else {
+				if !debounce.Stop() {
+					select {
+					case <-debounce.C:
+					default:
+					}
+				}
+				debounce.Reset(params.Debounce)
+			}
+			debounceCh = debounce.C
+
+		case err, ok := <-fsw.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("File watcher error: %v\n", err)
+
+		case <-debounceCh:
+			debounceCh = nil
+			w.sync()
+
+		case <-statusCh:
+			if streaming && time.Since(lastChecked) < watchStreamingStatusInterval {
+				continue
+			}
+			checkStatus()
+
+		case <-streamDoneCh:
+			streaming = false
+			checkStatus()
+		}
+	}
+}
+
+func isActiveStatus(status shared.PlanStatus) bool {
+	switch status {
+	case shared.PlanStatusReplying, shared.PlanStatusDescribing, shared.PlanStatusBuilding, shared.PlanStatusMissingFile:
+		return true
+	}
+	return false
+}
+
+// connectWatchStream connects to the current branch's stream, signalling done once when the plan finishes, errors, or is stopped, or when the stream closes. It returns false if the plan couldn't be connected to, in which case status keeps being polled.
+func connectWatchStream(done chan<- struct{}) bool {
+	var once sync.Once
+	signal := func() {
+		once.Do(func() { done <- struct{}{} })
+	}
+
+	isDone := func(msg *shared.StreamMessage) bool {
+		switch msg.Type {
+		case shared.StreamMessageFinished, shared.StreamMessageError, shared.StreamMessageAborted:
+			return true
+		case shared.StreamMessageMulti:
+			for _, m := range msg.StreamMessages {
+				if m.Type == shared.StreamMessageFinished || m.Type == shared.StreamMessageError || m.Type == shared.StreamMessageAborted {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	apiErr := api.Client.ConnectPlan(CurrentPlanId, CurrentBranch, func(params types.OnStreamPlanParams) {
+		if params.Err != nil || params.Msg == nil || isDone(params.Msg) {
+			signal()
+		}
+	})
+
+	if apiErr != nil {
+		log.Printf("Error connecting to plan stream: %v\n", apiErr.Msg)
+		return false
+	}
+
+	return true
+}
+
+func getBranchStatus() (shared.PlanStatus, error) {
+	branches, apiErr := api.Client.ListBranches(CurrentPlanId)
+	if apiErr != nil {
+		return "", fmt.Errorf("error getting branches: %v", apiErr.Msg)
+	}
+
+	for _, branch := range branches {
+		if branch.Name == CurrentBranch {
+			return branch.Status, nil
+		}
+	}
+
+	return "", fmt.Errorf("branch %s not found", CurrentBranch)
+}
+
+// watchContexts adds the directories of loaded files, plus every directory under loaded directory trees and the base of each pattern files were loaded through
+func (w *contextWatcher) watchContexts(contexts []*shared.Context) error {
+	for _, context := range contexts {
+		var err error
+		switch context.ContextType {
+		case shared.ContextFileType, shared.ContextImageType:
+			err = w.addDir(filepath.Dir(context.FilePath))
+			if err == nil && context.LoadedFrom != "" {
+				err = w.addTree(patternBaseDir(context.LoadedFrom))
+			}
+		case shared.ContextDirectoryTreeType:
+			err = w.addTree(context.FilePath)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *contextWatcher) addDir(dir string) error {
+	dir = filepath.Clean(dir)
+	if w.dirs[dir] {
+		return nil
+	}
+
+	err := w.fsw.Add(dir)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("error watching %s: %v", dir, err)
+	}
+
+	w.dirs[dir] = true
+	return nil
+}
+
+func (w *contextWatcher) addTree(root string) error {
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+
+		if !info.IsDir() {
+			return nil
+		}
+
+		if path != root && (info.Name() == ".git" || strings.Index(info.Name(), ".plandex") == 0) {
+			return filepath.SkipDir
+		}
+
+		return w.addDir(path)
+	})
+
+	if err != nil {
+		return fmt.Errorf("error watching %s: %v", root, err)
+	}
+
+	return nil
+}
+
+// onEvent records the path of a file event, returning true if there's anything to sync
+func (w *contextWatcher) onEvent(event fsnotify.Event) bool {
+	if event.Has(fsnotify.Chmod) && !(event.Has(fsnotify.Write) || event.Has(fsnotify.Create) || event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename)) {
+		return false
+	}
+
+	path := event.Name
+	if filepath.IsAbs(path) {
+		if rel, err := filepath.Rel(fs.Cwd, path); err == nil {
+			path = rel
+		}
+	}
+
+	// new directories under a watched directory are watched too, and the files they were created with are treated as new
+	if event.Has(fsnotify.Create) {
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			if strings.Index(info.Name(), ".plandex") == 0 || info.Name() == ".git" {
+				return false
+			}
+			err := w.addTree(path)
+			if err != nil {
+				log.Printf("Error watching new directory %s: %v\n", path, err)
+			}
+			filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+				if err == nil && !info.IsDir() {
+					w.pending[p] = true
+				}
+				return nil
+			})
+		}
+	}
+
+	w.pending[path] = true
+	return true
+}
+
+// sync updates context for the pending paths, then loads any new files matching a pattern that loaded context came from
+func (w *contextWatcher) sync() {
+	pending := w.pending
+	w.pending = map[string]bool{}
+
+	contexts, apiErr := api.Client.ListContext(CurrentPlanId, CurrentBranch)
+	if apiErr != nil {
+		fmt.Fprintf(os.Stderr, "🚨 Error getting context: %v\n", apiErr.Msg)
+		return
+	}
+
+	// context loaded since watching started gets watched too
+	err := w.watchContexts(contexts)
+	if err != nil {
+		log.Printf("Error watching context: %v\n", err)
+	}
+
+	var toUpdate []*shared.Context
+	loadedPaths := map[string]bool{}
+	var patterns []string
+	seenPatterns := map[string]bool{}
+
+	for _, context := range contexts {
+		switch context.ContextType {
+		case shared.ContextFileType, shared.ContextImageType:
+			loadedPaths[context.FilePath] = true
+			if pending[context.FilePath] && context.ContextType == shared.ContextFileType {
+				toUpdate = append(toUpdate, context)
+			}
+			if context.LoadedFrom != "" && !seenPatterns[context.LoadedFrom] {
+				seenPatterns[context.LoadedFrom] = true
+				patterns = append(patterns, context.LoadedFrom)
+			}
+		case shared.ContextDirectoryTreeType:
+			for path := range pending {
+				if containingDir(path, []string{context.FilePath}) != "" {
+					toUpdate = append(toUpdate, context)
+					break
+				}
+			}
+		}
+	}
+
+	newPaths, err := matchNewPaths(pending, loadedPaths, patterns)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "🚨 Error checking for new files: %v\n", err)
+	}
+
+	if len(toUpdate) == 0 && len(newPaths) == 0 {
+		return
+	}
+
+	fmt.Println()
+	fmt.Printf("🕐 %s\n", time.Now().Format("15:04:05"))
+
+	if len(toUpdate) > 0 {
+		updateRes, err := UpdateContext(toUpdate)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "🚨 Error updating context: %v\n", err)
+		} else if len(updateRes.UpdatedContexts) > 0 || len(updateRes.RemovedContexts) > 0 {
+			fmt.Print(tableForContextOutdated(append(updateRes.UpdatedContexts, updateRes.RemovedContexts...), updateRes.TokenDiffsById))
+			fmt.Println("✅ " + strings.TrimSpace(updateRes.Msg))
+			if updateRes.SecretsSummary != "" {
+				fmt.Print(updateRes.SecretsSummary)
+			}
+		}
+	}
+
+	if len(newPaths) > 0 {
+		err := w.loadNewPaths(newPaths)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "🚨 Error loading new files: %v\n", err)
+		}
+	}
+}
+
+// matchNewPaths returns the pending paths that aren't loaded yet and match one of patterns, by the pattern they match
+func matchNewPaths(pending, loadedPaths map[string]bool, patterns []string) (map[string]string, error) {
+	res := map[string]string{}
+	if len(patterns) == 0 {
+		return res, nil
+	}
+
+	var regexps []*regexp.Regexp
+	for _, pattern := range patterns {
+		re, err := globToRegexp(pattern)
+		if err != nil {
+			return nil, err
+		}
+		regexps = append(regexps, re)
+	}
+
+	var candidates []string
+	for path := range pending {
+		if loadedPaths[path] {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		for i, re := range regexps {
+			if re.MatchString(filepath.ToSlash(path)) {
+				res[path] = patterns[i]
+				candidates = append(candidates, path)
+				break
+			}
+		}
+	}
+
+	if len(candidates) == 0 {
+		return res, nil
+	}
+
+	// respect .gitignore and .plandexignore like `plandex load` does
+	paths, err := fs.GetProjectPaths(fs.GetBaseDirForFilePaths(candidates))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project paths: %v", err)
+	}
+	for _, path := range candidates {
+		if !paths.ActivePaths[path] {
+			delete(res, path)
+		}
+	}
+
+	return res, nil
+}
+
+func (w *contextWatcher) loadNewPaths(loadedFromByPath map[string]string) error {
+	var newPaths []string
+	for path := range loadedFromByPath {
+		newPaths = append(newPaths, path)
+	}
+	sort.Strings(newPaths)
+
+	var req shared.LoadContextRequest
+	for _, path := range newPaths {
+		params, err := getFileContextParams(path, "")
+		if err != nil {
+			return err
+		}
+		params.LoadedFrom = loadedFromByPath[path]
+		req = append(req, params)
+	}
+
+	guard, err := newSecretGuard()
+	if err != nil {
+		return err
+	}
+	for _, params := range req {
+		guard.scanLoadParams(params)
+	}
+	err = guard.resolve()
+	if err != nil {
+		return err
+	}
+
+	res, apiErr := api.Client.LoadContext(CurrentPlanId, CurrentBranch, req)
+	if apiErr != nil {
+		return fmt.Errorf("failed to load context: %v", apiErr.Msg)
+	}
+
+	if res.MaxTokensExceeded {
+		return fmt.Errorf("loading %s would add %d 🪙 and exceed the token limit (%d)", strings.Join(newPaths, ", "), res.TokensAdded, res.MaxTokens)
+	}
+
+	for _, path := range newPaths {
+		fmt.Printf("  + %s (from %s)\n", path, loadedFromByPath[path])
+	}
+	fmt.Println("✅ " + res.Msg)
+	guard.printSummary()
+
+	return nil
+}
+
+func pluralY(n int) string {
+	if n == 1 {
+		return "y"
+	}
+	return "ies"
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"plandex/api"
 	"plandex/fs"
+	"plandex/lib"
 	"plandex/stream"
 	streamtui "plandex/stream_tui"
 	"plandex/term"
@@ -36,6 +37,18 @@ func Build(params ExecParams, buildBg bool) (bool, error) {
 		return false, fmt.Errorf("error getting project paths: %v", err)
 	}
 
+	configSchemas, err := lib.GetConfigSchemas()
+
+	if err != nil {
+		return false, fmt.Errorf("error loading config schemas: %v", err)
+	}
+
+	projectInstructions, err := lib.GetProjectInstructions()
+
+	if err != nil {
+		return false, fmt.Errorf("error loading project instructions: %v", err)
+	}
+
 	var legacyApiKey, openAIBase, openAIOrgId string
 
 	if params.ApiKeys["OPENAI_API_KEY"] != "" {
@@ -54,11 +67,14 @@ func Build(params ExecParams, buildBg bool) (bool, error) {
 	apiErr = api.Client.BuildPlan(params.CurrentPlanId, params.CurrentBranch, shared.BuildPlanRequest{
 		ConnectStream: !buildBg,
 		ProjectPaths:  paths.ActivePaths,
+		ConfigSchemas: configSchemas,
 		ApiKey:        legacyApiKey, // deprecated
 		Endpoint:      openAIBase,   // deprecated
 		ApiKeys:       params.ApiKeys,
 		OpenAIBase:    openAIBase,
 		OpenAIOrgId:   openAIOrgId,
+
+		ProjectInstructions: projectInstructions,
 	}, stream.OnStreamPlan)
 
 	term.StopSpinner()
@@ -0,0 +1,389 @@
+package plan_exec
+
+import (
+	"fmt"
+	"os"
+	"plandex/api"
+	"plandex/lib"
+	"plandex/term"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/plandex/plandex/shared"
+)
+
+type packBranch struct {
+	pack   *shared.ModelPack
+	branch string
+	status shared.PlanStatus
+	// note is set when the branch was stopped while watching, e.g. on a timeout
+	note string
+}
+
+// TellComparePacks creates a branch from the current branch for each model pack, sends the prompt on all of them at once, and shows their statuses until every branch has finished
+func TellComparePacks(
+	params ExecParams,
+	prompt string,
+	packNames []string,
+	tellBg,
+	tellStop,
+	tellNoBuild bool,
+) {
+	term.StartSpinner("")
+	inputs := loadTellInputs(params, false)
+
+	packs := mustResolveModelPacks(packNames)
+
+	branches, apiErr := api.Client.ListBranches(params.CurrentPlanId)
+
+	if apiErr != nil {
+		term.OutputErrorAndExit("Error getting branches: %v", apiErr.Msg)
+	}
+
+	var parent *shared.Branch
+	existing := map[string]bool{}
+	for _, b := range branches {
+		existing[b.Name] = true
+		if b.Name == params.CurrentBranch {
+			parent = b
+		}
+	}
+
+	if parent == nil {
+		term.OutputErrorAndExit("Current branch %s not found", params.CurrentBranch)
+	}
+
+	var packBranches []*packBranch
+	for _, pack := range packs {
+		name := params.CurrentBranch + "-" + strings.ReplaceAll(strings.ToLower(pack.Name), " ", "-")
+
+		if existing[name] {
+			term.OutputErrorAndExit("Branch %s already exists. Delete it with 'plandex delete-branch %s' to compare this pack again.", name, name)
+		}
+
+		packBranches = append(packBranches, &packBranch{pack: pack, branch: name, status: shared.PlanStatusDraft})
+	}
+
+	parentState, apiErr := api.Client.GetCurrentPlanState(params.CurrentPlanId, params.CurrentBranch)
+
+	if apiErr != nil {
+		term.OutputErrorAndExit("Error getting current plan state: %v", apiErr.Msg)
+	}
+
+	parentResultIds := map[string]bool{}
+	for _, result := range parentState.PlanResult.Results {
+		parentResultIds[result.Id] = true
+	}
+
+	settings, apiErr := api.Client.GetSettings(params.CurrentPlanId, params.CurrentBranch)
+
+	if apiErr != nil {
+		term.OutputErrorAndExit("Error getting settings: %v", apiErr.Msg)
+	}
+
+	for _, pb := range packBranches {
+		apiErr := api.Client.CreateBranch(params.CurrentPlanId, params.CurrentBranch, shared.CreateBranchRequest{Name: pb.branch})
+
+		if apiErr != nil {
+			term.OutputErrorAndExit("Error creating branch %s: %v", pb.branch, apiErr.Msg)
+		}
+
+		packSettings := *settings
+		packSettings.ModelPack = pb.pack
+
+		_, apiErr = api.Client.UpdateSettings(params.CurrentPlanId, pb.branch, shared.UpdateSettingsRequest{
+			Settings: &packSettings,
+		})
+
+		if apiErr != nil {
+			term.OutputErrorAndExit("Error setting model pack on branch %s: %v", pb.branch, apiErr.Msg)
+		}
+	}
+
+	var buildMode shared.BuildMode
+	if tellNoBuild {
+		buildMode = shared.BuildModeNone
+	} else {
+		buildMode = shared.BuildModeAuto
+	}
+
+	errCh := make(chan error, len(packBranches))
+	for _, pb := range packBranches {
+		go func(pb *packBranch) {
+			req := inputs.request(params, prompt)
+			req.AutoContinue = !tellStop
+			req.BuildMode = buildMode
+
+			apiErr := api.Client.TellPlan(params.CurrentPlanId, pb.branch, req, nil)
+
+			if apiErr != nil {
+				errCh <- fmt.Errorf("error sending prompt on branch %s: %s", pb.branch, apiErr.Msg)
+				return
+			}
+
+			errCh <- nil
+		}(pb)
+	}
+
+	for range packBranches {
+		err := <-errCh
+		if err != nil {
+			term.OutputErrorAndExit("%v", err)
+		}
+	}
+
+	term.StopSpinner()
+
+	if tellBg {
+		fmt.Println("✅ Plan is active in the background on branches:")
+		for _, pb := range packBranches {
+			fmt.Printf("  • %s → %s\n", color.New(color.Bold, term.ColorHiCyan).Sprint(pb.branch), pb.pack.Name)
+		}
+		fmt.Println()
+		term.PrintCmds("", "ps", "branches", "compare")
+		return
+	}
+
+	byName := watchPackBranches(params.CurrentPlanId, packBranches)
+
+	fmt.Println()
+	printPackBranchSummary(params.CurrentPlanId, parent, parentResultIds, packBranches, byName)
+
+	fmt.Println()
+	term.PrintCmds("", "compare", "checkout", "branches", "delete-branch")
+}
+
+func mustResolveModelPacks(names []string) []*shared.ModelPack {
+	customPacks, apiErr := api.Client.ListModelPacks()
+
+	if apiErr != nil {
+		term.OutputErrorAndExit("Error getting custom model packs: %v", apiErr.Msg)
+	}
+
+	var packs []*shared.ModelPack
+	seen := map[string]bool{}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[strings.ToLower(name)] {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+
+		packs = append(packs, mustFindModelPack(name, customPacks))
+	}
+
+	if len(packs) < 2 {
+		term.OutputErrorAndExit("Pass at least two model packs to compare")
+	}
+
+	return packs
+}
+
+func mustFindModelPack(name string, customPacks []*shared.ModelPack) *shared.ModelPack {
+	for _, packs := range [][]*shared.ModelPack{shared.BuiltInModelPacks, customPacks} {
+		for _, pack := range packs {
+			if strings.EqualFold(pack.Name, name) {
+				return pack
+			}
+		}
+	}
+
+	term.OutputErrorAndExit("Model pack not found: %s", name)
+	return nil
+}
+
+// comparePacksTimeout is how long watchPackBranches waits for the branches before stopping any that are still running
+const comparePacksTimeout = 30 * time.Minute
+
+// comparePacksStreamGrace is how long a branch can show an active status with no running stream before it's treated as closed. It covers the gap between sending the prompt and the stream being registered.
+const comparePacksStreamGrace = 10 * time.Second
+
+// watchPackBranches redraws each branch's status until none are still running, then returns the branches by name. Branches waiting on a missing file are blocked rather than running, since they need the user to respond. A branch whose stream closed without it finishing, or that's still running after comparePacksTimeout, is stopped and marked with a note.
+func watchPackBranches(planId string, packBranches []*packBranch) map[string]*shared.Branch {
+	startedAt := time.Now()
+	noStreamSince := map[string]time.Time{}
+	numLines := 0
+	for {
+		branches, apiErr := api.Client.ListBranches(planId)
+
+		if apiErr != nil {
+			term.OutputErrorAndExit("Error getting branches: %v", apiErr.Msg)
+		}
+
+		runningRes, apiErr := api.Client.ListPlansRunning([]string{lib.CurrentProjectId}, false)
+
+		if apiErr != nil {
+			term.OutputErrorAndExit("Error getting running plans: %v", apiErr.Msg)
+		}
+
+		byName := map[string]*shared.Branch{}
+		for _, b := range branches {
+			byName[b.Name] = b
+		}
+
+		streaming := map[string]bool{}
+		for _, b := range runningRes.Branches {
+			if b.PlanId == planId {
+				streaming[b.Name] = true
+			}
+		}
+
+		timedOut := time.Since(startedAt) > comparePacksTimeout
+
+		if numLines > 0 {
+			term.MoveUpLines(numLines)
+		}
+
+		allDone := true
+		numLines = 0
+		for _, pb := range packBranches {
+			if b, ok := byName[pb.branch]; ok && pb.note == "" {
+				pb.status = b.Status
+			}
+
+			if isPackBranchRunning(pb.status) && pb.note == "" {
+				if streaming[pb.branch] {
+					delete(noStreamSince, pb.branch)
+				} else if _, ok := noStreamSince[pb.branch]; !ok {
+					noStreamSince[pb.branch] = time.Now()
+				}
+
+				if since, ok := noStreamSince[pb.branch]; ok && time.Since(since) > comparePacksStreamGrace {
+					pb.note = "stream closed before the plan finished"
+				} else if timedOut {
+					pb.note = fmt.Sprintf("timed out after %s", comparePacksTimeout)
+				}
+
+				if pb.note != "" {
+					// the plan may still be running on the server, so stop it rather than leave it unwatched
+					apiErr := api.Client.StopPlan(planId, pb.branch)
+					if apiErr != nil {
+						pb.note += fmt.Sprintf(", and failed to stop the plan: %s", apiErr.Msg)
+					}
+					pb.status = shared.PlanStatusStopped
+				} else {
+					allDone = false
+				}
+			}
+
+			term.ClearCurrentLine()
+			fmt.Printf("%s %s %s\n", packStatusLabel(pb.status), color.New(color.Bold, term.ColorHiCyan).Sprint(pb.branch), color.New(color.FgHiBlack).Sprintf("(%s)", pb.pack.Name))
+			numLines++
+		}
+
+		if allDone {
+			return byName
+		}
+
+		time.Sleep(time.Second)
+	}
+}
+
+// isPackBranchRunning is true until a branch has finished, stopped, errored, or is blocked waiting on a missing file
+func isPackBranchRunning(status shared.PlanStatus) bool {
+	switch status {
+	case shared.PlanStatusFinished, shared.PlanStatusStopped, shared.PlanStatusError, shared.PlanStatusMissingFile:
+		return false
+	}
+	return true
+}
+
+func packStatusLabel(status shared.PlanStatus) string {
+	switch status {
+	case shared.PlanStatusReplying:
+		return "💬 replying  "
+	case shared.PlanStatusDescribing:
+		return "✏️  describing"
+	case shared.PlanStatusBuilding:
+		return "🏗️  building  "
+	case shared.PlanStatusFinished:
+		return "✅ finished  "
+	case shared.PlanStatusStopped:
+		return "🛑 stopped   "
+	case shared.PlanStatusError:
+		return "🚨 error     "
+	case shared.PlanStatusMissingFile:
+		return "📄 blocked   "
+	}
+	return "⏳ starting  "
+}
+
+func printPackBranchSummary(planId string, parent *shared.Branch, parentResultIds map[string]bool, packBranches []*packBranch, byName map[string]*shared.Branch) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetAutoWrapText(false)
+	table.SetHeader([]string{"Branch", "Model Pack", "Status", "Built Files", "Failed Builds", "Convo Tokens Added"})
+
+	var failures []string
+	var blocked []string
+	for _, pb := range packBranches {
+		b := byName[pb.branch]
+
+		builtPaths := map[string]bool{}
+		failedPaths := map[string]bool{}
+
+		state, apiErr := api.Client.GetCurrentPlanState(planId, pb.branch)
+
+		if apiErr != nil {
+			failures = append(failures, fmt.Sprintf("%s: error getting plan state: %s", pb.branch, apiErr.Msg))
+		} else {
+			for _, result := range state.PlanResult.Results {
+				if parentResultIds[result.Id] {
+					continue
+				}
+				builtPaths[result.Path] = true
+				if result.AnyFailed {
+					failedPaths[result.Path] = true
+				}
+			}
+		}
+
+		if pb.note != "" {
+			failures = append(failures, fmt.Sprintf("%s: %s", pb.branch, pb.note))
+		} else if pb.status == shared.PlanStatusMissingFile {
+			blocked = append(blocked, pb.branch)
+		}
+
+		convoTokens := ""
+		if b != nil {
+			convoTokens = fmt.Sprintf("+%d 🪙", b.ConvoTokens-parent.ConvoTokens)
+			if b.Status == shared.PlanStatusError {
+				failures = append(failures, fmt.Sprintf("%s: %s", pb.branch, b.Error))
+			}
+		}
+
+		table.Append([]string{
+			pb.branch,
+			pb.pack.Name,
+			string(pb.status),
+			strconv.Itoa(len(builtPaths)),
+			strconv.Itoa(len(failedPaths)),
+			convoTokens,
+		})
+	}
+
+	table.Render()
+
+	fmt.Println(color.New(color.FgHiBlack).Sprint("Tokens added to each branch's conversation. Tokens used by builds aren't included."))
+
+	if len(blocked) > 0 {
+		fmt.Println()
+		color.New(color.Bold, term.ColorHiYellow).Println("📄 Waiting on a missing file")
+		for _, branch := range blocked {
+			fmt.Printf("  • %s\n", branch)
+		}
+		fmt.Println("Check out each branch and use 'plandex connect' to choose what to do with the file.")
+	}
+
+	if len(failures) > 0 {
+		sort.Strings(failures)
+		fmt.Println()
+		color.New(color.Bold, term.ColorHiRed).Println("🚨 Failures")
+		for _, failure := range failures {
+			fmt.Printf("  • %s\n", failure)
+		}
+	}
+}
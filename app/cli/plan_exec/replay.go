@@ -0,0 +1,417 @@
+package plan_exec
+
+import (
+	"fmt"
+	"plandex/api"
+	"plandex/lib"
+	"plandex/term"
+	"plandex/types"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/plandex/plandex/shared"
+)
+
+// each prompt gets this long to finish replying and building
+const replayPromptTimeout = 30 * time.Minute
+
+type ReplayParams struct {
+	PackName string
+	// FromNum is the number of the first message to replay. Replay starts at the first prompt at or after it.
+	FromNum int
+	Branch  string
+}
+
+// ReplayPlan creates a branch from the current branch rewound to before message FromNum, then sends each of the current branch's prompts from there in order with a different model pack, building as it goes. Context changes the original made between prompts are replayed too. It reports the files each prompt built that differ from the original run.
+func ReplayPlan(params ExecParams, replayParams ReplayParams) {
+	term.StartSpinner("")
+
+	customPacks, apiErr := api.Client.ListModelPacks()
+
+	if apiErr != nil {
+		term.OutputErrorAndExit("Error getting custom model packs: %v", apiErr.Msg)
+	}
+
+	pack := mustFindModelPack(replayParams.PackName, customPacks)
+
+	convo, apiErr := api.Client.ListConvo(params.CurrentPlanId, params.CurrentBranch)
+
+	if apiErr != nil {
+		term.OutputErrorAndExit("Error getting conversation: %v", apiErr.Msg)
+	}
+
+	var prompts []*shared.ConvoMessage
+	for _, msg := range convo {
+		if msg.Role == "user" && msg.Num >= replayParams.FromNum {
+			prompts = append(prompts, msg)
+		}
+	}
+
+	if len(prompts) == 0 {
+		term.StopSpinner()
+		fmt.Printf("🤷‍♂️ No prompts to replay from message %d\n", replayParams.FromNum)
+		return
+	}
+
+	originalState, apiErr := api.Client.GetCurrentPlanState(params.CurrentPlanId, params.CurrentBranch)
+
+	if apiErr != nil {
+		term.OutputErrorAndExit("Error getting current plan state: %v", apiErr.Msg)
+	}
+
+	originalPaths := originalPathsByPrompt(convo, originalState)
+
+	branch := replayParams.Branch
+	if branch == "" {
+		branch = params.CurrentBranch + "-replay-" + strings.ReplaceAll(strings.ToLower(pack.Name), " ", "-")
+	}
+
+	branches, apiErr := api.Client.ListBranches(params.CurrentPlanId)
+
+	if apiErr != nil {
+		term.OutputErrorAndExit("Error getting branches: %v", apiErr.Msg)
+	}
+
+	for _, b := range branches {
+		if b.Name == branch {
+			term.OutputErrorAndExit("Branch %s already exists. Delete it with 'plandex delete-branch %s' or pass --branch to replay on a new branch.", branch, branch)
+		}
+	}
+
+	settings, apiErr := api.Client.GetSettings(params.CurrentPlanId, params.CurrentBranch)
+
+	if apiErr != nil {
+		term.OutputErrorAndExit("Error getting settings: %v", apiErr.Msg)
+	}
+
+	apiErr = api.Client.CreateBranch(params.CurrentPlanId, params.CurrentBranch, shared.CreateBranchRequest{Name: branch})
+
+	if apiErr != nil {
+		term.OutputErrorAndExit("Error creating branch %s: %v", branch, apiErr.Msg)
+	}
+
+	replaySettings := *settings
+	replaySettings.ModelPack = pack
+
+	_, apiErr = api.Client.UpdateSettings(params.CurrentPlanId, branch, shared.UpdateSettingsRequest{
+		Settings: &replaySettings,
+	})
+
+	if apiErr != nil {
+		term.OutputErrorAndExit("Error setting model pack on branch %s: %v", branch, apiErr.Msg)
+	}
+
+	_, apiErr = api.Client.RewindPlan(params.CurrentPlanId, branch, shared.RewindPlanRequest{BeforeMessageId: prompts[0].Id})
+
+	if apiErr != nil {
+		term.OutputErrorAndExit("Error rewinding branch %s: %v", branch, apiErr.Msg)
+	}
+
+	// context is checked on the replay branch, which now has the context the original had before the first prompt
+	replayExecParams := params
+	replayExecParams.CurrentBranch = branch
+	replayExecParams.CheckOutdatedContext = func(maybeContexts []*shared.Context) (bool, bool) {
+		return checkReplayContext(params.CurrentPlanId, branch, maybeContexts)
+	}
+
+	inputs := loadTellInputs(replayExecParams, false)
+
+	replayState, apiErr := api.Client.GetCurrentPlanState(params.CurrentPlanId, branch)
+
+	if apiErr != nil {
+		term.OutputErrorAndExit("Error getting current plan state: %v", apiErr.Msg)
+	}
+
+	term.StopSpinner()
+
+	fmt.Printf("🔁 Replaying %d prompt%s on %s with %s\n\n", len(prompts), pluralS(len(prompts)), color.New(color.Bold, term.ColorHiCyan).Sprint(branch), color.New(color.Bold).Sprint(pack.Name))
+
+	seenResultIds := resultIds(replayState)
+	numDiffering := 0
+
+	for i, prompt := range prompts {
+		fmt.Printf("%s %s\n", color.New(color.Bold).Sprintf("Prompt %d/%d (message %d)", i+1, len(prompts), prompt.Num), summarizePrompt(prompt.Message))
+
+		term.StartSpinner("")
+
+		if i > 0 {
+			res, apiErr := api.Client.ReplayContextChanges(params.CurrentPlanId, branch, shared.ReplayContextChangesRequest{
+				SourceBranch:  params.CurrentBranch,
+				FromMessageId: prompts[i-1].Id,
+				ToMessageId:   prompt.Id,
+			})
+
+			if apiErr != nil {
+				term.StopSpinner()
+				fmt.Printf("🚨 Error replaying context changes: %s\n\n", apiErr.Msg)
+				fmt.Printf("Replay stopped at message %d\n", prompt.Num)
+				break
+			}
+
+			if res.NumChanged > 0 {
+				term.StopSpinner()
+				fmt.Println("📥 " + res.Msg)
+				term.ResumeSpinner()
+			}
+		}
+
+		req := inputs.request(params, prompt.Message)
+		req.ConnectStream = true
+		req.AutoContinue = !prompt.IsChat
+		req.IsChat = prompt.IsChat
+		if prompt.IsChat {
+			req.BuildMode = shared.BuildModeNone
+		} else {
+			req.BuildMode = shared.BuildModeAuto
+		}
+
+		err := tellAndWait(params.CurrentPlanId, branch, req)
+
+		term.StopSpinner()
+
+		if err != nil {
+			fmt.Printf("🚨 %v\n\n", err)
+			fmt.Printf("Replay stopped at message %d\n", prompt.Num)
+			break
+		}
+
+		replayState, apiErr = api.Client.GetCurrentPlanState(params.CurrentPlanId, branch)
+
+		if apiErr != nil {
+			term.OutputErrorAndExit("Error getting current plan state: %v", apiErr.Msg)
+		}
+
+		replayPaths := map[string]bool{}
+		for _, result := range replayState.PlanResult.Results {
+			if !seenResultIds[result.Id] {
+				replayPaths[result.Path] = true
+			}
+		}
+		seenResultIds = resultIds(replayState)
+
+		onlyOriginal := pathsMissingFrom(originalPaths[prompt.Id], replayPaths)
+		onlyReplay := pathsMissingFrom(replayPaths, originalPaths[prompt.Id])
+
+		if len(onlyOriginal) == 0 && len(onlyReplay) == 0 {
+			fmt.Printf("✅ Built the same %d file%s as the original\n\n", len(replayPaths), pluralS(len(replayPaths)))
+			continue
+		}
+
+		numDiffering++
+		for _, path := range onlyOriginal {
+			fmt.Printf("  %s %s\n", color.New(color.FgHiRed).Sprint("- only original"), path)
+		}
+		for _, path := range onlyReplay {
+			fmt.Printf("  %s %s\n", color.New(color.FgHiGreen).Sprint("+ only replay  "), path)
+		}
+		fmt.Println()
+	}
+
+	differingFiles := differingPendingFiles(originalState, replayState)
+
+	fmt.Println(color.New(color.Bold).Sprint("Summary"))
+	fmt.Printf("  • %d prompt%s built different files than the original\n", numDiffering, pluralS(numDiffering))
+	if len(differingFiles) == 0 {
+		fmt.Println("  • Pending files match the original")
+	} else {
+		fmt.Printf("  • %d pending file%s differ from the original:\n", len(differingFiles), pluralS(len(differingFiles)))
+		for _, path := range differingFiles {
+			fmt.Printf("    %s\n", path)
+		}
+	}
+
+	fmt.Println()
+	term.PrintCmds("", "compare", "checkout", "convo")
+}
+
+// tellAndWait sends a prompt and waits for its stream to finish, answering any missing file prompts by letting the model create the file. If the plan doesn't finish within replayPromptTimeout, it's stopped.
+func tellAndWait(planId, branch string, req shared.TellPlanRequest) error {
+	doneCh := make(chan error, 1)
+
+	// the stream can report more than once, e.g. a finished message followed by the stream closing
+	var once sync.Once
+	done := func(err error) {
+		once.Do(func() { doneCh <- err })
+	}
+
+	var handle func(msg *shared.StreamMessage) bool
+	handle = func(msg *shared.StreamMessage) bool {
+		switch msg.Type {
+		case shared.StreamMessageMulti:
+			for i := range msg.StreamMessages {
+				if handle(&msg.StreamMessages[i]) {
+					return true
+				}
+			}
+		case shared.StreamMessagePromptMissingFile:
+			apiErr := api.Client.RespondMissingFile(planId, branch, shared.RespondMissingFileRequest{
+				Choice:   shared.RespondMissingFileChoiceOverwrite,
+				FilePath: msg.MissingFilePath,
+			})
+			if apiErr != nil {
+				done(fmt.Errorf("error responding to missing file %s: %s", msg.MissingFilePath, apiErr.Msg))
+				return true
+			}
+		case shared.StreamMessageFinished:
+			done(nil)
+			return true
+		case shared.StreamMessageAborted:
+			done(fmt.Errorf("stream aborted"))
+			return true
+		case shared.StreamMessageError:
+			errMsg := "stream error"
+			if msg.Error != nil {
+				errMsg = msg.Error.Msg
+			}
+			done(fmt.Errorf("%s", errMsg))
+			return true
+		}
+		return false
+	}
+
+	apiErr := api.Client.TellPlan(planId, branch, req, func(params types.OnStreamPlanParams) {
+		if params.Err != nil {
+			done(fmt.Errorf("error reading stream: %v", params.Err))
+			return
+		}
+		if params.Msg == nil {
+			done(fmt.Errorf("stream closed before the plan finished"))
+			return
+		}
+		handle(params.Msg)
+	})
+
+	if apiErr != nil {
+		return fmt.Errorf("error sending prompt: %s", apiErr.Msg)
+	}
+
+	select {
+	case err := <-doneCh:
+		return err
+	case <-time.After(replayPromptTimeout):
+		apiErr := api.Client.StopPlan(planId, branch)
+		if apiErr != nil {
+			return fmt.Errorf("timed out after %s, and failed to stop the plan: %s", replayPromptTimeout, apiErr.Msg)
+		}
+		return fmt.Errorf("timed out after %s", replayPromptTimeout)
+	}
+}
+
+// checkReplayContext is the outdated context check for the replay branch. Replaying with context as the original had it keeps the comparison fair, so context is never updated -- the user chooses whether to replay anyway or stop and delete the branch.
+func checkReplayContext(planId, branch string, contexts []*shared.Context) (bool, bool) {
+	outdatedRes, err := lib.CheckOutdatedContext(contexts)
+
+	if err != nil {
+		term.StopSpinner()
+		term.OutputErrorAndExit("failed to check outdated context: %s", err)
+	}
+
+	numOutdated := len(outdatedRes.UpdatedContexts) + len(outdatedRes.RemovedContexts)
+
+	if numOutdated == 0 {
+		return false, false
+	}
+
+	term.StopSpinner()
+
+	fmt.Printf("⚠️  %d context%s on %s changed in the project since the first replayed prompt was sent\n", numOutdated, pluralS(numOutdated), color.New(color.Bold, term.ColorHiCyan).Sprint(branch))
+
+	confirmed, err := term.ConfirmYesNo("Replay with context as it was when the prompts were sent?")
+
+	if err != nil {
+		term.OutputErrorAndExit("failed to get user input: %s", err)
+	}
+
+	if confirmed {
+		term.StartSpinner("")
+		return false, false
+	}
+
+	apiErr := api.Client.DeleteBranch(planId, branch)
+
+	if apiErr != nil {
+		term.OutputErrorAndExit("Error deleting branch %s: %v", branch, apiErr.Msg)
+	}
+
+	fmt.Println("Replay canceled")
+	return true, false
+}
+
+// originalPathsByPrompt maps each user prompt's id to the paths of the results built from the replies that followed it
+func originalPathsByPrompt(convo []*shared.ConvoMessage, state *shared.CurrentPlanState) map[string]map[string]bool {
+	promptIdByReplyId := map[string]string{}
+	var promptId string
+	for _, msg := range convo {
+		if msg.Role == "user" {
+			promptId = msg.Id
+		} else {
+			promptIdByReplyId[msg.Id] = promptId
+		}
+	}
+
+	res := map[string]map[string]bool{}
+	for _, result := range state.PlanResult.Results {
+		promptId, ok := promptIdByReplyId[result.ConvoMessageId]
+		if !ok {
+			continue
+		}
+		if res[promptId] == nil {
+			res[promptId] = map[string]bool{}
+		}
+		res[promptId][result.Path] = true
+	}
+	return res
+}
+
+func differingPendingFiles(original, replay *shared.CurrentPlanState) []string {
+	var paths []string
+	for path, file := range original.CurrentPlanFiles.Files {
+		if replayFile, ok := replay.CurrentPlanFiles.Files[path]; !ok || replayFile != file {
+			paths = append(paths, path)
+		}
+	}
+	for path := range replay.CurrentPlanFiles.Files {
+		if _, ok := original.CurrentPlanFiles.Files[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func resultIds(state *shared.CurrentPlanState) map[string]bool {
+	ids := map[string]bool{}
+	for _, result := range state.PlanResult.Results {
+		ids[result.Id] = true
+	}
+	return ids
+}
+
+func pathsMissingFrom(paths, other map[string]bool) []string {
+	var res []string
+	for path := range paths {
+		if !other[path] {
+			res = append(res, path)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+func summarizePrompt(prompt string) string {
+	line := strings.TrimSpace(strings.SplitN(strings.TrimSpace(prompt), "\n", 2)[0])
+	if len(line) > 60 {
+		line = line[:57] + "..."
+	}
+	return color.New(color.FgHiBlack).Sprint(line)
+}
+
+func pluralS(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
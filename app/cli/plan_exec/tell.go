@@ -7,6 +7,7 @@ import (
 	"plandex/api"
 	"plandex/auth"
 	"plandex/fs"
+	"plandex/lib"
 	"plandex/stream"
 	streamtui "plandex/stream_tui"
 	"plandex/term"
@@ -20,38 +21,17 @@ func TellPlan(
 	tellBg,
 	tellStop,
 	tellNoBuild,
-	isUserContinue bool,
+	isUserContinue,
+	isChat bool,
 ) {
 	term.StartSpinner("")
-	contexts, apiErr := api.Client.ListContext(params.CurrentPlanId, params.CurrentBranch)
-
-	if apiErr != nil {
-		term.OutputErrorAndExit("Error getting context: %v", apiErr)
-	}
-
-	anyOutdated, didUpdate := params.CheckOutdatedContext(contexts)
-
-	if anyOutdated && !didUpdate {
-		term.StopSpinner()
-		if isUserContinue {
-			log.Println("Plan won't continue")
-		} else {
-			log.Println("Prompt not sent")
-		}
-		os.Exit(0)
-	}
-
-	paths, err := fs.GetProjectPaths(fs.GetBaseDirForContexts(contexts))
-
-	if err != nil {
-		term.OutputErrorAndExit("Error getting project paths: %v", err)
-	}
+	inputs := loadTellInputs(params, isUserContinue)
 
 	var fn func() bool
 	fn = func() bool {
 
 		var buildMode shared.BuildMode
-		if tellNoBuild {
+		if tellNoBuild || isChat {
 			buildMode = shared.BuildModeNone
 		} else {
 			buildMode = shared.BuildModeAuto
@@ -63,31 +43,14 @@ func TellPlan(
 			term.StartSpinner("💬 Sending prompt...")
 		}
 
-		var legacyApiKey, openAIBase, openAIOrgId string
-
-		if params.ApiKeys["OPENAI_API_KEY"] != "" {
-			openAIBase = os.Getenv("OPENAI_API_BASE")
-			if openAIBase == "" {
-				openAIBase = os.Getenv("OPENAI_ENDPOINT")
-			}
-
-			legacyApiKey = params.ApiKeys["OPENAI_API_KEY"]
-			openAIOrgId = params.ApiKeys["OPENAI_ORG_ID"]
-		}
+		req := inputs.request(params, prompt)
+		req.ConnectStream = !tellBg
+		req.AutoContinue = !tellStop && !isChat
+		req.BuildMode = buildMode
+		req.IsUserContinue = isUserContinue
+		req.IsChat = isChat
 
-		apiErr := api.Client.TellPlan(params.CurrentPlanId, params.CurrentBranch, shared.TellPlanRequest{
-			Prompt:         prompt,
-			ConnectStream:  !tellBg,
-			AutoContinue:   !tellStop,
-			ProjectPaths:   paths.ActivePaths,
-			BuildMode:      buildMode,
-			IsUserContinue: isUserContinue,
-			ApiKey:         legacyApiKey, // deprecated
-			Endpoint:       openAIBase,   // deprecated
-			ApiKeys:        params.ApiKeys,
-			OpenAIBase:     openAIBase,
-			OpenAIOrgId:    openAIOrgId,
-		}, stream.OnStreamPlan)
+		apiErr := api.Client.TellPlan(params.CurrentPlanId, params.CurrentBranch, req, stream.OnStreamPlan)
 
 		term.StopSpinner()
 
@@ -130,7 +93,9 @@ func TellPlan(
 
 				fmt.Println()
 
-				if tellStop {
+				if isChat {
+					term.PrintCmds("", "chat", "tell", "convo")
+				} else if tellStop {
 					term.PrintCmds("", "continue", "changes", "diff", "apply", "reject", "log", "rewind")
 				} else {
 					term.PrintCmds("", "changes", "diff", "apply", "reject", "log", "rewind")
@@ -156,3 +121,82 @@ func TellPlan(
 		select {}
 	}
 }
+
+// tellInputs are loaded from the project once before a prompt is sent
+type tellInputs struct {
+	projectPaths        map[string]bool
+	configSchemas       map[string]string
+	projectInstructions string
+}
+
+// loadTellInputs exits if the plan has outdated context that the user chose not to update
+func loadTellInputs(params ExecParams, isUserContinue bool) tellInputs {
+	contexts, apiErr := api.Client.ListContext(params.CurrentPlanId, params.CurrentBranch)
+
+	if apiErr != nil {
+		term.OutputErrorAndExit("Error getting context: %v", apiErr)
+	}
+
+	anyOutdated, didUpdate := params.CheckOutdatedContext(contexts)
+
+	if anyOutdated && !didUpdate {
+		term.StopSpinner()
+		if isUserContinue {
+			log.Println("Plan won't continue")
+		} else {
+			log.Println("Prompt not sent")
+		}
+		os.Exit(0)
+	}
+
+	paths, err := fs.GetProjectPaths(fs.GetBaseDirForContexts(contexts))
+
+	if err != nil {
+		term.OutputErrorAndExit("Error getting project paths: %v", err)
+	}
+
+	configSchemas, err := lib.GetConfigSchemas()
+
+	if err != nil {
+		term.OutputErrorAndExit("Error loading config schemas: %v", err)
+	}
+
+	projectInstructions, err := lib.GetProjectInstructions()
+
+	if err != nil {
+		term.OutputErrorAndExit("Error loading project instructions: %v", err)
+	}
+
+	return tellInputs{
+		projectPaths:        paths.ActivePaths,
+		configSchemas:       configSchemas,
+		projectInstructions: projectInstructions,
+	}
+}
+
+func (inputs tellInputs) request(params ExecParams, prompt string) shared.TellPlanRequest {
+	var legacyApiKey, openAIBase, openAIOrgId string
+
+	if params.ApiKeys["OPENAI_API_KEY"] != "" {
+		openAIBase = os.Getenv("OPENAI_API_BASE")
+		if openAIBase == "" {
+			openAIBase = os.Getenv("OPENAI_ENDPOINT")
+		}
+
+		legacyApiKey = params.ApiKeys["OPENAI_API_KEY"]
+		openAIOrgId = params.ApiKeys["OPENAI_ORG_ID"]
+	}
+
+	return shared.TellPlanRequest{
+		Prompt:        prompt,
+		ProjectPaths:  inputs.projectPaths,
+		ConfigSchemas: inputs.configSchemas,
+		ApiKey:        legacyApiKey, // deprecated
+		Endpoint:      openAIBase,   // deprecated
+		ApiKeys:       params.ApiKeys,
+		OpenAIBase:    openAIBase,
+		OpenAIOrgId:   openAIOrgId,
+
+		ProjectInstructions: inputs.projectInstructions,
+	}
+}
@@ -0,0 +1,118 @@
+package secrets
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Redacted secrets are replaced with a placeholder derived from a hash of the value, so the same secret always gets the same placeholder and redacted context keeps a stable sha.
+// Placeholders are mapped back to their values in a file in the home plandex dir that is never uploaded, and restored when changes are applied.
+
+var placeholderRegex = regexp.MustCompile(`REDACTED_[A-Z0-9_]+_[0-9a-f]{12}`)
+
+const redactionsFile = "redactions.json"
+
+var redactionsMu sync.Mutex
+
+func Placeholder(kind, value string) string {
+	hash := sha256.Sum256([]byte(value))
+	kind = strings.ToUpper(strings.NewReplacer("-", "_", " ", "_").Replace(kind))
+	return fmt.Sprintf("REDACTED_%s_%s", kind, hex.EncodeToString(hash[:])[:12])
+}
+
+func isPlaceholder(s string) bool {
+	return placeholderRegex.FindString(s) == s
+}
+
+// Redact replaces each finding's value with its placeholder, returning the redacted text and the values by placeholder
+func Redact(text string, findings []*Finding) (string, map[string]string) {
+	redactions := map[string]string{}
+	for _, finding := range findings {
+		placeholder := Placeholder(finding.Kind, finding.Value)
+		redactions[placeholder] = finding.Value
+		text = strings.ReplaceAll(text, finding.Value, placeholder)
+	}
+	return text, redactions
+}
+
+// Restore replaces known placeholders in text with the original values
+func Restore(text string, redactions map[string]string) string {
+	if len(redactions) == 0 {
+		return text
+	}
+	return placeholderRegex.ReplaceAllStringFunc(text, func(placeholder string) string {
+		if value, ok := redactions[placeholder]; ok {
+			return value
+		}
+		return placeholder
+	})
+}
+
+func LoadRedactions(homePlandexDir string) (map[string]string, error) {
+	redactionsMu.Lock()
+	defer redactionsMu.Unlock()
+	return loadRedactions(homePlandexDir)
+}
+
+func loadRedactions(homePlandexDir string) (map[string]string, error) {
+	redactions := map[string]string{}
+
+	bytes, err := os.ReadFile(filepath.Join(homePlandexDir, redactionsFile))
+	if os.IsNotExist(err) {
+		return redactions, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error reading %s: %v", redactionsFile, err)
+	}
+
+	err = json.Unmarshal(bytes, &redactions)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling %s: %v", redactionsFile, err)
+	}
+
+	return redactions, nil
+}
+
+// SaveRedactions merges redactions into the stored placeholders
+func SaveRedactions(homePlandexDir string, redactions map[string]string) error {
+	if len(redactions) == 0 {
+		return nil
+	}
+
+	redactionsMu.Lock()
+	defer redactionsMu.Unlock()
+
+	stored, err := loadRedactions(homePlandexDir)
+	if err != nil {
+		return err
+	}
+
+	var changed bool
+	for placeholder, value := range redactions {
+		if stored[placeholder] != value {
+			stored[placeholder] = value
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	bytes, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling %s: %v", redactionsFile, err)
+	}
+
+	err = os.WriteFile(filepath.Join(homePlandexDir, redactionsFile), bytes, 0600)
+	if err != nil {
+		return fmt.Errorf("error writing %s: %v", redactionsFile, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,178 @@
+package secrets
+
+import (
+	"math"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+type rule struct {
+	kind string
+	re   *regexp.Regexp
+}
+
+// if a rule's regex has a capture group, only the first group is the secret
+var builtinRules = []rule{
+	{"private-key", regexp.MustCompile(`-----BEGIN[A-Z ]*PRIVATE KEY-----[\s\S]*?-----END[A-Z ]*PRIVATE KEY-----`)},
+	{"aws-access-key", regexp.MustCompile(`\b(?:AKIA|ASIA|ABIA|ACCA)[0-9A-Z]{16}\b`)},
+	{"aws-secret-key", regexp.MustCompile(`(?i)aws.{0,20}?(?:secret|private).{0,20}?[=:]\s*["']?([A-Za-z0-9/+]{40})\b`)},
+	{"github-token", regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,255}|github_pat_[A-Za-z0-9_]{22,255})\b`)},
+	{"slack-token", regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}\b`)},
+	{"stripe-key", regexp.MustCompile(`\b(?:sk|rk)_live_[A-Za-z0-9]{20,}\b`)},
+	{"api-key", regexp.MustCompile(`\bsk-(?:proj-|ant-)?[A-Za-z0-9_-]{32,}\b`)},
+	{"google-api-key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{"jwt", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`)},
+	{"connection-string", regexp.MustCompile(`\b[a-z][a-z0-9+.-]*://[^\s:/@"']+:([^\s@/"']{3,})@`)},
+	// .env-style assignments to names that suggest a secret, e.g. DB_PASSWORD=hunter22 or export API_TOKEN="..."
+	{"env-assignment", regexp.MustCompile(`(?m)^[ \t]*(?:export[ \t]+)?[A-Z0-9_]*(?:SECRET|TOKEN|PASSWORD|PASSWD|API_?KEY|PRIVATE_?KEY|ACCESS_?KEY|CREDENTIALS?)[A-Z0-9_]*[ \t]*[=:][ \t]*["']?([^\s"'#$<{][^\s"'#]{7,})`)},
+}
+
+// lockfiles are full of checksums that look like random strings
+var entropyIgnoredFiles = map[string]bool{
+	"go.sum":            true,
+	"package-lock.json": true,
+	"yarn.lock":         true,
+	"pnpm-lock.yaml":    true,
+	"Cargo.lock":        true,
+	"Gemfile.lock":      true,
+	"poetry.lock":       true,
+	"composer.lock":     true,
+}
+
+var entropyCandidateRegex = regexp.MustCompile(`[A-Za-z0-9+/_-]{24,}={0,2}`)
+
+const minEntropy = 4.5
+
+type Finding struct {
+	Kind  string
+	Value string
+	Line  int
+}
+
+type Scanner struct {
+	rules   []rule
+	entropy bool
+	allow   []*regexp.Regexp
+}
+
+func NewScanner(config *Config) *Scanner {
+	scanner := &Scanner{
+		rules:   append([]rule{}, builtinRules...),
+		entropy: config.Entropy == nil || *config.Entropy,
+	}
+
+	var names []string
+	for name := range config.Patterns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// patterns are validated by LoadConfig
+	for _, name := range names {
+		scanner.rules = append(scanner.rules, rule{name, regexp.MustCompile(config.Patterns[name])})
+	}
+
+	for _, pattern := range config.Allow {
+		scanner.allow = append(scanner.allow, regexp.MustCompile(pattern))
+	}
+
+	return scanner
+}
+
+type span struct {
+	start, end int
+	kind       string
+}
+
+// Scan returns the secrets found in text, in order. path may be empty for context that doesn't come from a file.
+func (s *Scanner) Scan(path, text string) []*Finding {
+	var spans []span
+
+	for _, rule := range s.rules {
+		for _, match := range rule.re.FindAllStringSubmatchIndex(text, -1) {
+			start, end := match[0], match[1]
+			if len(match) >= 4 && match[2] >= 0 {
+				start, end = match[2], match[3]
+			}
+			if start == end || s.isAllowed(text[start:end]) || isPlaceholder(text[start:end]) {
+				continue
+			}
+			spans = append(spans, span{start, end, rule.kind})
+		}
+	}
+
+	if s.entropy && !entropyIgnoredFiles[filepath.Base(path)] {
+		for _, match := range entropyCandidateRegex.FindAllStringIndex(text, -1) {
+			value := text[match[0]:match[1]]
+			if !looksRandom(value) || s.isAllowed(value) || isPlaceholder(value) {
+				continue
+			}
+			spans = append(spans, span{match[0], match[1], "high-entropy-string"})
+		}
+	}
+
+	// earlier and longer spans win where rules overlap, then known formats over entropy
+	sort.SliceStable(spans, func(i, j int) bool {
+		if spans[i].start != spans[j].start {
+			return spans[i].start < spans[j].start
+		}
+		return spans[i].end > spans[j].end
+	})
+
+	var findings []*Finding
+	lastEnd := -1
+	for _, sp := range spans {
+		if sp.start < lastEnd {
+			continue
+		}
+		lastEnd = sp.end
+		findings = append(findings, &Finding{
+			Kind:  sp.kind,
+			Value: text[sp.start:sp.end],
+			Line:  strings.Count(text[:sp.start], "\n") + 1,
+		})
+	}
+
+	return findings
+}
+
+func (s *Scanner) isAllowed(value string) bool {
+	for _, re := range s.allow {
+		if re.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// looksRandom is true for strings with high shannon entropy that mix letters and digits, which excludes identifiers, words, and paths
+func looksRandom(value string) bool {
+	var hasUpper, hasLower, hasDigit bool
+	counts := map[rune]int{}
+	for _, c := range value {
+		counts[c]++
+		switch {
+		case c >= 'A' && c <= 'Z':
+			hasUpper = true
+		case c >= 'a' && c <= 'z':
+			hasLower = true
+		case c >= '0' && c <= '9':
+			hasDigit = true
+		}
+	}
+
+	if !hasDigit || !(hasUpper && hasLower) {
+		return false
+	}
+
+	var entropy float64
+	n := float64(len(value))
+	for _, count := range counts {
+		p := float64(count) / n
+		entropy -= p * math.Log2(p)
+	}
+
+	return entropy >= minEntropy
+}
@@ -245,6 +245,15 @@ func (m *streamUIModel) streamUpdate(msg *shared.StreamMessage, deferUIUpdate bo
 			}
 			m.missingFileContent = string(bytes)
 
+			if shared.IsNotebookFile(m.missingFilePath) {
+				m.missingFileContent, err = shared.NotebookToText(m.missingFileContent)
+				if err != nil {
+					log.Println("failed to read notebook:", err)
+					m.err = fmt.Errorf("failed to read notebook: %w", err)
+					return
+				}
+			}
+
 			numTokens, err := shared.GetNumTokens(m.missingFileContent)
 
 			if err != nil {
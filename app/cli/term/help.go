@@ -14,6 +14,7 @@ var CmdDesc = map[string][2]string{
 	"rename":  {"", "rename the current plan"},
 	"current": {"cu", "show current plan"},
 	"cd":      {"", "set current plan by name or index"},
+	"search":  {"", "search plan names, conversations, and commit messages"},
 	"load":    {"l", "load files, dirs, urls, notes, images, or piped data into context"},
 	"tell":    {"t", "describe a task, ask a question, or chat"},
 	"changes": {"ch", "review pending changes in a TUI"},
@@ -27,22 +28,56 @@ var CmdDesc = map[string][2]string{
 	"continue":  {"c", "continue the plan"},
 	// "status":      {"s", "show status of the plan"},
 	"rewind":                    {"rw", "rewind to a previous state"},
+	"edit-prompt":               {"", "edit an earlier prompt and send it on a new branch"},
+	"tag":                       {"", "tag the plan's current state as a named checkpoint"},
+	"log --tags":                {"", "list tagged checkpoints"},
 	"ls":                        {"", "list everything in context"},
 	"rm":                        {"", "remove context by index, range, name, or glob"},
 	"clear":                     {"", "remove all context"},
+	"load --group":              {"", "load context into a named group"},
+	"load --git-diff main":      {"", "load all changes since branching from main"},
+	"load --git-staged":         {"", "load the diff of staged changes"},
+	"load --cmd 'make test'":    {"", "load a command's output, re-run on update"},
+	"load --crawl [url]":        {"", "load a page and the same-site pages it links to"},
+	"load file.go:10-50":        {"", "load a range of lines from a file"},
+	"load 'src/**' '!*.md'":     {"", "load files matching a glob, with exclusions"},
+	"context disable":           {"", "leave a context group out of the prompt"},
+	"context enable":            {"", "include a disabled context group again"},
 	"delete-plan":               {"dp", "delete plan by name or index"},
 	"delete-branch":             {"db", "delete a branch by name or index"},
 	"plans":                     {"pl", "list plans"},
 	"plans --archived":          {"", "list archived plans"},
+	"share":                     {"", "share the current plan with your org"},
+	"share --user":              {"", "share the current plan with a teammate"},
+	"unshare":                   {"", "stop sharing the current plan"},
 	"update":                    {"u", "update outdated context"},
+	"instructions":              {"", "show instructions included in every prompt"},
+	"instructions set-org":      {"", "set org-wide instructions from a file"},
+	"watch":                     {"", "keep context in sync as files change"},
+	"watch --apply":             {"", "also apply changes when the plan finishes"},
+	"prompts":                   {"", "list the prompt templates in effect"},
+	"prompts show":              {"", "show a prompt template"},
+	"prompts edit":              {"", "override a prompt template in your editor"},
+	"prompts reset":             {"", "remove a prompt template override"},
 	"log":                       {"", "show log of plan updates"},
 	"convo":                     {"", "show plan conversation"},
+	"export convo":              {"", "export conversation as markdown, html, or json"},
 	"convo 1":                   {"", "show a specific message in the conversation"},
 	"convo 2-5":                 {"", "show a range of messages in the conversation"},
 	"convo --plain":             {"", "show conversation in plain text"},
 	"branches":                  {"br", "list plan branches"},
 	"checkout":                  {"co", "checkout or create a branch"},
+	"merge":                     {"", "merge a branch into the current branch"},
+	"compare":                   {"", "compare pending changes, context, and convo of two branches"},
 	"build":                     {"b", "build any pending changes"},
+	"tell --auto-context":       {"", "load relevant project files, then send a prompt"},
+	"tell --compare-packs":      {"", "send a prompt with several model packs, each on its own branch"},
+	"replay --pack":             {"", "replay the plan's prompts on a new branch with another model pack"},
+	"chat":                      {"", "ask a question without making changes"},
+	"review":                    {"", "audit pending changes for bugs and security issues"},
+	"review --show":             {"", "show the latest review of pending changes"},
+	"request-review":            {"", "ask teammates to approve pending changes"},
+	"reviews":                   {"", "list requested reviews, approvals, and comments"},
 	"models":                    {"", "show current plan model settings"},
 	"models default":            {"", "show org-wide default model settings for new plans"},
 	"models available":          {"", "show all available models"},
@@ -139,27 +174,27 @@ func PrintCustomHelp(all bool) {
 		fmt.Fprintln(builder)
 
 		color.New(color.Bold, color.BgCyan, color.FgHiWhite).Fprintln(builder, " Plans ")
-		printCmds(builder, " ", []color.Attribute{color.Bold, ColorHiCyan}, "new", "plans", "cd", "current", "delete-plan", "rename", "archive", "plans --archived", "unarchive")
+		printCmds(builder, " ", []color.Attribute{color.Bold, ColorHiCyan}, "new", "plans", "cd", "search", "current", "delete-plan", "rename", "archive", "plans --archived", "unarchive", "share", "share --user", "unshare")
 		fmt.Fprintln(builder)
 
 		color.New(color.Bold, color.BgCyan, color.FgHiWhite).Fprintln(builder, " Changes ")
-		printCmds(builder, " ", []color.Attribute{color.Bold, ColorHiCyan}, "changes", "diff", "apply", "reject")
+		printCmds(builder, " ", []color.Attribute{color.Bold, ColorHiCyan}, "changes", "diff", "review", "review --show", "request-review", "reviews", "apply", "reject")
 		fmt.Fprintln(builder)
 
 		color.New(color.Bold, color.BgCyan, color.FgHiWhite).Fprintln(builder, " Context ")
-		printCmds(builder, " ", []color.Attribute{color.Bold, ColorHiCyan}, "load", "ls", "rm", "update", "clear")
+		printCmds(builder, " ", []color.Attribute{color.Bold, ColorHiCyan}, "load", "ls", "rm", "update", "clear", "load file.go:10-50", "load 'src/**' '!*.md'", "load --git-diff main", "load --git-staged", "load --cmd 'make test'", "load --crawl [url]", "load --group", "watch", "watch --apply", "context disable", "context enable", "instructions", "instructions set-org")
 		fmt.Fprintln(builder)
 
 		color.New(color.Bold, color.BgCyan, color.FgHiWhite).Fprintln(builder, " Branches ")
-		printCmds(builder, " ", []color.Attribute{color.Bold, ColorHiCyan}, "branches", "checkout", "delete-branch")
+		printCmds(builder, " ", []color.Attribute{color.Bold, ColorHiCyan}, "branches", "checkout", "merge", "compare", "replay --pack", "delete-branch")
 		fmt.Fprintln(builder)
 
 		color.New(color.Bold, color.BgCyan, color.FgHiWhite).Fprintln(builder, " History ")
-		printCmds(builder, " ", []color.Attribute{color.Bold, ColorHiCyan}, "log", "rewind", "convo", "convo 1", "convo 2-5", "convo --plain", "summary")
+		printCmds(builder, " ", []color.Attribute{color.Bold, ColorHiCyan}, "log", "rewind", "edit-prompt", "tag", "log --tags", "convo", "convo 1", "convo 2-5", "convo --plain", "export convo", "summary")
 		fmt.Fprintln(builder)
 
 		color.New(color.Bold, color.BgCyan, color.FgHiWhite).Fprintln(builder, " Control ")
-		printCmds(builder, " ", []color.Attribute{color.Bold, ColorHiCyan}, "tell", "continue", "build")
+		printCmds(builder, " ", []color.Attribute{color.Bold, ColorHiCyan}, "tell", "tell --auto-context", "tell --compare-packs", "chat", "continue", "build")
 		fmt.Fprintln(builder)
 
 		color.New(color.Bold, color.BgCyan, color.FgHiWhite).Fprintln(builder, " Streams ")
@@ -167,7 +202,7 @@ func PrintCustomHelp(all bool) {
 		fmt.Fprintln(builder)
 
 		color.New(color.Bold, color.BgCyan, color.FgHiWhite).Fprintln(builder, " AI Models ")
-		printCmds(builder, " ", []color.Attribute{color.Bold, ColorHiCyan}, "models", "models default", "models available", "set-model", "set-model default", "models available --custom", "models add", "models delete", "model-packs", "model-packs --custom", "model-packs create", "model-packs delete")
+		printCmds(builder, " ", []color.Attribute{color.Bold, ColorHiCyan}, "models", "models default", "models available", "set-model", "set-model default", "models available --custom", "models add", "models delete", "model-packs", "model-packs --custom", "model-packs create", "model-packs delete", "prompts", "prompts show", "prompts edit", "prompts reset")
 		fmt.Fprintln(builder)
 
 		color.New(color.Bold, color.BgCyan, color.FgHiWhite).Fprintln(builder, " Accounts ")
@@ -42,6 +42,7 @@ type ApiClient interface {
 	RenameProject(projectId string, req shared.RenameProjectRequest) *shared.ApiError
 
 	ListPlans(projectIds []string) ([]*shared.Plan, *shared.ApiError)
+	SearchPlans(query string, projectIds []string) (*shared.SearchPlansResponse, *shared.ApiError)
 	ListArchivedPlans(projectIds []string) ([]*shared.Plan, *shared.ApiError)
 	ListPlansRunning(projectIds []string, includeRecent bool) (*shared.ListPlansRunningResponse, *shared.ApiError)
 
@@ -69,13 +70,17 @@ type ApiClient interface {
 	RejectFile(planId, branch, filePath string) *shared.ApiError
 	RejectFiles(planId, branch string, paths []string) *shared.ApiError
 	GetPlanDiffs(planId, branch string) (string, *shared.ApiError)
+	ReviewPlan(planId, branch string, req shared.ReviewPlanRequest) (*shared.PlanReview, *shared.ApiError)
 
 	LoadContext(planId, branch string, req shared.LoadContextRequest) (*shared.LoadContextResponse, *shared.ApiError)
 	UpdateContext(planId, branch string, req shared.UpdateContextRequest) (*shared.UpdateContextResponse, *shared.ApiError)
 	DeleteContext(planId, branch string, req shared.DeleteContextRequest) (*shared.DeleteContextResponse, *shared.ApiError)
 	ListContext(planId, branch string) ([]*shared.Context, *shared.ApiError)
+	UpdateContextGroup(planId, branch string, req shared.UpdateContextGroupRequest) (*shared.UpdateContextGroupResponse, *shared.ApiError)
+	ReplayContextChanges(planId, branch string, req shared.ReplayContextChangesRequest) (*shared.ReplayContextChangesResponse, *shared.ApiError)
 
 	ListConvo(planId, branch string) ([]*shared.ConvoMessage, *shared.ApiError)
+	ListConvoDiffs(planId, branch string) (map[string]string, *shared.ApiError)
 	GetPlanStatus(planId, branch string) (string, *shared.ApiError)
 	ListLogs(planId, branch string) (*shared.LogResponse, *shared.ApiError)
 	RewindPlan(planId, branch string, req shared.RewindPlanRequest) (*shared.RewindPlanResponse, *shared.ApiError)
@@ -97,4 +102,23 @@ type ApiClient interface {
 	CreateModelPack(set *shared.ModelPack) *shared.ApiError
 	ListModelPacks() ([]*shared.ModelPack, *shared.ApiError)
 	DeleteModelPack(setId string) *shared.ApiError
+
+	ListPrompts(planId string) ([]*shared.PromptTemplate, *shared.ApiError)
+	GetPromptVersion(name shared.PromptName, version string) (*shared.PromptTemplate, *shared.ApiError)
+	UpdatePrompt(planId string, name shared.PromptName, req shared.UpdatePromptTemplateRequest) (*shared.PromptTemplate, *shared.ApiError)
+	DeletePrompt(planId string, name shared.PromptName) *shared.ApiError
+
+	ListPlanCollaborators(planId string) (*shared.ListPlanCollaboratorsResponse, *shared.ApiError)
+	SharePlan(planId string, req shared.SharePlanRequest) *shared.ApiError
+	UnsharePlan(planId string, req shared.UnsharePlanRequest) *shared.ApiError
+
+	RequestReview(planId, branch string, req shared.RequestReviewRequest) (*shared.PlanReviewState, *shared.ApiError)
+	LeaveReviewFeedback(planId, branch string, req shared.ReviewFeedbackRequest) (*shared.PlanReviewState, *shared.ApiError)
+
+	MergeBranch(planId, branch string, req shared.MergeBranchRequest) (*shared.MergeBranchResponse, *shared.ApiError)
+	CompareBranches(planId, branch, otherBranch string) (*shared.CompareBranchesResponse, *shared.ApiError)
+
+	ListPlanTags(planId, branch string) ([]*shared.PlanTag, *shared.ApiError)
+	CreatePlanTag(planId, branch string, req shared.CreatePlanTagRequest) (*shared.PlanTag, *shared.ApiError)
+	DeletePlanTag(planId, branch, tag string, req shared.DeletePlanTagRequest) *shared.ApiError
 }
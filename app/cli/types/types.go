@@ -27,6 +27,17 @@ type LoadContextParams struct {
 	NamesOnly       bool
 	ForceSkipIgnore bool
 	ImageDetail     openai.ImageURLDetail
+	Group           string
+	Excludes        []string
+	GitDiff         bool
+	GitDiffBase     string
+	GitCommit       string
+	GitStaged       bool
+	Commands        []string
+	Crawl           bool
+	CrawlDepth      int
+	CrawlMaxPages   int
+	CrawlMaxTokens  int
 }
 
 type ContextOutdatedResult struct {
@@ -37,8 +48,15 @@ type ContextOutdatedResult struct {
 	NumFiles        int
 	NumUrls         int
 	NumTrees        int
+	NumGitDiffs     int
+	NumCommands     int
 	NumFilesRemoved int
 	NumTreesRemoved int
+	SecretsSummary  string
+	// contexts that weren't refreshed because they aren't trusted on this machine
+	UntrustedContexts []*shared.Context
+	// file and directory contexts that weren't refreshed because their paths are outside the project
+	InvalidPathContexts []*shared.Context
 }
 
 const (
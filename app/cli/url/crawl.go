@@ -0,0 +1,296 @@
+package url
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/plandex/plandex/shared"
+)
+
+var getNumTokens = shared.GetNumTokens
+
+type CrawlOptions struct {
+	// Depth is how many links away from the root page to follow. 0 loads only the root page.
+	Depth     int
+	MaxPages  int
+	MaxTokens int
+	// Skip is called with each page's url; pages it returns true for aren't loaded, but their links are still followed
+	Skip func(u string) bool
+}
+
+type CrawledPage struct {
+	Url       string
+	Body      string
+	SourceSha string
+	NumTokens int
+}
+
+type CrawlResult struct {
+	Pages       []*CrawledPage
+	NumTokens   int
+	NumFailed   int
+	NumDisallow int
+	// Truncated is set when the page or token limit stopped the crawl before every link was followed
+	Truncated bool
+}
+
+// Crawl loads the root page and the pages it links to on the same host, breadth first, up to opts.Depth links away. Paths disallowed by the host's robots.txt are skipped.
+func Crawl(rootUrl string, opts CrawlOptions) (*CrawlResult, error) {
+	root, err := url.Parse(rootUrl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid url %s: %v", rootUrl, err)
+	}
+	root.Fragment = ""
+
+	// redirects can't leave the root page's host, so a link can't pull in pages from elsewhere
+	sameHost := func(req *http.Request) error {
+		if req.URL.Host != root.Host {
+			return fmt.Errorf("redirect to %s leaves %s", req.URL, root.Host)
+		}
+		return nil
+	}
+
+	robots := fetchRobots(newHttpClient(sameHost), root)
+	if !robots.allowed(root) {
+		return nil, fmt.Errorf("%s is disallowed by robots.txt", rootUrl)
+	}
+
+	client := newHttpClient(func(req *http.Request) error {
+		err := sameHost(req)
+		if err != nil {
+			return err
+		}
+		if !robots.allowed(req.URL) {
+			return fmt.Errorf("redirect to %s is disallowed by robots.txt", req.URL)
+		}
+		return nil
+	})
+
+	res := &CrawlResult{}
+	seen := map[string]bool{root.String(): true}
+	level := []*url.URL{root}
+
+	for depth := 0; depth <= opts.Depth && len(level) > 0; depth++ {
+		var next []*url.URL
+
+		for _, u := range level {
+			if len(res.Pages) >= opts.MaxPages {
+				res.Truncated = true
+				return res, nil
+			}
+
+			content, contentType, err := fetchWithClient(client, u.String())
+			if err != nil {
+				if u == root {
+					return nil, fmt.Errorf("failed to fetch %s: %v", rootUrl, err)
+				}
+				res.NumFailed++
+				continue
+			}
+
+			isHtml := strings.Contains(contentType, "text/html")
+			documentType := getDocumentType(u.String(), contentType)
+			if !isHtml && documentType == "" && !strings.HasPrefix(contentType, "text/") {
+				// images, archives, etc.
+				continue
+			}
+
+			if isHtml && depth < opts.Depth {
+				for _, link := range extractLinks(u, string(content)) {
+					key := link.String()
+					if seen[key] {
+						continue
+					}
+					seen[key] = true
+
+					if !robots.allowed(link) {
+						res.NumDisallow++
+						continue
+					}
+					next = append(next, link)
+				}
+			}
+
+			if opts.Skip != nil && opts.Skip(u.String()) {
+				continue
+			}
+
+			body := string(content)
+			var sourceSha string
+			if documentType != "" {
+				body, err = shared.DocumentToText(documentType, content)
+				if err != nil {
+					res.NumFailed++
+					continue
+				}
+				sourceSha = shared.GetDocumentSha(content)
+			} else if isHtml {
+				body = ExtractTextualContent(body)
+			}
+
+			numTokens, err := getNumTokens(body)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get num tokens for %s: %v", u, err)
+			}
+
+			if res.NumTokens+numTokens > opts.MaxTokens {
+				res.Truncated = true
+				continue
+			}
+
+			res.NumTokens += numTokens
+			res.Pages = append(res.Pages, &CrawledPage{
+				Url:       u.String(),
+				Body:      body,
+				SourceSha: sourceSha,
+				NumTokens: numTokens,
+			})
+		}
+
+		level = next
+	}
+
+	return res, nil
+}
+
+// extractLinks returns the absolute http(s) urls on the same host as base that the page links to, without fragments
+func extractLinks(base *url.URL, htmlContent string) []*url.URL {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
+	if err != nil {
+		return nil
+	}
+
+	var res []*url.URL
+	doc.Find("a[href]").Each(func(i int, s *goquery.Selection) {
+		href, _ := s.Attr("href")
+		link, err := base.Parse(strings.TrimSpace(href))
+		if err != nil {
+			return
+		}
+		if link.Scheme != "http" && link.Scheme != "https" {
+			return
+		}
+		if link.Host != base.Host {
+			return
+		}
+		link.Fragment = ""
+		link.RawFragment = ""
+		res = append(res, link)
+	})
+
+	return res
+}
+
+type robotsRule struct {
+	allow   bool
+	pattern *regexp.Regexp
+	length  int
+}
+
+type robotsRules []robotsRule
+
+// fetchRobots loads the rules for plandex (or for all user agents if there's no plandex group) from the host's robots.txt. A missing or unreadable robots.txt allows everything.
+func fetchRobots(client *http.Client, root *url.URL) robotsRules {
+	robotsUrl := &url.URL{Scheme: root.Scheme, Host: root.Host, Path: "/robots.txt"}
+	content, _, err := fetchWithClient(client, robotsUrl.String())
+	if err != nil {
+		return nil
+	}
+	return parseRobots(string(content))
+}
+
+func parseRobots(content string) robotsRules {
+	groups := map[string]robotsRules{}
+	var agents []string
+	inRules := false
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			// consecutive user-agent lines share a group
+			if inRules {
+				agents = nil
+				inRules = false
+			}
+			agents = append(agents, strings.ToLower(value))
+		case "allow", "disallow":
+			inRules = true
+			if value == "" {
+				// an empty disallow allows everything
+				continue
+			}
+			rule := robotsRule{
+				allow:   key == "allow",
+				pattern: robotsPatternToRegexp(value),
+				length:  len(value),
+			}
+			for _, agent := range agents {
+				groups[agent] = append(groups[agent], rule)
+			}
+		}
+	}
+
+	if rules, ok := groups[userAgent]; ok {
+		return rules
+	}
+	return groups["*"]
+}
+
+// robots.txt paths are prefixes, with '*' matching any sequence and a trailing '$' anchoring the end
+func robotsPatternToRegexp(pattern string) *regexp.Regexp {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+
+	expr := "^" + strings.Join(parts, ".*")
+	if anchored {
+		expr += "$"
+	}
+	return regexp.MustCompile(expr)
+}
+
+// allowed applies the longest matching rule, with allow winning ties
+func (rules robotsRules) allowed(u *url.URL) bool {
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+
+	allowed := true
+	matchLength := -1
+	for _, rule := range rules {
+		if !rule.pattern.MatchString(path) {
+			continue
+		}
+		if rule.length > matchLength || (rule.length == matchLength && rule.allow) {
+			allowed = rule.allow
+			matchLength = rule.length
+		}
+	}
+	return allowed
+}
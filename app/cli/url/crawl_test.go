@@ -0,0 +1,187 @@
+package url
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func newDocsServer(t *testing.T) *httptest.Server {
+	// count words rather than tokens so tests don't need to download the tokenizer
+	getNumTokens = func(text string) (int, error) {
+		return len(strings.Fields(text)), nil
+	}
+
+	pages := map[string]string{
+		"/guide":          `<a href="/guide/intro">Intro</a> <a href="/guide/setup#install">Setup</a> <a href="/private/notes">Notes</a> <a href="https://elsewhere.example/">Elsewhere</a>`,
+		"/guide/intro":    `<p>Intro page</p> <a href="/guide/advanced">Advanced</a> <a href="/guide">Back</a>`,
+		"/guide/setup":    `<p>Setup page</p>`,
+		"/guide/advanced": `<p>Advanced page</p> <a href="/guide/deeper">Deeper</a>`,
+		"/guide/deeper":   `<p>Deeper page</p>`,
+		"/private/notes":  `<p>Private</p>`,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/robots.txt", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "User-agent: *\nDisallow: /private/\n")
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		body, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprintf(w, "<html><body>%s</body></html>", body)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func crawledPaths(server *httptest.Server, res *CrawlResult) []string {
+	var paths []string
+	for _, page := range res.Pages {
+		paths = append(paths, strings.TrimPrefix(page.Url, server.URL))
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func TestCrawlDepth(t *testing.T) {
+	server := newDocsServer(t)
+
+	res, err := Crawl(server.URL+"/guide", CrawlOptions{Depth: 1, MaxPages: 10, MaxTokens: 10000})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := strings.Join(crawledPaths(server, res), ",")
+	if got != "/guide,/guide/intro,/guide/setup" {
+		t.Errorf("unexpected pages at depth 1: %s", got)
+	}
+	if res.NumDisallow != 1 {
+		t.Errorf("expected 1 page disallowed by robots.txt, got %d", res.NumDisallow)
+	}
+	if res.Truncated {
+		t.Errorf("expected crawl not to be truncated")
+	}
+
+	res, err = Crawl(server.URL+"/guide", CrawlOptions{Depth: 2, MaxPages: 10, MaxTokens: 10000})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got = strings.Join(crawledPaths(server, res), ",")
+	if got != "/guide,/guide/advanced,/guide/intro,/guide/setup" {
+		t.Errorf("unexpected pages at depth 2: %s", got)
+	}
+}
+
+func TestCrawlLimits(t *testing.T) {
+	server := newDocsServer(t)
+
+	res, err := Crawl(server.URL+"/guide", CrawlOptions{Depth: 3, MaxPages: 2, MaxTokens: 10000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Pages) != 2 || !res.Truncated {
+		t.Errorf("expected 2 pages and a truncated crawl, got %d pages, truncated=%v", len(res.Pages), res.Truncated)
+	}
+
+	res, err = Crawl(server.URL+"/guide", CrawlOptions{Depth: 3, MaxPages: 10, MaxTokens: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Pages) != 0 || !res.Truncated {
+		t.Errorf("expected no pages and a truncated crawl, got %d pages, truncated=%v", len(res.Pages), res.Truncated)
+	}
+}
+
+func TestRobotsRules(t *testing.T) {
+	rules := parseRobots(`
+User-agent: googlebot
+Disallow: /
+
+User-agent: *
+Disallow: /docs/
+Allow: /docs/public
+Disallow: /*.pdf$
+`)
+
+	tests := map[string]bool{
+		"/":                     true,
+		"/docs/internal":        false,
+		"/docs/public/page":     true,
+		"/guide/manual.pdf":     false,
+		"/guide/manual.pdf?x=1": true,
+	}
+
+	for path, expected := range tests {
+		u, err := url.Parse(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rules.allowed(u) != expected {
+			t.Errorf("expected allowed(%s) to be %v", path, expected)
+		}
+	}
+}
+
+func TestCrawlRedirectsAndUserAgent(t *testing.T) {
+	getNumTokens = func(text string) (int, error) {
+		return len(strings.Fields(text)), nil
+	}
+
+	elsewhere := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("expected crawl not to follow a redirect to another host, got request for %s", r.URL.Path)
+	}))
+	t.Cleanup(elsewhere.Close)
+
+	var userAgents []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		userAgents = append(userAgents, r.UserAgent())
+
+		switch r.URL.Path {
+		case "/guide":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			fmt.Fprint(w, `<html><body><a href="/moved">Moved</a> <a href="/away">Away</a></body></html>`)
+		case "/moved":
+			http.Redirect(w, r, "/guide/setup", http.StatusFound)
+		case "/away":
+			http.Redirect(w, r, elsewhere.URL+"/page", http.StatusFound)
+		case "/guide/setup":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			fmt.Fprint(w, "<html><body><p>Setup page</p></body></html>")
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	res, err := Crawl(server.URL+"/guide", CrawlOptions{Depth: 1, MaxPages: 10, MaxTokens: 10000})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := strings.Join(crawledPaths(server, res), ",")
+	if got != "/guide,/moved" {
+		t.Errorf("unexpected pages: %s", got)
+	}
+	if res.NumFailed != 1 {
+		t.Errorf("expected the redirect to another host to fail, got %d failed", res.NumFailed)
+	}
+
+	for _, userAgent := range userAgents {
+		if userAgent != "plandex" {
+			t.Errorf("expected user agent plandex, got %q", userAgent)
+		}
+	}
+}
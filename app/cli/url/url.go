@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/plandex/plandex/shared"
 )
 
 const (
@@ -18,27 +19,82 @@ const (
 	maxRedirections    = 10
 	httpTimeout        = 30 * time.Second
 	maxContentSizeInMB = 10
+	userAgent          = "plandex"
 )
 
 func FetchURLContent(url string) (string, error) {
-	client := &http.Client{
+	body, _, err := FetchURLContentAndSha(url)
+	return body, err
+}
+
+// FetchURLContentAndSha also returns the sha of the original document when the url is a pdf or office document that was converted to text
+func FetchURLContentAndSha(url string) (string, string, error) {
+	content, contentType, err := fetch(url)
+	if err != nil {
+		return "", "", err
+	}
+
+	if documentType := getDocumentType(url, contentType); documentType != "" {
+		body, err := shared.DocumentToText(documentType, content)
+		if err != nil {
+			return "", "", err
+		}
+		return body, shared.GetDocumentSha(content), nil
+	}
+
+	if strings.Contains(contentType, "text/html") {
+		return ExtractTextualContent(string(content)), "", nil
+	} else {
+		return string(content), "", nil
+	}
+}
+
+// servers often send documents as application/octet-stream, so fall back to the url's extension
+func getDocumentType(url, contentType string) string {
+	if documentType := shared.GetDocumentTypeForContentType(contentType); documentType != "" {
+		return documentType
+	}
+	if strings.HasPrefix(contentType, "text/") {
+		return ""
+	}
+	return shared.GetDocumentType(url)
+}
+
+func fetch(url string) ([]byte, string, error) {
+	return fetchWithClient(newHttpClient(nil), url)
+}
+
+// newHttpClient returns a client that follows up to maxRedirections redirects, each of which must also pass checkRedirect if it isn't nil
+func newHttpClient(checkRedirect func(req *http.Request) error) *http.Client {
+	return &http.Client{
 		Timeout: httpTimeout,
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			if len(via) >= maxRedirections {
 				return errors.New("stopped after too many redirects")
 			}
+			if checkRedirect != nil {
+				return checkRedirect(req)
+			}
 			return nil
 		},
 	}
+}
 
-	resp, err := client.Get(url)
+func fetchWithClient(client *http.Client, url string) ([]byte, string, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
-		return "", err
+		return nil, "", err
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return "", errors.New("non-2xx HTTP response status: " + resp.Status)
+		return nil, "", errors.New("non-2xx HTTP response status: " + resp.Status)
 	}
 
 	// Limit the response reader to a maximum amount
@@ -46,15 +102,10 @@ func FetchURLContent(url string) (string, error) {
 
 	content, err := io.ReadAll(limitedReader)
 	if err != nil {
-		return "", err
+		return nil, "", err
 	}
 
-	contentType := resp.Header.Get("Content-Type")
-	if strings.Contains(contentType, "text/html") {
-		return ExtractTextualContent(string(content)), nil
-	} else {
-		return string(content), nil
-	}
+	return content, resp.Header.Get("Content-Type"), nil
 }
 
 func ExtractTextualContent(htmlContent string) string {
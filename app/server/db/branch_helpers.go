@@ -184,6 +184,19 @@ func DeleteBranch(orgId, planId, branch string) error {
 		return fmt.Errorf("error deleting branch: %v", err)
 	}
 
+	// review state is keyed by branch name, so a later branch with the same name shouldn't inherit it
+	_, err = tx.Exec("DELETE FROM plan_review_requests WHERE plan_id = $1 AND branch = $2", planId, branch)
+
+	if err != nil {
+		return fmt.Errorf("error deleting review requests: %v", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM plan_review_feedback WHERE plan_id = $1 AND branch = $2", planId, branch)
+
+	if err != nil {
+		return fmt.Errorf("error deleting review feedback: %v", err)
+	}
+
 	err = IncActiveBranches(planId, -1, tx)
 
 	if err != nil {
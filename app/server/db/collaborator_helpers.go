@@ -0,0 +1,105 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/plandex/plandex/shared"
+)
+
+func GetPlanCollaboratorAccess(planId, userId string) (shared.PlanAccess, error) {
+	var access string
+	err := Conn.Get(&access, "SELECT access FROM plan_collaborators WHERE plan_id = $1 AND user_id = $2", planId, userId)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", fmt.Errorf("error getting plan collaborator: %v", err)
+	}
+
+	return shared.PlanAccess(access), nil
+}
+
+func ListPlanCollaborators(planId string) ([]*shared.PlanCollaborator, error) {
+	var rows []struct {
+		PlanCollaborator
+		UserName  string `db:"user_name"`
+		UserEmail string `db:"user_email"`
+	}
+
+	query := `SELECT plan_collaborators.*, users.name AS user_name, users.email AS user_email
+	FROM plan_collaborators
+	JOIN users ON users.id = plan_collaborators.user_id
+	WHERE plan_collaborators.plan_id = $1
+	ORDER BY plan_collaborators.created_at`
+
+	err := Conn.Select(&rows, query, planId)
+
+	if err != nil {
+		return nil, fmt.Errorf("error listing plan collaborators: %v", err)
+	}
+
+	var res []*shared.PlanCollaborator
+	for _, row := range rows {
+		res = append(res, &shared.PlanCollaborator{
+			UserId:    row.UserId,
+			UserName:  row.UserName,
+			UserEmail: row.UserEmail,
+			Access:    shared.PlanAccess(row.Access),
+			CreatedAt: row.CreatedAt,
+		})
+	}
+
+	return res, nil
+}
+
+func StorePlanCollaborator(orgId, planId, userId string, access shared.PlanAccess) error {
+	query := `INSERT INTO plan_collaborators (org_id, plan_id, user_id, access) VALUES ($1, $2, $3, $4)
+	ON CONFLICT (plan_id, user_id) DO UPDATE SET access = excluded.access`
+
+	_, err := Conn.Exec(query, orgId, planId, userId, access)
+
+	if err != nil {
+		return fmt.Errorf("error storing plan collaborator: %v", err)
+	}
+
+	return nil
+}
+
+// DeletePlanCollaborator removes a collaborator, returning false if the plan wasn't shared with them
+func DeletePlanCollaborator(planId, userId string) (bool, error) {
+	res, err := Conn.Exec("DELETE FROM plan_collaborators WHERE plan_id = $1 AND user_id = $2", planId, userId)
+
+	if err != nil {
+		return false, fmt.Errorf("error deleting plan collaborator: %v", err)
+	}
+
+	n, err := res.RowsAffected()
+
+	if err != nil {
+		return false, fmt.Errorf("error getting rows affected: %v", err)
+	}
+
+	return n > 0, nil
+}
+
+func SharePlanWithOrg(planId string, access shared.PlanAccess) error {
+	_, err := Conn.Exec("UPDATE plans SET shared_with_org_at = COALESCE(shared_with_org_at, NOW()), shared_with_org_access = $1 WHERE id = $2", access, planId)
+
+	if err != nil {
+		return fmt.Errorf("error sharing plan with org: %v", err)
+	}
+
+	return nil
+}
+
+func UnsharePlanWithOrg(planId string) error {
+	_, err := Conn.Exec("UPDATE plans SET shared_with_org_at = NULL, shared_with_org_access = NULL WHERE id = $1", planId)
+
+	if err != nil {
+		return fmt.Errorf("error unsharing plan with org: %v", err)
+	}
+
+	return nil
+}
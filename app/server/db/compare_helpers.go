@@ -0,0 +1,153 @@
+package db
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/plandex/plandex/shared"
+)
+
+type BranchCompareState struct {
+	Branch      *Branch
+	PlanState   *shared.CurrentPlanState
+	Contexts    []*Context
+	NumMessages int
+}
+
+// GetBranchCompareState loads what's needed to compare a branch with another one. The branch must be checked out.
+func GetBranchCompareState(orgId, planId, branch string) (*BranchCompareState, error) {
+	dbBranch, err := GetDbBranch(planId, branch)
+
+	if err != nil {
+		return nil, fmt.Errorf("error getting branch: %v", err)
+	}
+
+	if dbBranch == nil {
+		return nil, fmt.Errorf("branch not found: %s", branch)
+	}
+
+	contexts, err := GetPlanContexts(orgId, planId, true)
+
+	if err != nil {
+		return nil, fmt.Errorf("error getting plan contexts: %v", err)
+	}
+
+	planState, err := GetCurrentPlanState(CurrentPlanStateParams{
+		OrgId:    orgId,
+		PlanId:   planId,
+		Contexts: contexts,
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("error getting current plan state: %v", err)
+	}
+
+	convo, err := GetPlanConvo(orgId, planId)
+
+	if err != nil {
+		return nil, fmt.Errorf("error getting plan convo: %v", err)
+	}
+
+	return &BranchCompareState{
+		Branch:      dbBranch,
+		PlanState:   planState,
+		Contexts:    contexts,
+		NumMessages: len(convo),
+	}, nil
+}
+
+// CompareBranches diffs the files as they'd be after applying each branch's pending changes, and summarizes how the branches' context and conversations differ
+func CompareBranches(orgId string, a, b *BranchCompareState) (*shared.CompareBranchesResponse, error) {
+	filesA := a.pendingFileStates()
+	filesB := b.pendingFileStates()
+
+	// a path pending on only one branch is compared with its current state on the other
+	for path := range filesA {
+		if _, ok := filesB[path]; !ok {
+			if context, ok := b.PlanState.ContextsByPath[path]; ok {
+				filesB[path] = context.Body
+			}
+		}
+	}
+	for path := range b.PlanState.CurrentPlanFiles.Files {
+		if _, ok := filesA[path]; !ok {
+			if context, ok := a.PlanState.ContextsByPath[path]; ok {
+				filesA[path] = context.Body
+			}
+		}
+	}
+
+	diffs, err := getFilesDiff(orgId, filesA, filesB, false)
+
+	if err != nil {
+		return nil, fmt.Errorf("error getting diffs: %v", err)
+	}
+
+	res := shared.CompareBranchesResponse{
+		A:     a.summary(),
+		B:     b.summary(),
+		Diffs: diffs,
+	}
+
+	contextsA := contextsByCompareKey(a.Contexts)
+	contextsB := contextsByCompareKey(b.Contexts)
+
+	for key, context := range contextsA {
+		other, ok := contextsB[key]
+		if !ok {
+			res.ContextOnlyA = append(res.ContextOnlyA, context.Name)
+		} else if other.Sha != context.Sha {
+			res.ContextChanged = append(res.ContextChanged, context.Name)
+		}
+	}
+	for key, context := range contextsB {
+		if _, ok := contextsA[key]; !ok {
+			res.ContextOnlyB = append(res.ContextOnlyB, context.Name)
+		}
+	}
+
+	sort.Strings(res.ContextOnlyA)
+	sort.Strings(res.ContextOnlyB)
+	sort.Strings(res.ContextChanged)
+
+	return &res, nil
+}
+
+func (state *BranchCompareState) pendingFileStates() map[string]string {
+	files := map[string]string{}
+	for path, file := range state.PlanState.CurrentPlanFiles.Files {
+		files[path] = file
+	}
+	return files
+}
+
+func (state *BranchCompareState) summary() shared.BranchComparisonSummary {
+	summary := shared.BranchComparisonSummary{
+		Name:          state.Branch.Name,
+		NumContexts:   len(state.Contexts),
+		ContextTokens: state.Branch.ContextTokens,
+		NumMessages:   state.NumMessages,
+		ConvoTokens:   state.Branch.ConvoTokens,
+	}
+
+	for path := range state.PlanState.CurrentPlanFiles.Files {
+		summary.PendingPaths = append(summary.PendingPaths, path)
+	}
+	sort.Strings(summary.PendingPaths)
+
+	return summary
+}
+
+// contextsByCompareKey keys contexts by what they load rather than by id, since ids differ for the same context loaded separately on each branch
+func contextsByCompareKey(contexts []*Context) map[string]*Context {
+	res := map[string]*Context{}
+	for _, context := range contexts {
+		key := strings.Join([]string{string(context.ContextType), context.Name, context.FilePath, context.Url, context.Command, context.GitRef}, "|")
+		if context.LineRange != nil {
+			key += "|" + context.LineRange.Label()
+		}
+		res[key] = context
+	}
+	return res
+}
@@ -106,7 +106,9 @@ func ContextRemove(orgId, planId string, contexts []*Context) error {
 
 	errCh := make(chan error, numFiles)
 	for _, context := range contexts {
-		filesToUpdate[context.FilePath] = ""
+		if context.LineRange == nil {
+			filesToUpdate[context.FilePath] = ""
+		}
 		contextDir := getPlanContextDir(orgId, planId)
 		for _, ext := range []string{".meta", ".body"} {
 			go func(context *Context, dir, ext string) {
@@ -178,6 +180,29 @@ func StoreContext(context *Context) error {
 	return nil
 }
 
+// StoreContextMeta updates a context's meta file without touching its body
+func StoreContextMeta(context *Context) error {
+	contextDir := getPlanContextDir(context.OrgId, context.PlanId)
+	metaPath := filepath.Join(contextDir, context.Id+".meta")
+
+	context.UpdatedAt = time.Now().UTC()
+
+	body := context.Body
+	context.Body = ""
+	data, err := json.MarshalIndent(context, "", "  ")
+	context.Body = body
+
+	if err != nil {
+		return fmt.Errorf("failed to marshal context: %v", err)
+	}
+
+	if err = os.WriteFile(metaPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write context meta to file %s: %v", metaPath, err)
+	}
+
+	return nil
+}
+
 type LoadContextsParams struct {
 	Req                      *shared.LoadContextRequest
 	OrgId                    string
@@ -197,7 +222,7 @@ func LoadContexts(params LoadContextsParams) (*shared.LoadContextResponse, []*Co
 
 	filesToLoad := map[string]string{}
 	for _, context := range *req {
-		if context.ContextType == shared.ContextFileType {
+		if context.ContextType == shared.ContextFileType && context.LineRange == nil {
 			filesToLoad[context.FilePath] = context.Body
 		}
 	}
@@ -227,6 +252,11 @@ func LoadContexts(params LoadContextsParams) (*shared.LoadContextResponse, []*Co
 
 	maxTokens := settings.GetPlannerEffectiveMaxTokens()
 
+	disabledGroups, err := getDisabledContextGroups(orgId, planId, req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting disabled context groups: %v", err)
+	}
+
 	for _, context := range *req {
 		tempId := uuid.New().String()
 
@@ -246,6 +276,11 @@ func LoadContexts(params LoadContextsParams) (*shared.LoadContextResponse, []*Co
 		paramsByTempId[tempId] = context
 		numTokensByTempId[tempId] = numTokens
 
+		// contexts loaded into a disabled group stay disabled and don't count toward the total
+		if disabledGroups[context.Group] {
+			continue
+		}
+
 		tokensAdded += numTokens
 		totalTokens += numTokens
 	}
@@ -281,6 +316,13 @@ func LoadContexts(params LoadContextsParams) (*shared.LoadContextResponse, []*Co
 				Body:            params.Body,
 				ForceSkipIgnore: params.ForceSkipIgnore,
 				ImageDetail:     params.ImageDetail,
+				Group:           params.Group,
+				Disabled:        disabledGroups[params.Group],
+				LineRange:       params.LineRange,
+				GitRef:          params.GitRef,
+				Command:         params.Command,
+				SourceSha:       params.SourceSha,
+				LoadedFrom:      params.LoadedFrom,
 			}
 
 			err := StoreContext(&context)
@@ -376,6 +418,8 @@ func UpdateContexts(params UpdateContextsParams) (*shared.UpdateContextResponse,
 	numFiles := 0
 	numUrls := 0
 	numTrees := 0
+	numGitDiffs := 0
+	numCommands := 0
 
 	var mu sync.Mutex
 	errCh := make(chan error)
@@ -396,6 +440,12 @@ func UpdateContexts(params UpdateContextsParams) (*shared.UpdateContextResponse,
 				}
 			}
 
+			// contexts stored before paths were validated can't be updated until they're removed and loaded again
+			if err := shared.ValidateContextPath(context.FilePath); err != nil {
+				errCh <- err
+				return
+			}
+
 			mu.Lock()
 			defer mu.Unlock()
 
@@ -418,8 +468,12 @@ func UpdateContexts(params UpdateContextsParams) (*shared.UpdateContextResponse,
 
 			tokenDiff := updateNumTokens - context.NumTokens
 			tokenDiffsById[id] = tokenDiff
-			tokensDiff += tokenDiff
-			totalTokens += tokenDiff
+
+			// disabled contexts are kept up to date but don't count toward the total
+			if !context.Disabled {
+				tokensDiff += tokenDiff
+				totalTokens += tokenDiff
+			}
 
 			context.NumTokens = updateNumTokens
 
@@ -430,6 +484,10 @@ func UpdateContexts(params UpdateContextsParams) (*shared.UpdateContextResponse,
 				numUrls++
 			case shared.ContextDirectoryTreeType:
 				numTrees++
+			case shared.ContextGitDiffType, shared.ContextGitCommitType, shared.ContextGitStagedType:
+				numGitDiffs++
+			case shared.ContextCommandType:
+				numCommands++
 			}
 
 			errCh <- nil
@@ -451,6 +509,8 @@ func UpdateContexts(params UpdateContextsParams) (*shared.UpdateContextResponse,
 		NumFiles:        numFiles,
 		NumUrls:         numUrls,
 		NumTrees:        numTrees,
+		NumGitDiffs:     numGitDiffs,
+		NumCommands:     numCommands,
 		MaxTokens:       maxTokens,
 	}
 
@@ -465,7 +525,7 @@ func UpdateContexts(params UpdateContextsParams) (*shared.UpdateContextResponse,
 
 	filesToLoad := map[string]string{}
 	for _, context := range updatedContexts {
-		if context.ContextType == shared.ContextFileType {
+		if context.ContextType == shared.ContextFileType && context.LineRange == nil {
 			filesToLoad[context.FilePath] = (*req)[context.Id].Body
 		}
 	}
@@ -489,6 +549,12 @@ func UpdateContexts(params UpdateContextsParams) (*shared.UpdateContextResponse,
 
 			context.Body = params.Body
 			context.Sha = sha
+			if params.LineRange != nil {
+				context.LineRange = params.LineRange
+			}
+			if params.SourceSha != "" {
+				context.SourceSha = params.SourceSha
+			}
 
 			err := StoreContext(context)
 
@@ -522,6 +588,36 @@ func UpdateContexts(params UpdateContextsParams) (*shared.UpdateContextResponse,
 	}, nil
 }
 
+// getDisabledContextGroups returns the groups in the request that already have disabled contexts
+func getDisabledContextGroups(orgId, planId string, req *shared.LoadContextRequest) (map[string]bool, error) {
+	res := map[string]bool{}
+
+	hasGroup := false
+	for _, params := range *req {
+		if params.Group != "" {
+			hasGroup = true
+			break
+		}
+	}
+
+	if !hasGroup {
+		return res, nil
+	}
+
+	contexts, err := GetPlanContexts(orgId, planId, false)
+	if err != nil {
+		return nil, fmt.Errorf("error getting contexts: %v", err)
+	}
+
+	for _, context := range contexts {
+		if context.Group != "" && context.Disabled {
+			res[context.Group] = true
+		}
+	}
+
+	return res, nil
+}
+
 func invalidateConflictedResults(orgId, planId string, filesToUpdate map[string]string) error {
 	descriptions, err := GetConvoMessageDescriptions(orgId, planId)
 	if err != nil {
@@ -0,0 +1,172 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/plandex/plandex/shared"
+)
+
+// GetConvoMessageDiffs returns the diff of the files each reply built, keyed by the reply's message id. Each reply's results are replayed on the plan's state from git history just before they were first written, so context updates and applied changes since then don't shift the diff. Rejected results are skipped.
+func GetConvoMessageDiffs(orgId, planId string) (map[string]string, error) {
+	results, err := GetPlanFileResults(orgId, planId)
+
+	if err != nil {
+		return nil, fmt.Errorf("error getting plan file results: %v", err)
+	}
+
+	var messageIds []string
+	resultsByMessageId := map[string][]*PlanFileResult{}
+	for _, result := range results {
+		msgId := result.ConvoMessageId
+		if resultsByMessageId[msgId] == nil {
+			messageIds = append(messageIds, msgId)
+		}
+		resultsByMessageId[msgId] = append(resultsByMessageId[msgId], result)
+	}
+
+	res := map[string]string{}
+	for _, msgId := range messageIds {
+		msgResults := resultsByMessageId[msgId]
+
+		var resultPaths []string
+		for _, result := range msgResults {
+			resultPaths = append(resultPaths, filepath.Join("results", result.Id+".json"))
+		}
+
+		sha, err := GitShaBeforeFilesAdded(orgId, planId, "HEAD", resultPaths)
+
+		if err != nil {
+			return nil, fmt.Errorf("error getting commit before message %s: %v", msgId, err)
+		}
+
+		original, updated, err := replayMessageResults(orgId, planId, sha, msgResults)
+
+		if err != nil {
+			return nil, fmt.Errorf("error replaying results for message %s: %v", msgId, err)
+		}
+
+		if len(updated) == 0 {
+			continue
+		}
+
+		diff, err := getFilesDiff(orgId, original, updated, true)
+
+		if err != nil {
+			return nil, fmt.Errorf("error getting diff for message %s: %v", msgId, err)
+		}
+
+		res[msgId] = diff
+	}
+
+	return res, nil
+}
+
+// replayMessageResults applies a reply's results in order on top of the plan's files as of sha, returning each file's content before and after. Files the reply created are left out of original.
+func replayMessageResults(orgId, planId, sha string, results []*PlanFileResult) (original, updated map[string]string, err error) {
+	planState, err := getPlanStateAtCommit(orgId, planId, sha)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	original = map[string]string{}
+	updated = map[string]string{}
+
+	for _, result := range results {
+		if result.RejectedAt != nil {
+			continue
+		}
+
+		before, exists := updated[result.Path]
+		if !exists {
+			if content, ok := planState.CurrentPlanFiles.Files[result.Path]; ok {
+				before, exists = content, true
+			} else if context, ok := planState.ContextsByPath[result.Path]; ok {
+				before, exists = context.Body, true
+			}
+
+			if exists {
+				original[result.Path] = before
+			}
+		}
+
+		after := result.Content
+		if len(result.Replacements) > 0 {
+			withLineNums := before
+			if result.ReplaceWithLineNums {
+				withLineNums = shared.AddLineNums(before)
+			}
+
+			var allSucceeded bool
+			after, allSucceeded = shared.ApplyReplacements(withLineNums, result.Replacements, false)
+
+			if !allSucceeded {
+				return nil, nil, fmt.Errorf("replacements failed for %s in result %s", result.Path, result.Id)
+			}
+
+			after = shared.RemoveLineNums(after)
+		}
+
+		updated[result.Path] = after
+	}
+
+	return original, updated, nil
+}
+
+// getPlanStateAtCommit reads context and results from a commit in the plan's history and returns the plan's state as it was then
+func getPlanStateAtCommit(orgId, planId, sha string) (*shared.CurrentPlanState, error) {
+	contextFiles, err := GitReadBranchFiles(orgId, planId, sha, "context")
+
+	if err != nil {
+		return nil, fmt.Errorf("error reading context at %s: %v", sha, err)
+	}
+
+	resultFiles, err := GitReadBranchFiles(orgId, planId, sha, "results")
+
+	if err != nil {
+		return nil, fmt.Errorf("error reading results at %s: %v", sha, err)
+	}
+
+	// non-nil so GetCurrentPlanState doesn't read the checked out files instead
+	contexts := []*Context{}
+	for name, bytes := range contextFiles {
+		if !strings.HasSuffix(name, ".meta") {
+			continue
+		}
+
+		var context Context
+		err := json.Unmarshal(bytes, &context)
+		if err != nil {
+			return nil, fmt.Errorf("error unmarshalling context %s: %v", name, err)
+		}
+
+		context.Body = string(contextFiles[strings.TrimSuffix(name, ".meta")+".body"])
+		contexts = append(contexts, &context)
+	}
+
+	results := []*PlanFileResult{}
+	for name, bytes := range resultFiles {
+		var result PlanFileResult
+		err := json.Unmarshal(bytes, &result)
+		if err != nil {
+			return nil, fmt.Errorf("error unmarshalling result %s: %v", name, err)
+		}
+		results = append(results, &result)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].CreatedAt.Before(results[j].CreatedAt)
+	})
+
+	return GetCurrentPlanState(CurrentPlanStateParams{
+		OrgId:                    orgId,
+		PlanId:                   planId,
+		PlanFileResults:          results,
+		Contexts:                 contexts,
+		ConvoMessageDescriptions: []*ConvoMessageDescription{},
+	})
+}
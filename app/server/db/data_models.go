@@ -121,34 +121,51 @@ func (project *Project) ToApi() *shared.Project {
 }
 
 type Plan struct {
-	Id              string     `db:"id"`
-	OrgId           string     `db:"org_id"`
-	OwnerId         string     `db:"owner_id"`
-	ProjectId       string     `db:"project_id"`
-	Name            string     `db:"name"`
-	SharedWithOrgAt *time.Time `db:"shared_with_org_at,omitempty"`
-	TotalReplies    int        `db:"total_replies"`
-	ActiveBranches  int        `db:"active_branches"`
-	ArchivedAt      *time.Time `db:"archived_at,omitempty"`
-	CreatedAt       time.Time  `db:"created_at"`
-	UpdatedAt       time.Time  `db:"updated_at"`
+	Id                  string     `db:"id"`
+	OrgId               string     `db:"org_id"`
+	OwnerId             string     `db:"owner_id"`
+	ProjectId           string     `db:"project_id"`
+	Name                string     `db:"name"`
+	SharedWithOrgAt     *time.Time `db:"shared_with_org_at,omitempty"`
+	SharedWithOrgAccess *string    `db:"shared_with_org_access,omitempty"`
+	TotalReplies        int        `db:"total_replies"`
+	ActiveBranches      int        `db:"active_branches"`
+	ArchivedAt          *time.Time `db:"archived_at,omitempty"`
+	CreatedAt           time.Time  `db:"created_at"`
+	UpdatedAt           time.Time  `db:"updated_at"`
 }
 
 func (plan *Plan) ToApi() *shared.Plan {
+	var sharedWithOrgAccess shared.PlanAccess
+	if plan.SharedWithOrgAt != nil && plan.SharedWithOrgAccess != nil {
+		sharedWithOrgAccess = shared.PlanAccess(*plan.SharedWithOrgAccess)
+	}
+
 	return &shared.Plan{
-		Id:              plan.Id,
-		OwnerId:         plan.OwnerId,
-		ProjectId:       plan.ProjectId,
-		Name:            plan.Name,
-		SharedWithOrgAt: plan.SharedWithOrgAt,
-		TotalReplies:    plan.TotalReplies,
-		ActiveBranches:  plan.ActiveBranches,
-		ArchivedAt:      plan.ArchivedAt,
-		CreatedAt:       plan.CreatedAt,
-		UpdatedAt:       plan.UpdatedAt,
+		Id:                  plan.Id,
+		OwnerId:             plan.OwnerId,
+		ProjectId:           plan.ProjectId,
+		Name:                plan.Name,
+		SharedWithOrgAt:     plan.SharedWithOrgAt,
+		SharedWithOrgAccess: sharedWithOrgAccess,
+		TotalReplies:        plan.TotalReplies,
+		ActiveBranches:      plan.ActiveBranches,
+		ArchivedAt:          plan.ArchivedAt,
+		CreatedAt:           plan.CreatedAt,
+		UpdatedAt:           plan.UpdatedAt,
 	}
 }
 
+type PlanCollaborator struct {
+	Id        string    `db:"id"`
+	OrgId     string    `db:"org_id"`
+	PlanId    string    `db:"plan_id"`
+	UserId    string    `db:"user_id"`
+	Access    string    `db:"access"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
 type Branch struct {
 	Id              string            `db:"id"`
 	OrgId           string            `db:"org_id"`
@@ -168,6 +185,11 @@ type Branch struct {
 }
 
 func (branch *Branch) ToApi() *shared.Branch {
+	var errStr string
+	if branch.Error != nil {
+		errStr = *branch.Error
+	}
+
 	return &shared.Branch{
 		Id:              branch.Id,
 		PlanId:          branch.PlanId,
@@ -175,6 +197,7 @@ func (branch *Branch) ToApi() *shared.Branch {
 		ParentBranchId:  branch.ParentBranchId,
 		Name:            branch.Name,
 		Status:          branch.Status,
+		Error:           errStr,
 		ContextTokens:   branch.ContextTokens,
 		ConvoTokens:     branch.ConvoTokens,
 		SharedWithOrgAt: branch.SharedWithOrgAt,
@@ -372,6 +395,32 @@ type DefaultPlanSettings struct {
 	UpdatedAt    time.Time           `db:"updated_at"`
 }
 
+type PromptTemplate struct {
+	Id        string    `db:"id"`
+	OrgId     string    `db:"org_id"`
+	PlanId    *string   `db:"plan_id"`
+	Name      string    `db:"name"`
+	Template  string    `db:"template"`
+	Version   string    `db:"version"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func (t *PromptTemplate) ToApi() *shared.PromptTemplate {
+	scope := shared.PromptScopeOrg
+	if t.PlanId != nil {
+		scope = shared.PromptScopePlan
+	}
+
+	return &shared.PromptTemplate{
+		Name:      shared.PromptName(t.Name),
+		Scope:     scope,
+		Template:  t.Template,
+		Version:   t.Version,
+		UpdatedAt: &t.UpdatedAt,
+	}
+}
+
 // Models below are stored in files, not in the database.
 // This allows us to store them in a git repo and use git to manage history.
 
@@ -385,10 +434,17 @@ type Context struct {
 	Url             string                `json:"url"`
 	FilePath        string                `json:"filePath"`
 	Sha             string                `json:"sha"`
+	SourceSha       string                `json:"sourceSha,omitempty"`
+	LoadedFrom      string                `json:"loadedFrom,omitempty"`
 	NumTokens       int                   `json:"numTokens"`
 	Body            string                `json:"body,omitempty"`
 	ForceSkipIgnore bool                  `json:"forceSkipIgnore"`
 	ImageDetail     openai.ImageURLDetail `json:"imageDetail,omitempty"`
+	Group           string                `json:"group,omitempty"`
+	Disabled        bool                  `json:"disabled,omitempty"`
+	LineRange       *shared.LineRange     `json:"lineRange,omitempty"`
+	GitRef          string                `json:"gitRef,omitempty"`
+	Command         string                `json:"command,omitempty"`
 	CreatedAt       time.Time             `json:"createdAt"`
 	UpdatedAt       time.Time             `json:"updatedAt"`
 }
@@ -405,34 +461,47 @@ func (context *Context) ToApi() *shared.Context {
 		NumTokens:       context.NumTokens,
 		Body:            context.Body,
 		ForceSkipIgnore: context.ForceSkipIgnore,
+		Group:           context.Group,
+		Disabled:        context.Disabled,
+		LineRange:       context.LineRange,
+		GitRef:          context.GitRef,
+		Command:         context.Command,
+		SourceSha:       context.SourceSha,
+		LoadedFrom:      context.LoadedFrom,
 		CreatedAt:       context.CreatedAt,
 		UpdatedAt:       context.UpdatedAt,
 	}
 }
 
 type ConvoMessage struct {
-	Id        string    `json:"id"`
-	OrgId     string    `json:"orgId"`
-	PlanId    string    `json:"planId"`
-	UserId    string    `json:"userId"`
-	Role      string    `json:"role"`
-	Tokens    int       `json:"tokens"`
-	Num       int       `json:"num"`
-	Message   string    `json:"message"`
-	Stopped   bool      `json:"stopped"`
-	CreatedAt time.Time `json:"createdAt"`
+	Id      string `json:"id"`
+	OrgId   string `json:"orgId"`
+	PlanId  string `json:"planId"`
+	UserId  string `json:"userId"`
+	Role    string `json:"role"`
+	Tokens  int    `json:"tokens"`
+	Num     int    `json:"num"`
+	Message string `json:"message"`
+	Stopped bool   `json:"stopped"`
+	// IsChat marks messages sent with `plandex chat`, which are answered without making changes to files
+	IsChat bool `json:"isChat,omitempty"`
+	// PromptVersion is the version of the system prompt that produced an assistant message
+	PromptVersion string    `json:"promptVersion,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
 }
 
 func (msg *ConvoMessage) ToApi() *shared.ConvoMessage {
 	return &shared.ConvoMessage{
-		Id:        msg.Id,
-		UserId:    msg.UserId,
-		Role:      msg.Role,
-		Tokens:    msg.Tokens,
-		Num:       msg.Num,
-		Message:   msg.Message,
-		Stopped:   msg.Stopped,
-		CreatedAt: msg.CreatedAt,
+		Id:            msg.Id,
+		UserId:        msg.UserId,
+		Role:          msg.Role,
+		Tokens:        msg.Tokens,
+		Num:           msg.Num,
+		Message:       msg.Message,
+		Stopped:       msg.Stopped,
+		IsChat:        msg.IsChat,
+		PromptVersion: msg.PromptVersion,
+		CreatedAt:     msg.CreatedAt,
 	}
 }
 
@@ -448,6 +517,7 @@ type ConvoMessageDescription struct {
 	Error                 string          `json:"error"`
 	DidBuild              bool            `json:"didBuild"`
 	BuildPathsInvalidated map[string]bool `json:"buildPathsInvalidated"`
+	BuildPromptVersions   []string        `json:"buildPromptVersions,omitempty"`
 	AppliedAt             *time.Time      `json:"appliedAt,omitempty"`
 	CreatedAt             time.Time       `json:"createdAt"`
 	UpdatedAt             time.Time       `json:"updatedAt"`
@@ -462,6 +532,7 @@ func (desc *ConvoMessageDescription) ToApi() *shared.ConvoMessageDescription {
 		CommitMsg:             desc.CommitMsg,
 		Files:                 desc.Files,
 		DidBuild:              desc.DidBuild,
+		BuildPromptVersions:   desc.BuildPromptVersions,
 		BuildPathsInvalidated: desc.BuildPathsInvalidated,
 		AppliedAt:             desc.AppliedAt,
 		Error:                 desc.Error,
@@ -491,12 +562,17 @@ type PlanFileResult struct {
 	WillCheckSyntax bool     `json:"willCheckSyntax"`
 	SyntaxValid     bool     `json:"syntaxValid"`
 	SyntaxErrors    []string `json:"syntaxErrors"`
+	// violations of the project's JSON Schema for the file, which also make SyntaxValid false
+	SchemaErrors []string `json:"schemaErrors,omitempty"`
 
 	IsFix       bool `json:"isFix"`
 	IsSyntaxFix bool `json:"isSyntaxFix"`
 	IsOtherFix  bool `json:"isOtherFix"`
 	FixEpoch    int  `json:"fixEpoch"`
 
+	PromptVersion         string `json:"promptVersion,omitempty"`
+	VerifierPromptVersion string `json:"verifierPromptVersion,omitempty"`
+
 	AppliedAt  *time.Time `json:"appliedAt,omitempty"`
 	RejectedAt *time.Time `json:"rejectedAt,omitempty"`
 	CreatedAt  time.Time  `json:"createdAt"`
@@ -524,5 +600,8 @@ func (res *PlanFileResult) ToApi() *shared.PlanFileResult {
 		IsOtherFix:          res.IsOtherFix,
 		CreatedAt:           res.CreatedAt,
 		UpdatedAt:           res.UpdatedAt,
+
+		PromptVersion:         res.PromptVersion,
+		VerifierPromptVersion: res.VerifierPromptVersion,
 	}
 }
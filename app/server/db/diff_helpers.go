@@ -6,11 +6,9 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
-
-	"github.com/plandex/plandex/shared"
 )
 
-func GetPlanDiffs(orgId, planId string) (string, error) {
+func GetPlanDiffs(orgId, planId string, plain bool) (string, error) {
 	planState, err := GetCurrentPlanState(CurrentPlanStateParams{
 		OrgId:  orgId,
 		PlanId: planId,
@@ -20,6 +18,20 @@ func GetPlanDiffs(orgId, planId string) (string, error) {
 		return "", fmt.Errorf("error getting current plan state: %v", err)
 	}
 
+	files := planState.CurrentPlanFiles.Files
+
+	original := map[string]string{}
+	for path, context := range planState.ContextsByPath {
+		if _, hasPath := files[path]; hasPath {
+			original[path] = context.Body
+		}
+	}
+
+	return getFilesDiff(orgId, original, files, plain)
+}
+
+// getFilesDiff writes the original files to a temp git repo, commits them, then stages the updated files over them and returns the staged diff
+func getFilesDiff(orgId string, original, updated map[string]string, plain bool) (string, error) {
 	// create temp directory
 	tempDirPath, err := os.MkdirTemp(getOrgDir(orgId), "tmp-diffs-*")
 
@@ -38,42 +50,14 @@ func GetPlanDiffs(orgId, planId string) (string, error) {
 		return "", fmt.Errorf("error initializing git repo: %v", err)
 	}
 
-	files := planState.CurrentPlanFiles.Files
-
 	// write the original files to the temp dir
-	errCh := make(chan error, len(planState.ContextsByPath))
-	hasAnyOriginal := false
+	err = writeDiffFiles(tempDirPath, original)
 
-	for path, context := range planState.ContextsByPath {
-		go func(path string, context *shared.Context) {
-			_, hasPath := files[path]
-			if hasPath {
-				hasAnyOriginal = true
-				// ensure file directory exists
-				err = os.MkdirAll(filepath.Dir(filepath.Join(tempDirPath, path)), 0755)
-				if err != nil {
-					errCh <- fmt.Errorf("error creating directory: %v", err)
-					return
-				}
-
-				err = os.WriteFile(filepath.Join(tempDirPath, path), []byte(context.Body), 0644)
-				if err != nil {
-					errCh <- fmt.Errorf("error writing file: %v", err)
-					return
-				}
-			}
-			errCh <- nil
-		}(path, context)
-	}
-
-	for range planState.ContextsByPath {
-		err = <-errCh
-		if err != nil {
-			return "", fmt.Errorf("error writing original files to temp dir: %v", err)
-		}
+	if err != nil {
+		return "", fmt.Errorf("error writing original files to temp dir: %v", err)
 	}
 
-	if hasAnyOriginal {
+	if len(original) > 0 {
 		// add and commit the files in the temp dir
 		err := gitAdd(tempDirPath, ".")
 		if err != nil {
@@ -86,19 +70,55 @@ func GetPlanDiffs(orgId, planId string) (string, error) {
 		}
 	}
 
-	// write the current files to the temp dir
-	errCh = make(chan error, len(files))
+	// remove original files that aren't in the updated files so they show as deleted
+	for path := range original {
+		if _, ok := updated[path]; !ok {
+			err = os.Remove(filepath.Join(tempDirPath, path))
+			if err != nil {
+				return "", fmt.Errorf("error removing file: %v", err)
+			}
+		}
+	}
+
+	// write the updated files to the temp dir
+	err = writeDiffFiles(tempDirPath, updated)
+
+	if err != nil {
+		return "", fmt.Errorf("error writing current files to temp dir: %v", err)
+	}
+
+	err = gitAdd(tempDirPath, ".")
+	if err != nil {
+		return "", fmt.Errorf("error adding files to git repository for dir: %s, err: %v", tempDirPath, err)
+	}
+
+	colorArg := "--color=always"
+	if plain {
+		colorArg = "--no-color"
+	}
+
+	res, err := exec.Command("git", "-C", tempDirPath, "diff", "--cached", colorArg).CombinedOutput()
+
+	if err != nil {
+		return "", fmt.Errorf("error getting diffs: %v", err)
+	}
+
+	return string(res), nil
+}
+
+func writeDiffFiles(dir string, files map[string]string) error {
+	errCh := make(chan error, len(files))
 
 	for path, file := range files {
 		go func(path, file string) {
 			// ensure file directory exists
-			err = os.MkdirAll(filepath.Dir(filepath.Join(tempDirPath, path)), 0755)
+			err := os.MkdirAll(filepath.Dir(filepath.Join(dir, path)), 0755)
 			if err != nil {
 				errCh <- fmt.Errorf("error creating directory: %v", err)
 				return
 			}
 
-			err = os.WriteFile(filepath.Join(tempDirPath, path), []byte(file), 0644)
+			err = os.WriteFile(filepath.Join(dir, path), []byte(file), 0644)
 			if err != nil {
 				errCh <- fmt.Errorf("error writing file: %v", err)
 				return
@@ -108,24 +128,13 @@ func GetPlanDiffs(orgId, planId string) (string, error) {
 	}
 
 	for range files {
-		err = <-errCh
+		err := <-errCh
 		if err != nil {
-			return "", fmt.Errorf("error writing current files to temp dir: %v", err)
+			return err
 		}
 	}
 
-	err = gitAdd(tempDirPath, ".")
-	if err != nil {
-		return "", fmt.Errorf("error adding files to git repository for dir: %s, err: %v", tempDirPath, err)
-	}
-
-	res, err := exec.Command("git", "-C", tempDirPath, "diff", "--cached", "--color=always").CombinedOutput()
-
-	if err != nil {
-		return "", fmt.Errorf("error getting diffs: %v", err)
-	}
-
-	return string(res), nil
+	return nil
 }
 
 func GetDiffsForBuild(original, updated string) (string, error) {
@@ -65,6 +65,31 @@ func GitAddAndCommit(orgId, planId, branch, message string) error {
 		return fmt.Errorf("error committing files to git repository for dir: %s, err: %v", dir, err)
 	}
 
+	QueueBranchSearchIndex(orgId, planId, branch)
+
+	return nil
+}
+
+// GitAddAndCommitAllowEmpty commits even if no files changed, so events whose state is stored in the db are still recorded in the plan's log
+func GitAddAndCommitAllowEmpty(orgId, planId, branch, message string) error {
+	dir := getPlanDir(orgId, planId)
+
+	err := retryGitWriteOperationIfIndexFileErr(func() error {
+		return gitAdd(dir, ".")
+	})
+	if err != nil {
+		return fmt.Errorf("error adding files to git repository for dir: %s, err: %v", dir, err)
+	}
+
+	err = retryGitWriteOperationIfIndexFileErr(func() error {
+		return gitCommit(dir, message, "--allow-empty")
+	})
+	if err != nil {
+		return fmt.Errorf("error committing files to git repository for dir: %s, err: %v", dir, err)
+	}
+
+	QueueBranchSearchIndex(orgId, planId, branch)
+
 	return nil
 }
 
@@ -78,9 +103,27 @@ func GitRewindToSha(orgId, planId, branch, sha string) error {
 		return fmt.Errorf("error rewinding git repository for dir: %s, err: %v", dir, err)
 	}
 
+	QueueBranchSearchIndex(orgId, planId, branch)
+
 	return nil
 }
 
+// GitIsAncestorOfHead returns whether sha is in the history of the checked out branch
+func GitIsAncestorOfHead(orgId, planId, sha string) (bool, error) {
+	dir := getPlanDir(orgId, planId)
+
+	res, err := exec.Command("git", "-C", dir, "merge-base", "--is-ancestor", sha, "HEAD").CombinedOutput()
+	if err != nil {
+		// exit status 1 means it isn't an ancestor -- anything else is an error, e.g. an unknown sha
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return false, nil
+		}
+		return false, fmt.Errorf("error checking ancestry of %s for dir: %s, err: %v, output: %s", sha, dir, err, string(res))
+	}
+
+	return true, nil
+}
+
 func GetGitCommitHistory(orgId, planId, branch string) (body string, shas []string, err error) {
 	dir := getPlanDir(orgId, planId)
 
@@ -135,7 +178,13 @@ func GitCreateBranch(orgId, planId, branch, newBranch string) error {
 		return nil
 	})
 
-	return err
+	if err != nil {
+		return err
+	}
+
+	QueueBranchSearchIndex(orgId, planId, newBranch)
+
+	return nil
 }
 
 func GitDeleteBranch(orgId, planId, branchName string) error {
@@ -181,6 +230,213 @@ func GitClearUncommittedChanges(orgId, planId string) error {
 	return err
 }
 
+// files a merge leaves as they are on the checked out branch -- the pending changes review belongs to the branch it was run on
+var mergeKeepCurrentPaths = []string{"review.json"}
+
+// GitMergeBranch merges sourceBranch into the checked out branch without committing, so the merge can be adjusted before it's committed with GitAddAndCommit. Where both branches changed the same file, the source branch's version wins, except for mergeKeepCurrentPaths. Returns false if there was nothing to merge.
+func GitMergeBranch(orgId, planId, sourceBranch string) (bool, error) {
+	dir := getPlanDir(orgId, planId)
+
+	var res []byte
+	err := retryGitWriteOperationIfIndexFileErr(func() error {
+		var err error
+		res, err = exec.Command("git", "-C", dir, "merge", "--no-ff", "--no-commit", "-X", "theirs", sourceBranch).CombinedOutput()
+		return err
+	})
+
+	if strings.Contains(string(res), "Already up to date") {
+		return false, nil
+	}
+
+	// -X theirs doesn't resolve files that were deleted on one branch and changed on the other, which leaves the merge stopped with conflicts
+	if err != nil && !strings.Contains(string(res), "CONFLICT") {
+		return false, fmt.Errorf("error merging git branch %s for dir: %s, err: %v, output: %s", sourceBranch, dir, err, string(res))
+	}
+
+	out, err := exec.Command("git", "-C", dir, "diff", "--name-only", "--diff-filter=U").Output()
+	if err != nil {
+		return false, fmt.Errorf("error listing merge conflicts for dir: %s, err: %v", dir, err)
+	}
+
+	for _, path := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if path == "" {
+			continue
+		}
+
+		var args []string
+		if exec.Command("git", "-C", dir, "cat-file", "-e", "MERGE_HEAD:"+path).Run() == nil {
+			res, err := exec.Command("git", "-C", dir, "checkout", "--theirs", "--", path).CombinedOutput()
+			if err != nil {
+				return false, fmt.Errorf("error resolving merge conflict for %s in dir: %s, err: %v, output: %s", path, dir, err, string(res))
+			}
+			args = []string{"add", path}
+		} else {
+			args = []string{"rm", "--quiet", path}
+		}
+
+		res, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+		if err != nil {
+			return false, fmt.Errorf("error resolving merge conflict for %s in dir: %s, err: %v, output: %s", path, dir, err, string(res))
+		}
+	}
+
+	// state that belongs to the checked out branch keeps its version rather than taking the source branch's
+	for _, path := range mergeKeepCurrentPaths {
+		var args []string
+		if exec.Command("git", "-C", dir, "cat-file", "-e", "HEAD:"+path).Run() == nil {
+			args = []string{"checkout", "HEAD", "--", path}
+		} else {
+			args = []string{"rm", "--quiet", "--force", "--ignore-unmatch", "--", path}
+		}
+
+		res, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+		if err != nil {
+			return false, fmt.Errorf("error keeping current %s in merge for dir: %s, err: %v, output: %s", path, dir, err, string(res))
+		}
+	}
+
+	return true, nil
+}
+
+// GitHeadSha returns the full sha of the checked out branch's latest commit
+func GitHeadSha(orgId, planId string) (string, error) {
+	dir := getPlanDir(orgId, planId)
+
+	res, err := exec.Command("git", "-C", dir, "rev-parse", "HEAD").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("error getting HEAD sha for dir: %s, err: %v, output: %s", dir, err, string(res))
+	}
+
+	return strings.TrimSpace(string(res)), nil
+}
+
+// GitReadBranchFiles returns the contents of the files in a plan dir subdirectory at a branch or commit other than the one checked out, by file name. Blobs are read with a single `git cat-file --batch`.
+func GitReadBranchFiles(orgId, planId, branch, subdir string) (map[string][]byte, error) {
+	dir := getPlanDir(orgId, planId)
+
+	out, err := exec.Command("git", "-C", dir, "ls-tree", branch, subdir+"/").Output()
+	if err != nil {
+		return nil, fmt.Errorf("error listing files on git branch %s for dir: %s, err: %v", branch, dir, err)
+	}
+
+	var names []string
+	var input bytes.Buffer
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		// <mode> <type> <sha>\t<path>
+		meta, path, found := strings.Cut(line, "\t")
+		fields := strings.Fields(meta)
+		if !found || len(fields) != 3 || fields[1] != "blob" {
+			continue
+		}
+
+		names = append(names, filepath.Base(path))
+		input.WriteString(fields[2] + "\n")
+	}
+
+	files := map[string][]byte{}
+	if len(names) == 0 {
+		return files, nil
+	}
+
+	cmd := exec.Command("git", "-C", dir, "cat-file", "--batch")
+	cmd.Stdin = &input
+	out, err = cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("error reading files on git branch %s for dir: %s, err: %v", branch, dir, err)
+	}
+
+	// each object is written as '<sha> <type> <size>\n<contents>\n', in the order requested
+	for _, name := range names {
+		header, rest, found := bytes.Cut(out, []byte("\n"))
+		fields := strings.Fields(string(header))
+		if !found || len(fields) != 3 {
+			return nil, fmt.Errorf("error reading %s on git branch %s for dir: %s, unexpected output: %s", name, branch, dir, string(header))
+		}
+
+		size, err := strconv.Atoi(fields[2])
+		if err != nil || size+1 > len(rest) {
+			return nil, fmt.Errorf("error reading %s on git branch %s for dir: %s, bad object size: %s", name, branch, dir, fields[2])
+		}
+
+		files[name] = rest[:size]
+		out = rest[size+1:]
+	}
+
+	return files, nil
+}
+
+// GitShaBeforeConvoMessage returns the sha of the commit just before the one that added a conversation message in the history of rev
+func GitShaBeforeConvoMessage(orgId, planId, rev, messageId string) (string, error) {
+	sha, err := GitShaBeforeFilesAdded(orgId, planId, rev, []string{filepath.Join("conversation", messageId+".json")})
+	if err != nil {
+		return "", fmt.Errorf("error getting commit for convo message %s: %v", messageId, err)
+	}
+
+	return sha, nil
+}
+
+// GitShaBeforeFilesAdded returns the sha of the commit just before the earliest one that added any of the given paths in the history of rev
+func GitShaBeforeFilesAdded(orgId, planId, rev string, paths []string) (string, error) {
+	dir := getPlanDir(orgId, planId)
+
+	out, err := exec.Command("git", append([]string{"-C", dir, "log", "--diff-filter=A", "--format=%h", rev, "--"}, paths...)...).Output()
+	if err != nil {
+		return "", fmt.Errorf("error getting commits for dir: %s, err: %v", dir, err)
+	}
+
+	shas := strings.Fields(string(out))
+	if len(shas) == 0 {
+		return "", fmt.Errorf("no commit found adding %s", strings.Join(paths, ", "))
+	}
+
+	// the earliest commit that added any of the paths
+	sha := shas[len(shas)-1]
+
+	res, err := exec.Command("git", "-C", dir, "rev-parse", "--short", sha+"^").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("error getting parent of commit %s for dir: %s, err: %v, output: %s", sha, dir, err, string(res))
+	}
+
+	return strings.TrimSpace(string(res)), nil
+}
+
+// GitCheckoutChangedFiles updates the files in a plan dir subdirectory that changed between two commits to their state at toSha, deleting those that were removed. It returns the changed paths.
+func GitCheckoutChangedFiles(orgId, planId, fromSha, toSha, subdir string) ([]string, error) {
+	dir := getPlanDir(orgId, planId)
+
+	out, err := exec.Command("git", "-C", dir, "diff", "--name-status", "--no-renames", fromSha, toSha, "--", subdir+"/").Output()
+	if err != nil {
+		return nil, fmt.Errorf("error diffing %s and %s for dir: %s, err: %v", fromSha, toSha, dir, err)
+	}
+
+	var changed, toCheckout []string
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		status, path, found := strings.Cut(line, "\t")
+		if !found {
+			continue
+		}
+		changed = append(changed, path)
+
+		if status == "D" {
+			err := os.Remove(filepath.Join(dir, path))
+			if err != nil && !os.IsNotExist(err) {
+				return nil, fmt.Errorf("error removing %s for dir: %s, err: %v", path, dir, err)
+			}
+		} else {
+			toCheckout = append(toCheckout, path)
+		}
+	}
+
+	if len(toCheckout) > 0 {
+		res, err := exec.Command("git", append([]string{"-C", dir, "checkout", toSha, "--"}, toCheckout...)...).CombinedOutput()
+		if err != nil {
+			return nil, fmt.Errorf("error checking out files at %s for dir: %s, err: %v, output: %s", toSha, dir, err, string(res))
+		}
+	}
+
+	return changed, nil
+}
+
 // Not used currently but may be good to handle these errors specifically later if locking can't fully prevent them
 // func isLockFileError(output string) bool {
 // 	return strings.Contains(output, "fatal: Unable to create") && strings.Contains output, ".git/index.lock': File exists")
@@ -261,10 +517,26 @@ func getGitCommitHistory(dir string) (body string, shas []string, err error) {
 	// Process the log output to get it in the desired format.
 	history := processGitHistoryOutput(strings.TrimSpace(out.String()))
 
+	tagNamesByCommit, err := getTagNamesByCommit(dir)
+	if err != nil {
+		return "", nil, err
+	}
+
+	tagColor := color.New(color.FgHiYellow, color.Bold)
+
 	var output []string
 	for _, el := range history {
 		shas = append(shas, el[0])
-		output = append(output, el[1])
+
+		entry := el[1]
+		for commitSha, names := range tagNamesByCommit {
+			if strings.HasPrefix(commitSha, el[0]) {
+				for _, name := range names {
+					entry = tagColor.Sprintf("🏷️  %s", name) + "\n" + entry
+				}
+			}
+		}
+		output = append(output, entry)
 	}
 
 	return strings.Join(output, "\n\n"), shas, nil
@@ -324,8 +596,8 @@ func gitAdd(repoDir, path string) error {
 	return nil
 }
 
-func gitCommit(repoDir, commitMsg string) error {
-	res, err := exec.Command("git", "-C", repoDir, "commit", "-m", commitMsg).CombinedOutput()
+func gitCommit(repoDir, commitMsg string, args ...string) error {
+	res, err := exec.Command("git", append([]string{"-C", repoDir, "commit", "-m", commitMsg}, args...)...).CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("error committing files to git repository for dir: %s, err: %v, output: %s", repoDir, err, string(res))
 	}
@@ -0,0 +1,375 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GetMergeConflicts returns the paths with pending changes made on both the checked out branch and sourceBranch since they diverged. Pending results the branches share from before they diverged aren't conflicts.
+func GetMergeConflicts(orgId, planId, sourceBranch string) ([]string, error) {
+	currentResults, err := GetPlanFileResults(orgId, planId)
+
+	if err != nil {
+		return nil, fmt.Errorf("error getting plan file results: %v", err)
+	}
+
+	sourceFiles, err := GitReadBranchFiles(orgId, planId, sourceBranch, "results")
+
+	if err != nil {
+		return nil, err
+	}
+
+	sourceIds := map[string]bool{}
+	sourcePendingPaths := map[string]bool{}
+	currentIds := map[string]bool{}
+	for _, result := range currentResults {
+		currentIds[result.Id] = true
+	}
+
+	for _, bytes := range sourceFiles {
+		var result PlanFileResult
+		err := json.Unmarshal(bytes, &result)
+
+		if err != nil {
+			return nil, fmt.Errorf("error unmarshalling result file: %v", err)
+		}
+
+		sourceIds[result.Id] = true
+
+		if !currentIds[result.Id] && result.ToApi().IsPending() {
+			sourcePendingPaths[result.Path] = true
+		}
+	}
+
+	conflicts := map[string]bool{}
+	for _, result := range currentResults {
+		if !sourceIds[result.Id] && sourcePendingPaths[result.Path] && result.ToApi().IsPending() {
+			conflicts[result.Path] = true
+		}
+	}
+
+	var paths []string
+	for path := range conflicts {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	return paths, nil
+}
+
+type MergeBranchParams struct {
+	OrgId        string
+	PlanId       string
+	Branch       string
+	SourceBranch string
+	// pending changes to these paths on Branch are rejected so that the pending changes from SourceBranch replace them
+	ConflictedPaths []string
+}
+
+// MergeBranch brings a branch's context, conversation, and results into the checked out branch with a git merge. Messages from the source branch are added after the current branch's conversation, with new ids and numbers. Returns an empty message if there was nothing to merge. If the merge fails, the branch is reset to where it was before, including undoing the rejection of conflicted files.
+func MergeBranch(params MergeBranchParams) (res string, err error) {
+	orgId := params.OrgId
+	planId := params.PlanId
+
+	preMergeSha, err := GitHeadSha(orgId, planId)
+
+	if err != nil {
+		return "", err
+	}
+
+	defer func() {
+		if err != nil || res == "" {
+			resetMerge(orgId, planId, params.Branch, preMergeSha)
+		}
+	}()
+
+	if len(params.ConflictedPaths) > 0 {
+		err = RejectPlanFiles(orgId, planId, params.ConflictedPaths, time.Now())
+
+		if err != nil {
+			return "", fmt.Errorf("error rejecting conflicted files: %v", err)
+		}
+
+		msg := fmt.Sprintf("🚫 Rejected pending changes to be replaced by changes from branch %s:", params.SourceBranch)
+		for _, path := range params.ConflictedPaths {
+			msg += fmt.Sprintf("\n • %s", path)
+		}
+
+		err = GitAddAndCommit(orgId, planId, params.Branch, msg)
+
+		if err != nil {
+			return "", fmt.Errorf("error committing rejected files: %v", err)
+		}
+	}
+
+	convo, err := GetPlanConvo(orgId, planId)
+
+	if err != nil {
+		return "", fmt.Errorf("error getting plan convo: %v", err)
+	}
+
+	contexts, err := GetPlanContexts(orgId, planId, false)
+
+	if err != nil {
+		return "", fmt.Errorf("error getting plan contexts: %v", err)
+	}
+
+	results, err := GetPlanFileResults(orgId, planId)
+
+	if err != nil {
+		return "", fmt.Errorf("error getting plan file results: %v", err)
+	}
+
+	currentIds := map[string]bool{}
+	maxNum := 0
+	for _, msg := range convo {
+		currentIds[msg.Id] = true
+		if msg.Num > maxNum {
+			maxNum = msg.Num
+		}
+	}
+	for _, context := range contexts {
+		currentIds[context.Id] = true
+	}
+	for _, result := range results {
+		currentIds[result.Id] = true
+	}
+
+	merged, err := GitMergeBranch(orgId, planId, params.SourceBranch)
+
+	if err != nil {
+		return "", err
+	}
+
+	if !merged {
+		return "", nil
+	}
+
+	msg, err := finishMerge(orgId, planId, params.SourceBranch, currentIds, maxNum)
+
+	if err != nil {
+		return "", err
+	}
+
+	err = GitAddAndCommit(orgId, planId, params.Branch, msg)
+
+	if err != nil {
+		return "", fmt.Errorf("error committing merge: %v", err)
+	}
+
+	err = SyncPlanTokens(orgId, planId, params.Branch)
+
+	if err != nil {
+		return "", fmt.Errorf("error syncing plan tokens: %v", err)
+	}
+
+	return msg, nil
+}
+
+// finishMerge renumbers merged messages, points merged descriptions and results at them, and drops merged context that duplicates context on the current branch. It returns the merge's commit message.
+func finishMerge(orgId, planId, sourceBranch string, currentIds map[string]bool, maxNum int) (string, error) {
+	convo, err := GetPlanConvo(orgId, planId)
+
+	if err != nil {
+		return "", fmt.Errorf("error getting plan convo: %v", err)
+	}
+
+	// new ids keep summaries of the source branch's conversation, which don't cover the current branch's messages, from being used for the merged conversation
+	convoDir := getPlanConversationDir(orgId, planId)
+	messageIds := map[string]string{}
+	ts := time.Now().UTC()
+	for _, msg := range convo {
+		if currentIds[msg.Id] {
+			continue
+		}
+
+		oldId := msg.Id
+		msg.Id = uuid.New().String()
+		msg.Num = maxNum + len(messageIds) + 1
+		msg.CreatedAt = ts.Add(time.Duration(len(messageIds)) * time.Millisecond)
+		messageIds[oldId] = msg.Id
+
+		bytes, err := json.Marshal(msg)
+
+		if err != nil {
+			return "", fmt.Errorf("error marshalling convo message: %v", err)
+		}
+
+		err = os.WriteFile(filepath.Join(convoDir, msg.Id+".json"), bytes, os.ModePerm)
+
+		if err != nil {
+			return "", fmt.Errorf("error writing convo message: %v", err)
+		}
+
+		err = os.Remove(filepath.Join(convoDir, oldId+".json"))
+
+		if err != nil {
+			return "", fmt.Errorf("error removing convo message: %v", err)
+		}
+	}
+
+	descriptions, err := GetConvoMessageDescriptions(orgId, planId)
+
+	if err != nil {
+		return "", fmt.Errorf("error getting convo message descriptions: %v", err)
+	}
+
+	for _, desc := range descriptions {
+		newId, ok := messageIds[desc.ConvoMessageId]
+		if !ok {
+			continue
+		}
+		desc.ConvoMessageId = newId
+		if summarizedTo, ok := messageIds[desc.SummarizedToMessageId]; ok {
+			desc.SummarizedToMessageId = summarizedTo
+		}
+
+		err = StoreDescription(desc)
+
+		if err != nil {
+			return "", fmt.Errorf("error storing description: %v", err)
+		}
+	}
+
+	results, err := GetPlanFileResults(orgId, planId)
+
+	if err != nil {
+		return "", fmt.Errorf("error getting plan file results: %v", err)
+	}
+
+	numPending := 0
+	for _, result := range results {
+		if currentIds[result.Id] {
+			continue
+		}
+
+		if result.ToApi().IsPending() {
+			numPending++
+		}
+
+		newId, ok := messageIds[result.ConvoMessageId]
+		if !ok {
+			continue
+		}
+		result.ConvoMessageId = newId
+
+		err = StorePlanResult(result)
+
+		if err != nil {
+			return "", fmt.Errorf("error storing plan result: %v", err)
+		}
+	}
+
+	numContexts, err := dedupeMergedContexts(orgId, planId, currentIds)
+
+	if err != nil {
+		return "", err
+	}
+
+	msg := fmt.Sprintf("🔀 Merged branch %s", sourceBranch)
+	if len(messageIds) > 0 {
+		msg += fmt.Sprintf("\n • %d message%s", len(messageIds), pluralS(len(messageIds)))
+	}
+	if numContexts > 0 {
+		msg += fmt.Sprintf("\n • %d context item%s", numContexts, pluralS(numContexts))
+	}
+	if numPending > 0 {
+		msg += fmt.Sprintf("\n • %d pending result%s", numPending, pluralS(numPending))
+	}
+
+	return msg, nil
+}
+
+// dedupeMergedContexts keeps only the most recently updated of any file, url, or command that's now loaded more than once, returning the number of contexts the merge added
+func dedupeMergedContexts(orgId, planId string, currentIds map[string]bool) (int, error) {
+	contexts, err := GetPlanContexts(orgId, planId, false)
+
+	if err != nil {
+		return 0, fmt.Errorf("error getting plan contexts: %v", err)
+	}
+
+	byKey := map[string][]*Context{}
+	for _, context := range contexts {
+		if context.FilePath == "" && context.Url == "" && context.Command == "" {
+			continue
+		}
+
+		key := strings.Join([]string{string(context.ContextType), context.FilePath, context.Url, context.Command, context.GitRef}, "|")
+		if context.LineRange != nil {
+			key += "|" + context.LineRange.Label()
+		}
+		byKey[key] = append(byKey[key], context)
+	}
+
+	removed := map[string]bool{}
+	contextDir := getPlanContextDir(orgId, planId)
+	for _, dupes := range byKey {
+		if len(dupes) < 2 {
+			continue
+		}
+
+		anyMerged := false
+		for _, context := range dupes {
+			if !currentIds[context.Id] {
+				anyMerged = true
+			}
+		}
+		if !anyMerged {
+			continue
+		}
+
+		sort.Slice(dupes, func(i, j int) bool {
+			return dupes[i].UpdatedAt.After(dupes[j].UpdatedAt)
+		})
+
+		for _, context := range dupes[1:] {
+			for _, ext := range []string{".meta", ".body"} {
+				err := os.Remove(filepath.Join(contextDir, context.Id+ext))
+
+				if err != nil && !os.IsNotExist(err) {
+					return 0, fmt.Errorf("error removing context file: %v", err)
+				}
+			}
+			removed[context.Id] = true
+		}
+	}
+
+	numAdded := 0
+	for _, context := range contexts {
+		if !currentIds[context.Id] && !removed[context.Id] {
+			numAdded++
+		}
+	}
+
+	return numAdded, nil
+}
+
+// resetMerge puts the branch back to sha, dropping any merge in progress and any commits made since
+func resetMerge(orgId, planId, branch, sha string) {
+	err := GitClearUncommittedChanges(orgId, planId)
+
+	if err != nil {
+		log.Printf("Error clearing merge changes: %v\n", err)
+	}
+
+	err = GitRewindToSha(orgId, planId, branch, sha)
+
+	if err != nil {
+		log.Printf("Error resetting branch %s to %s after merge: %v\n", branch, sha, err)
+	}
+}
+
+func pluralS(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
@@ -0,0 +1,257 @@
+package db
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const testOrgId = "org"
+const testPlanId = "plan"
+
+func newTestPlanRepo(t *testing.T) string {
+	BaseDir = t.TempDir()
+
+	err := InitPlan(testOrgId, testPlanId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = InitGitRepo(testOrgId, testPlanId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := getPlanDir(testOrgId, testPlanId)
+	writeTestPlanFile(t, "README", "plan")
+	commitTestPlan(t, "init")
+
+	return dir
+}
+
+func writeTestPlanFile(t *testing.T, path, content string) {
+	err := os.WriteFile(filepath.Join(getPlanDir(testOrgId, testPlanId), path), []byte(content), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readTestPlanFile(t *testing.T, path string) (string, bool) {
+	bytes, err := os.ReadFile(filepath.Join(getPlanDir(testOrgId, testPlanId), path))
+	if os.IsNotExist(err) {
+		return "", false
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(bytes), true
+}
+
+func writeTestResult(t *testing.T, id, path string) {
+	bytes, err := json.Marshal(PlanFileResult{Id: id, Path: path, Content: "content of " + id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeTestPlanFile(t, filepath.Join("results", id+".json"), string(bytes))
+}
+
+func commitTestPlan(t *testing.T, msg string) {
+	err := GitAddAndCommit(testOrgId, testPlanId, "main", msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func checkoutTestBranch(t *testing.T, branch string) {
+	err := gitCheckoutBranch(getPlanDir(testOrgId, testPlanId), branch)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetMergeConflicts(t *testing.T) {
+	newTestPlanRepo(t)
+
+	// pending on both branches from before they diverged
+	writeTestResult(t, "shared", "a.go")
+	commitTestPlan(t, "shared result")
+
+	err := GitCreateBranch(testOrgId, testPlanId, "main", "feature")
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeTestResult(t, "feature-a", "a.go")
+	writeTestResult(t, "feature-b", "b.go")
+	commitTestPlan(t, "feature results")
+
+	checkoutTestBranch(t, "main")
+	writeTestResult(t, "main-a", "a.go")
+	writeTestResult(t, "main-c", "c.go")
+	commitTestPlan(t, "main results")
+
+	conflicts, err := GetMergeConflicts(testOrgId, testPlanId, "feature")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(conflicts, []string{"a.go"}) {
+		t.Errorf("expected only a.go to conflict, got %v", conflicts)
+	}
+}
+
+func TestGitMergeBranch(t *testing.T) {
+	newTestPlanRepo(t)
+
+	writeTestPlanFile(t, "conversation/shared.json", "shared")
+	writeTestPlanFile(t, "review.json", "main review")
+	commitTestPlan(t, "shared")
+
+	err := GitCreateBranch(testOrgId, testPlanId, "main", "feature")
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeTestPlanFile(t, "conversation/feature.json", "feature")
+	writeTestPlanFile(t, "conversation/shared.json", "shared, edited on feature")
+	writeTestPlanFile(t, "review.json", "feature review")
+	commitTestPlan(t, "feature")
+
+	checkoutTestBranch(t, "main")
+	writeTestPlanFile(t, "conversation/main.json", "main")
+	writeTestPlanFile(t, "conversation/shared.json", "shared, edited on main")
+	commitTestPlan(t, "main")
+
+	merged, err := GitMergeBranch(testOrgId, testPlanId, "feature")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !merged {
+		t.Fatal("expected branch to be merged")
+	}
+
+	expected := map[string]string{
+		"conversation/main.json":    "main",
+		"conversation/feature.json": "feature",
+		// conflicting edits take the source branch's version
+		"conversation/shared.json": "shared, edited on feature",
+		// but the review stays as it is on the current branch
+		"review.json": "main review",
+	}
+	for path, content := range expected {
+		got, _ := readTestPlanFile(t, path)
+		if got != content {
+			t.Errorf("expected %s to be %q after merge, got %q", path, content, got)
+		}
+	}
+
+	commitTestPlan(t, "merge")
+
+	merged, err = GitMergeBranch(testOrgId, testPlanId, "feature")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if merged {
+		t.Error("expected nothing to merge the second time")
+	}
+}
+
+func TestGitMergeBranchDeleteConflict(t *testing.T) {
+	newTestPlanRepo(t)
+
+	writeTestPlanFile(t, "context/deleted.body", "original")
+	writeTestPlanFile(t, "context/edited.body", "original")
+	commitTestPlan(t, "context")
+
+	err := GitCreateBranch(testOrgId, testPlanId, "main", "feature")
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeTestPlanFile(t, "context/edited.body", "edited on feature")
+	err = os.Remove(filepath.Join(getPlanDir(testOrgId, testPlanId), "context/deleted.body"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a review that only exists on the source branch isn't merged
+	writeTestPlanFile(t, "review.json", "feature review")
+	commitTestPlan(t, "feature")
+
+	checkoutTestBranch(t, "main")
+	writeTestPlanFile(t, "context/deleted.body", "edited on main")
+	err = os.Remove(filepath.Join(getPlanDir(testOrgId, testPlanId), "context/edited.body"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitTestPlan(t, "main")
+
+	merged, err := GitMergeBranch(testOrgId, testPlanId, "feature")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !merged {
+		t.Fatal("expected branch to be merged")
+	}
+
+	if got, _ := readTestPlanFile(t, "context/edited.body"); got != "edited on feature" {
+		t.Errorf("expected file edited on the source branch to be kept, got %q", got)
+	}
+	if _, exists := readTestPlanFile(t, "context/deleted.body"); exists {
+		t.Error("expected file deleted on the source branch to be deleted")
+	}
+	if _, exists := readTestPlanFile(t, "review.json"); exists {
+		t.Error("expected source branch's review not to be merged")
+	}
+
+	// no conflicts are left unresolved, so the merge can be committed
+	commitTestPlan(t, "merge")
+}
+
+func TestResetMerge(t *testing.T) {
+	dir := newTestPlanRepo(t)
+
+	err := GitCreateBranch(testOrgId, testPlanId, "main", "feature")
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeTestPlanFile(t, "conversation/feature.json", "feature")
+	commitTestPlan(t, "feature")
+	checkoutTestBranch(t, "main")
+
+	preMergeSha, err := GitHeadSha(testOrgId, testPlanId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// like the commit rejecting conflicted results before a merge
+	writeTestResult(t, "rejected", "a.go")
+	commitTestPlan(t, "rejected")
+
+	_, err = GitMergeBranch(testOrgId, testPlanId, "feature")
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeTestPlanFile(t, "conversation/untracked.json", "written while finishing the merge")
+
+	resetMerge(testOrgId, testPlanId, "main", preMergeSha)
+
+	sha, err := GitHeadSha(testOrgId, testPlanId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sha != preMergeSha {
+		t.Errorf("expected HEAD to be reset to %s, got %s", preMergeSha, sha)
+	}
+
+	if exec.Command("git", "-C", dir, "rev-parse", "-q", "--verify", "MERGE_HEAD").Run() == nil {
+		t.Error("expected merge to be aborted")
+	}
+
+	out, err := exec.Command("git", "-C", dir, "status", "--porcelain").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) > 0 {
+		t.Errorf("expected a clean tree after reset, got:\n%s", out)
+	}
+}
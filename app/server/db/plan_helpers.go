@@ -84,8 +84,9 @@ func CreatePlan(orgId, projectId, userId, name string) (*Plan, error) {
 	return plan, nil
 }
 
-func ListOwnedPlans(projectIds []string, userId string, archived bool) ([]*Plan, error) {
-	qs := "SELECT * FROM plans WHERE project_id = ANY($1) AND owner_id = $2"
+// ListAccessiblePlans lists the plans in projectIds that the user owns or that have been shared with them
+func ListAccessiblePlans(projectIds []string, userId string, archived bool) ([]*Plan, error) {
+	qs := "SELECT * FROM plans WHERE project_id = ANY($1) AND (owner_id = $2 OR shared_with_org_at IS NOT NULL OR EXISTS (SELECT 1 FROM plan_collaborators WHERE plan_collaborators.plan_id = plans.id AND plan_collaborators.user_id = $2))"
 	qargs := []interface{}{pq.Array(projectIds), userId}
 
 	if archived {
@@ -177,7 +178,9 @@ func SyncPlanTokens(orgId, planId, branch string) error {
 
 	contextTokens := 0
 	for _, context := range contexts {
-		contextTokens += context.NumTokens
+		if !context.Disabled {
+			contextTokens += context.NumTokens
+		}
 	}
 
 	convoTokens := 0
@@ -365,43 +368,57 @@ func DeleteOwnerPlans(orgId, projectId, userId string) error {
 	return nil
 }
 
-func ValidatePlanAccess(planId, userId, orgId string) (*Plan, error) {
+// GetPlanAccess returns the plan and the user's access to it. The owner has write access, collaborators and org members have whatever access the plan was shared with, and the access is empty if the user can't access the plan at all.
+func GetPlanAccess(planId, userId, orgId string) (*Plan, shared.PlanAccess, error) {
 	// get plan
 	plan, err := GetPlan(planId)
 
 	if err != nil {
-		return nil, fmt.Errorf("error getting plan: %v", err)
+		return nil, "", fmt.Errorf("error getting plan: %v", err)
 	}
 
 	if plan == nil {
-		return nil, nil
+		return nil, "", nil
 	}
 
 	if plan.OrgId != orgId {
-		return nil, nil
+		return nil, "", nil
 	}
 
 	hasProjectAccess, err := ProjectExists(orgId, plan.ProjectId)
 
 	if err != nil {
-		return nil, fmt.Errorf("error validating project membership: %v", err)
+		return nil, "", fmt.Errorf("error validating project membership: %v", err)
 	}
 
 	if !hasProjectAccess {
-		return nil, nil
+		return nil, "", nil
 	}
 
 	// owner has access
 	if plan.OwnerId == userId {
-		return plan, nil
+		return plan, shared.PlanAccessWrite, nil
+	}
+
+	access, err := GetPlanCollaboratorAccess(planId, userId)
+
+	if err != nil {
+		return nil, "", err
 	}
 
 	// plan is shared with org
-	if plan.SharedWithOrgAt != nil {
-		return plan, nil
+	if plan.SharedWithOrgAt != nil && access != shared.PlanAccessWrite {
+		access = shared.PlanAccessRead
+		if plan.SharedWithOrgAccess != nil {
+			access = shared.PlanAccess(*plan.SharedWithOrgAccess)
+		}
+	}
+
+	if access == "" {
+		return nil, "", nil
 	}
 
-	return nil, nil
+	return plan, access, nil
 }
 
 func BumpPlanUpdatedAt(planId string, t time.Time) error {
@@ -0,0 +1,136 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/plandex/plandex/shared"
+)
+
+// ListPromptTemplates returns the org's overrides, plus the plan's overrides if planId isn't empty
+func ListPromptTemplates(orgId, planId string) ([]*PromptTemplate, error) {
+	var templates []*PromptTemplate
+
+	query := `SELECT * FROM prompt_templates WHERE org_id = $1 AND (plan_id IS NULL OR plan_id = NULLIF($2, '')::uuid) ORDER BY name`
+
+	err := Conn.Select(&templates, query, orgId, planId)
+
+	if err != nil {
+		return nil, fmt.Errorf("error listing prompt templates: %v", err)
+	}
+
+	return templates, nil
+}
+
+// GetPromptTemplate returns the plan's override of a prompt if there is one, otherwise the org's, or nil if neither has overridden it
+func GetPromptTemplate(orgId, planId string, name shared.PromptName) (*PromptTemplate, error) {
+	var template PromptTemplate
+
+	query := `SELECT * FROM prompt_templates WHERE org_id = $1 AND name = $2 AND (plan_id IS NULL OR plan_id = NULLIF($3, '')::uuid) ORDER BY plan_id IS NULL LIMIT 1`
+
+	err := Conn.Get(&template, query, orgId, name, planId)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting prompt template: %v", err)
+	}
+
+	return &template, nil
+}
+
+// StorePromptTemplate sets the org's override of a prompt, or the plan's if planId isn't empty, and records the template's version
+func StorePromptTemplate(orgId, planId string, name shared.PromptName, template string) (*PromptTemplate, error) {
+	version := shared.GetPromptVersion(template)
+
+	tx, err := Conn.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	_, err = tx.Exec(`INSERT INTO prompt_template_versions (org_id, version, name, template) VALUES ($1, $2, $3, $4) ON CONFLICT DO NOTHING`, orgId, version, name, template)
+
+	if err != nil {
+		return nil, fmt.Errorf("error storing prompt template version: %v", err)
+	}
+
+	var query string
+	if planId == "" {
+		query = `INSERT INTO prompt_templates (org_id, plan_id, name, template, version) VALUES ($1, NULL, $2, $3, $4)
+		ON CONFLICT (org_id, name) WHERE plan_id IS NULL DO UPDATE SET template = excluded.template, version = excluded.version
+		RETURNING *`
+	} else {
+		query = `INSERT INTO prompt_templates (org_id, plan_id, name, template, version) VALUES ($1, $5, $2, $3, $4)
+		ON CONFLICT (plan_id, name) WHERE plan_id IS NOT NULL DO UPDATE SET template = excluded.template, version = excluded.version
+		RETURNING *`
+	}
+
+	args := []interface{}{orgId, name, template, version}
+	if planId != "" {
+		args = append(args, planId)
+	}
+
+	var res PromptTemplate
+	err = tx.Get(&res, query, args...)
+
+	if err != nil {
+		return nil, fmt.Errorf("error storing prompt template: %v", err)
+	}
+
+	err = tx.Commit()
+
+	if err != nil {
+		return nil, fmt.Errorf("error committing transaction: %v", err)
+	}
+
+	return &res, nil
+}
+
+// DeletePromptTemplate removes the org's override of a prompt, or the plan's if planId isn't empty. Its versions are kept.
+func DeletePromptTemplate(orgId, planId string, name shared.PromptName) error {
+	var err error
+	if planId == "" {
+		_, err = Conn.Exec(`DELETE FROM prompt_templates WHERE org_id = $1 AND name = $2 AND plan_id IS NULL`, orgId, name)
+	} else {
+		_, err = Conn.Exec(`DELETE FROM prompt_templates WHERE org_id = $1 AND name = $2 AND plan_id = $3`, orgId, name, planId)
+	}
+
+	if err != nil {
+		return fmt.Errorf("error deleting prompt template: %v", err)
+	}
+
+	return nil
+}
+
+// GetPromptTemplateVersion returns a template by version if it was ever set in the org, or nil. If name is empty and the same template was set for more than one prompt, the earliest is returned.
+func GetPromptTemplateVersion(orgId string, name shared.PromptName, version string) (*shared.PromptTemplate, error) {
+	var row struct {
+		Name      string       `db:"name"`
+		Template  string       `db:"template"`
+		CreatedAt sql.NullTime `db:"created_at"`
+	}
+
+	query := `SELECT name, template, created_at FROM prompt_template_versions WHERE org_id = $1 AND version = $2 AND (name = $3 OR $3 = '') ORDER BY created_at LIMIT 1`
+
+	err := Conn.Get(&row, query, orgId, version, name)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting prompt template version: %v", err)
+	}
+
+	return &shared.PromptTemplate{
+		Name:      shared.PromptName(row.Name),
+		Template:  row.Template,
+		Version:   version,
+		UpdatedAt: &row.CreatedAt.Time,
+	}, nil
+}
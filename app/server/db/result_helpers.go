@@ -118,7 +118,7 @@ func GetCurrentPlanState(params CurrentPlanStateParams) (*shared.CurrentPlanStat
 		}
 
 		for _, context := range contexts {
-			if context.FilePath != "" {
+			if context.FilePath != "" && context.LineRange == nil {
 				contextsByPath[context.FilePath] = context
 			}
 		}
@@ -379,7 +379,8 @@ func ApplyPlan(orgId, userId, branchName string, plan *Plan) (*shared.CurrentPla
 
 		for _, context := range res {
 			contextsById[context.Id] = context
-			if context.FilePath != "" {
+			// line range contexts are left to be re-resolved from the applied file by the client
+			if context.FilePath != "" && context.LineRange == nil {
 				contextsByPath[context.FilePath] = context
 			}
 		}
@@ -511,6 +512,9 @@ func ApplyPlan(orgId, userId, branchName string, plan *Plan) (*shared.CurrentPla
 			updateReq := shared.UpdateContextRequest{}
 			for path := range pendingUpdatedFilesSet {
 				context := contextsByPath[path]
+				if context == nil {
+					continue
+				}
 				updateReq[context.Id] = &shared.UpdateContextParams{
 					Body: currentPlanState.CurrentPlanFiles.Files[path],
 				}
@@ -564,7 +568,14 @@ func ApplyPlan(orgId, userId, branchName string, plan *Plan) (*shared.CurrentPla
 		msg += "\n\n" + updateContextRes.Msg
 	}
 
-	err := GitAddAndCommit(orgId, plan.Id, branchName, msg)
+	// requested reviews only cover the changes that were just applied
+	err := ClearPlanReviewState(orgId, planId, branchName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = GitAddAndCommit(orgId, plan.Id, branchName, msg)
 
 	if err != nil {
 		return nil, fmt.Errorf("error committing plan: %v", err)
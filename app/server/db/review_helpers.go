@@ -0,0 +1,82 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plandex/plandex/shared"
+)
+
+func StorePlanReview(orgId, planId string, review *shared.PlanReview) error {
+	if review.Id == "" {
+		review.Id = uuid.New().String()
+	}
+	if review.CreatedAt.IsZero() {
+		review.CreatedAt = time.Now()
+	}
+
+	bytes, err := json.MarshalIndent(review, "", "  ")
+
+	if err != nil {
+		return fmt.Errorf("error marshalling review: %v", err)
+	}
+
+	err = os.WriteFile(getPlanReviewPath(orgId, planId), bytes, 0644)
+
+	if err != nil {
+		return fmt.Errorf("error writing review file: %v", err)
+	}
+
+	return nil
+}
+
+// GetPlanReview returns nil if the plan hasn't been reviewed
+func GetPlanReview(orgId, planId string) (*shared.PlanReview, error) {
+	bytes, err := os.ReadFile(getPlanReviewPath(orgId, planId))
+
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error reading review file: %v", err)
+	}
+
+	var review shared.PlanReview
+	err = json.Unmarshal(bytes, &review)
+
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling review: %v", err)
+	}
+
+	return &review, nil
+}
+
+// GetCurrentPlanReview returns the stored review only if it still covers exactly the pending results in the plan state
+func GetCurrentPlanReview(orgId, planId string, planState *shared.CurrentPlanState) (*shared.PlanReview, error) {
+	review, err := GetPlanReview(orgId, planId)
+
+	if err != nil || review == nil {
+		return nil, err
+	}
+
+	pendingIds := planState.PlanResult.PendingResultIds()
+
+	if len(pendingIds) != len(review.ResultIds) {
+		return nil, nil
+	}
+
+	for i, id := range pendingIds {
+		if review.ResultIds[i] != id {
+			return nil, nil
+		}
+	}
+
+	return review, nil
+}
+
+func getPlanReviewPath(orgId, planId string) string {
+	return filepath.Join(getPlanDir(orgId, planId), "review.json")
+}
@@ -0,0 +1,117 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/plandex/plandex/shared"
+)
+
+type reviewRequestRow struct {
+	Id              string    `db:"id"`
+	ReviewerId      string    `db:"reviewer_id"`
+	ReviewerName    string    `db:"reviewer_name"`
+	ReviewerEmail   string    `db:"reviewer_email"`
+	RequestedById   string    `db:"requested_by_id"`
+	RequestedByName string    `db:"requested_by_name"`
+	CreatedAt       time.Time `db:"created_at"`
+}
+
+type reviewFeedbackRow struct {
+	Id            string         `db:"id"`
+	UserId        string         `db:"user_id"`
+	UserName      string         `db:"user_name"`
+	Path          string         `db:"path"`
+	ReplacementId string         `db:"replacement_id"`
+	ResultIds     pq.StringArray `db:"result_ids"`
+	Approved      bool           `db:"approved"`
+	Comment       string         `db:"comment"`
+	CreatedAt     time.Time      `db:"created_at"`
+}
+
+// GetPlanReviewState returns an empty state if no reviews have been requested on the branch since changes were last applied. Review state is stored in the db rather than the plan's git repo so that rewinding or merging can't drop open requests.
+func GetPlanReviewState(orgId, planId, branch string) (*shared.PlanReviewState, error) {
+	state := &shared.PlanReviewState{}
+
+	var requests []reviewRequestRow
+	err := Conn.Select(&requests, "SELECT id, reviewer_id, reviewer_name, reviewer_email, requested_by_id, requested_by_name, created_at FROM plan_review_requests WHERE org_id = $1 AND plan_id = $2 AND branch = $3 ORDER BY created_at", orgId, planId, branch)
+
+	if err != nil {
+		return nil, fmt.Errorf("error getting review requests: %v", err)
+	}
+
+	for _, row := range requests {
+		state.Requests = append(state.Requests, &shared.ReviewRequest{
+			Id:              row.Id,
+			ReviewerId:      row.ReviewerId,
+			ReviewerName:    row.ReviewerName,
+			ReviewerEmail:   row.ReviewerEmail,
+			RequestedById:   row.RequestedById,
+			RequestedByName: row.RequestedByName,
+			CreatedAt:       row.CreatedAt,
+		})
+	}
+
+	var feedback []reviewFeedbackRow
+	err = Conn.Select(&feedback, "SELECT id, user_id, user_name, path, replacement_id, result_ids, approved, comment, created_at FROM plan_review_feedback WHERE org_id = $1 AND plan_id = $2 AND branch = $3 ORDER BY created_at", orgId, planId, branch)
+
+	if err != nil {
+		return nil, fmt.Errorf("error getting review feedback: %v", err)
+	}
+
+	for _, row := range feedback {
+		state.Feedback = append(state.Feedback, &shared.ReviewFeedback{
+			Id:            row.Id,
+			UserId:        row.UserId,
+			UserName:      row.UserName,
+			Path:          row.Path,
+			ReplacementId: row.ReplacementId,
+			ResultIds:     []string(row.ResultIds),
+			Approved:      row.Approved,
+			Comment:       row.Comment,
+			CreatedAt:     row.CreatedAt,
+		})
+	}
+
+	return state, nil
+}
+
+func AddPlanReviewRequest(orgId, planId, branch string, request *shared.ReviewRequest) error {
+	_, err := Conn.Exec("INSERT INTO plan_review_requests (id, org_id, plan_id, branch, reviewer_id, reviewer_name, reviewer_email, requested_by_id, requested_by_name, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) ON CONFLICT (plan_id, branch, reviewer_id) DO NOTHING",
+		request.Id, orgId, planId, branch, request.ReviewerId, request.ReviewerName, request.ReviewerEmail, request.RequestedById, request.RequestedByName, request.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("error adding review request: %v", err)
+	}
+
+	return nil
+}
+
+func AddPlanReviewFeedback(orgId, planId, branch string, feedback *shared.ReviewFeedback) error {
+	_, err := Conn.Exec("INSERT INTO plan_review_feedback (id, org_id, plan_id, branch, user_id, user_name, path, replacement_id, result_ids, approved, comment, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)",
+		feedback.Id, orgId, planId, branch, feedback.UserId, feedback.UserName, feedback.Path, feedback.ReplacementId, pq.Array(feedback.ResultIds), feedback.Approved, feedback.Comment, feedback.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("error adding review feedback: %v", err)
+	}
+
+	return nil
+}
+
+// ClearPlanReviewState removes the branch's review requests and feedback once the changes they cover are applied
+func ClearPlanReviewState(orgId, planId, branch string) error {
+	_, err := Conn.Exec("DELETE FROM plan_review_requests WHERE org_id = $1 AND plan_id = $2 AND branch = $3", orgId, planId, branch)
+
+	if err != nil {
+		return fmt.Errorf("error deleting review requests: %v", err)
+	}
+
+	_, err = Conn.Exec("DELETE FROM plan_review_feedback WHERE org_id = $1 AND plan_id = $2 AND branch = $3", orgId, planId, branch)
+
+	if err != nil {
+		return fmt.Errorf("error deleting review feedback: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,401 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/lib/pq"
+	"github.com/plandex/plandex/shared"
+)
+
+const maxPlanSearchResults = 50
+
+// number of branches to reindex at once
+const searchIndexConcurrency = 8
+
+var searchHeadlineOpts = fmt.Sprintf("StartSel=%s, StopSel=%s, MaxWords=25, MinWords=10, MaxFragments=1", shared.PlanSearchMatchStart, shared.PlanSearchMatchStop)
+
+// strips match delimiters from indexed text so only ts_headline adds them
+var searchMatchDelimReplacer = strings.NewReplacer(shared.PlanSearchMatchStart, "", shared.PlanSearchMatchStop, "")
+
+// markdown emphasis, e.g. **bold**, __bold__, ~~strike~~, *italic* -- single underscores are left alone so snake_case isn't mangled
+var searchSnippetEmphasisRegexes = []*regexp.Regexp{
+	regexp.MustCompile(`\*\*(\S(?:.*?\S)?)\*\*`),
+	regexp.MustCompile(`__(\S(?:.*?\S)?)__`),
+	regexp.MustCompile(`~~(\S(?:.*?\S)?)~~`),
+	regexp.MustCompile(`\*(\S(?:[^*]*?\S)?)\*`),
+}
+
+var searchIndexQueue = struct {
+	mu      sync.Mutex
+	pending map[string]searchIndexQueueItem
+	signal  chan struct{}
+}{
+	pending: map[string]searchIndexQueueItem{},
+	signal:  make(chan struct{}, 1),
+}
+
+type searchIndexQueueItem struct {
+	orgId  string
+	planId string
+	branch string
+}
+
+// QueueBranchSearchIndex schedules a branch to be reindexed by the search index worker. It's called after writes to a branch and never blocks, so a branch written to several times before the worker gets to it is only indexed once.
+func QueueBranchSearchIndex(orgId, planId, branch string) {
+	searchIndexQueue.mu.Lock()
+	searchIndexQueue.pending[strings.Join([]string{orgId, planId, branch}, "|")] = searchIndexQueueItem{orgId, planId, branch}
+	searchIndexQueue.mu.Unlock()
+
+	select {
+	case searchIndexQueue.signal <- struct{}{}:
+	default:
+	}
+}
+
+func takeQueuedSearchIndexItems() []searchIndexQueueItem {
+	searchIndexQueue.mu.Lock()
+	defer searchIndexQueue.mu.Unlock()
+
+	var items []searchIndexQueueItem
+	for key, item := range searchIndexQueue.pending {
+		items = append(items, item)
+		delete(searchIndexQueue.pending, key)
+	}
+
+	return items
+}
+
+// StartSearchIndexWorker indexes every branch that changed while the server was down, then keeps the search index up to date in the background as branches are queued. Files are read from each branch's latest commit rather than the checked out branch, so no repo lock is needed.
+func StartSearchIndexWorker() {
+	go func() {
+		var branches []*Branch
+		err := Conn.Select(&branches, "SELECT * FROM branches ORDER BY updated_at DESC")
+
+		if err != nil {
+			log.Printf("Error listing branches to index for search: %v\n", err)
+		} else {
+			syncBranchesSearchIndex(branches)
+		}
+
+		for range searchIndexQueue.signal {
+			var branches []*Branch
+			for _, item := range takeQueuedSearchIndexItems() {
+				branch, err := GetDbBranch(item.planId, item.branch)
+
+				if err != nil {
+					log.Printf("Error getting branch %s of plan %s to index for search: %v\n", item.branch, item.planId, err)
+					continue
+				}
+
+				// a new branch that isn't stored yet is indexed on its first commit
+				if branch == nil {
+					continue
+				}
+
+				branches = append(branches, branch)
+			}
+
+			syncBranchesSearchIndex(branches)
+		}
+	}()
+}
+
+// syncBranchesSearchIndex reindexes the convo messages and descriptions of any of the given branches that have new commits since they were last indexed. Branches that fail to index are logged and still searched as of their last successful index.
+func syncBranchesSearchIndex(branches []*Branch) {
+	sem := make(chan struct{}, searchIndexConcurrency)
+	var wg sync.WaitGroup
+
+	for _, branch := range branches {
+		wg.Add(1)
+		go func(branch *Branch) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			err := syncBranchSearchIndex(branch)
+			if err != nil {
+				log.Printf("Error indexing branch %s of plan %s for search: %v\n", branch.Name, branch.PlanId, err)
+			}
+		}(branch)
+	}
+
+	wg.Wait()
+}
+
+func syncBranchSearchIndex(branch *Branch) error {
+	dir := getPlanDir(branch.OrgId, branch.PlanId)
+
+	out, err := exec.Command("git", "-C", dir, "rev-parse", "refs/heads/"+branch.Name).Output()
+	if err != nil {
+		return fmt.Errorf("error getting latest commit on branch %s for dir: %s, err: %v", branch.Name, dir, err)
+	}
+	sha := strings.TrimSpace(string(out))
+
+	indexedSha, err := getIndexedSha(Conn, branch.Id)
+
+	if err != nil {
+		return err
+	}
+
+	if indexedSha == sha {
+		return nil
+	}
+
+	convoFiles, err := GitReadBranchFiles(branch.OrgId, branch.PlanId, sha, "conversation")
+
+	if err != nil {
+		return fmt.Errorf("error reading convo: %v", err)
+	}
+
+	descFiles, err := GitReadBranchFiles(branch.OrgId, branch.PlanId, sha, "descriptions")
+
+	if err != nil {
+		return fmt.Errorf("error reading descriptions: %v", err)
+	}
+
+	messagesById := map[string]*ConvoMessage{}
+	for name, bytes := range convoFiles {
+		var msg ConvoMessage
+		err := json.Unmarshal(bytes, &msg)
+		if err != nil {
+			return fmt.Errorf("error unmarshalling convo message %s: %v", name, err)
+		}
+		messagesById[msg.Id] = &msg
+	}
+
+	var descriptions []*ConvoMessageDescription
+	for name, bytes := range descFiles {
+		var desc ConvoMessageDescription
+		err := json.Unmarshal(bytes, &desc)
+		if err != nil {
+			return fmt.Errorf("error unmarshalling convo message description %s: %v", name, err)
+		}
+		descriptions = append(descriptions, &desc)
+	}
+
+	tx, err := Conn.Beginx()
+
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %v", err)
+	}
+
+	// Ensure that rollback is attempted in case of failure
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Printf("transaction rollback error: %v\n", rbErr)
+			}
+		}
+	}()
+
+	// lock the branch row so server instances indexing at the same time don't index the same branch twice
+	_, err = tx.Exec("SELECT id FROM branches WHERE id = $1 FOR UPDATE", branch.Id)
+
+	if err != nil {
+		return fmt.Errorf("error locking branch: %v", err)
+	}
+
+	indexedSha, err = getIndexedSha(tx, branch.Id)
+
+	if err != nil {
+		return err
+	}
+
+	if indexedSha == sha {
+		err = tx.Commit()
+		if err != nil {
+			return fmt.Errorf("error committing transaction: %v", err)
+		}
+		return nil
+	}
+
+	_, err = tx.Exec("DELETE FROM plan_search_entries WHERE branch_id = $1", branch.Id)
+
+	if err != nil {
+		return fmt.Errorf("error deleting search entries: %v", err)
+	}
+
+	query := "INSERT INTO plan_search_entries (org_id, plan_id, branch_id, kind, convo_message_id, message_num, role, body, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)"
+
+	for _, msg := range messagesById {
+		_, err = tx.Exec(query, branch.OrgId, branch.PlanId, branch.Id, shared.PlanSearchResultKindMessage, msg.Id, msg.Num, msg.Role, searchMatchDelimReplacer.Replace(msg.Message), msg.CreatedAt)
+
+		if err != nil {
+			return fmt.Errorf("error inserting search entry for message %s: %v", msg.Id, err)
+		}
+	}
+
+	for _, desc := range descriptions {
+		msg, ok := messagesById[desc.ConvoMessageId]
+		if !ok || desc.CommitMsg == "" {
+			continue
+		}
+
+		_, err = tx.Exec(query, branch.OrgId, branch.PlanId, branch.Id, shared.PlanSearchResultKindDescription, msg.Id, msg.Num, msg.Role, searchMatchDelimReplacer.Replace(desc.CommitMsg), desc.CreatedAt)
+
+		if err != nil {
+			return fmt.Errorf("error inserting search entry for description %s: %v", desc.Id, err)
+		}
+	}
+
+	_, err = tx.Exec("INSERT INTO plan_search_branches (branch_id, indexed_sha) VALUES ($1, $2) ON CONFLICT (branch_id) DO UPDATE SET indexed_sha = excluded.indexed_sha, updated_at = NOW()", branch.Id, sha)
+
+	if err != nil {
+		return fmt.Errorf("error updating indexed sha: %v", err)
+	}
+
+	err = tx.Commit()
+
+	if err != nil {
+		return fmt.Errorf("error committing transaction: %v", err)
+	}
+
+	return nil
+}
+
+func getIndexedSha(q interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}, branchId string) (string, error) {
+	var sha string
+	err := q.QueryRow("SELECT indexed_sha FROM plan_search_branches WHERE branch_id = $1", branchId).Scan(&sha)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", fmt.Errorf("error getting indexed sha: %v", err)
+	}
+
+	return sha, nil
+}
+
+// SearchPlans runs a full-text search over the names of the given plans and the convo messages and descriptions on their branches. Plan name matches come first, then message matches by rank. A message that matches on several branches is returned once with all its branches.
+func SearchPlans(plans []*Plan, query string) ([]*shared.PlanSearchResult, error) {
+	var planIds []string
+	plansById := map[string]*Plan{}
+	for _, plan := range plans {
+		planIds = append(planIds, plan.Id)
+		plansById[plan.Id] = plan
+	}
+
+	var nameRows []struct {
+		PlanId  string  `db:"id"`
+		Snippet string  `db:"snippet"`
+		Rank    float64 `db:"rank"`
+	}
+
+	likePattern := "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(query) + "%"
+
+	err := Conn.Select(&nameRows, `
+		SELECT id, ts_headline('english', name, q, $3) AS snippet, ts_rank(to_tsvector('english', name), q) AS rank
+		FROM plans, websearch_to_tsquery('english', $2) q
+		WHERE id = ANY($1) AND (to_tsvector('english', name) @@ q OR name ILIKE $4)
+		ORDER BY rank DESC, updated_at DESC
+		LIMIT $5`,
+		pq.Array(planIds), query, searchHeadlineOpts, likePattern, maxPlanSearchResults)
+
+	if err != nil {
+		return nil, fmt.Errorf("error searching plan names: %v", err)
+	}
+
+	var results []*shared.PlanSearchResult
+
+	for _, row := range nameRows {
+		plan := plansById[row.PlanId]
+		results = append(results, &shared.PlanSearchResult{
+			PlanId:    plan.Id,
+			PlanName:  plan.Name,
+			ProjectId: plan.ProjectId,
+			Kind:      shared.PlanSearchResultKindPlan,
+			Snippet:   row.Snippet,
+			Rank:      row.Rank,
+		})
+	}
+
+	var entryRows []searchEntryRow
+
+	// over-fetch since messages shared by several branches are merged below
+	err = Conn.Select(&entryRows, `
+		SELECT e.plan_id, e.convo_message_id, e.kind, e.message_num, e.role, b.name AS branch, ts_headline('english', e.body, q, $3) AS snippet, ts_rank(e.search_vector, q) AS rank
+		FROM plan_search_entries e
+		JOIN branches b ON b.id = e.branch_id,
+		websearch_to_tsquery('english', $2) q
+		WHERE e.plan_id = ANY($1) AND e.search_vector @@ q
+		ORDER BY rank DESC, e.created_at DESC
+		LIMIT $4`,
+		pq.Array(planIds), query, searchHeadlineOpts, maxPlanSearchResults*10)
+
+	if err != nil {
+		return nil, fmt.Errorf("error searching convo: %v", err)
+	}
+
+	results = mergeSearchEntryRows(results, entryRows, plansById)
+
+	for _, res := range results {
+		res.Snippet = cleanSearchSnippet(res.Snippet)
+	}
+
+	return results, nil
+}
+
+type searchEntryRow struct {
+	PlanId         string  `db:"plan_id"`
+	ConvoMessageId string  `db:"convo_message_id"`
+	Kind           string  `db:"kind"`
+	MessageNum     int     `db:"message_num"`
+	Role           string  `db:"role"`
+	Branch         string  `db:"branch"`
+	Snippet        string  `db:"snippet"`
+	Rank           float64 `db:"rank"`
+}
+
+// mergeSearchEntryRows appends rows ordered by rank to results, merging rows for the same message and kind on different branches into one result that keeps the best ranked row's snippet. Results are capped at maxPlanSearchResults, but branches are still added to results already included.
+func mergeSearchEntryRows(results []*shared.PlanSearchResult, rows []searchEntryRow, plansById map[string]*Plan) []*shared.PlanSearchResult {
+	byKey := map[string]*shared.PlanSearchResult{}
+
+	for _, row := range rows {
+		key := strings.Join([]string{row.PlanId, row.ConvoMessageId, row.Kind}, "|")
+
+		if res, ok := byKey[key]; ok {
+			res.Branches = append(res.Branches, row.Branch)
+			continue
+		}
+
+		if len(results) >= maxPlanSearchResults {
+			continue
+		}
+
+		plan := plansById[row.PlanId]
+		res := &shared.PlanSearchResult{
+			PlanId:     plan.Id,
+			PlanName:   plan.Name,
+			ProjectId:  plan.ProjectId,
+			Kind:       shared.PlanSearchResultKind(row.Kind),
+			Branches:   []string{row.Branch},
+			MessageNum: row.MessageNum,
+			Role:       row.Role,
+			Snippet:    row.Snippet,
+			Rank:       row.Rank,
+		}
+		byKey[key] = res
+		results = append(results, res)
+	}
+
+	return results
+}
+
+// cleanSearchSnippet collapses whitespace and strips markdown emphasis so snippets read as plain text on one line
+func cleanSearchSnippet(snippet string) string {
+	snippet = strings.Join(strings.Fields(snippet), " ")
+	for _, re := range searchSnippetEmphasisRegexes {
+		snippet = re.ReplaceAllString(snippet, "$1")
+	}
+	return snippet
+}
@@ -0,0 +1,122 @@
+package db
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/plandex/plandex/shared"
+)
+
+func TestMergeSearchEntryRows(t *testing.T) {
+	plansById := map[string]*Plan{
+		"p1": {Id: "p1", Name: "plan one", ProjectId: "proj"},
+		"p2": {Id: "p2", Name: "plan two", ProjectId: "proj"},
+	}
+
+	nameMatch := &shared.PlanSearchResult{PlanId: "p2", Kind: shared.PlanSearchResultKindPlan, Snippet: "plan two"}
+
+	rows := []searchEntryRow{
+		{PlanId: "p1", ConvoMessageId: "m1", Kind: "message", MessageNum: 1, Role: "user", Branch: "main", Snippet: "best", Rank: 0.9},
+		{PlanId: "p1", ConvoMessageId: "m1", Kind: "description", MessageNum: 1, Role: "user", Branch: "main", Snippet: "desc", Rank: 0.8},
+		{PlanId: "p1", ConvoMessageId: "m1", Kind: "message", MessageNum: 1, Role: "user", Branch: "feature", Snippet: "worse", Rank: 0.5},
+		// same message id in another plan isn't merged
+		{PlanId: "p2", ConvoMessageId: "m1", Kind: "message", MessageNum: 3, Role: "assistant", Branch: "main", Snippet: "other", Rank: 0.4},
+	}
+
+	results := mergeSearchEntryRows([]*shared.PlanSearchResult{nameMatch}, rows, plansById)
+
+	if len(results) != 4 {
+		t.Fatalf("expected 4 results, got %d", len(results))
+	}
+
+	if results[0] != nameMatch {
+		t.Errorf("expected plan name match to stay first")
+	}
+
+	msg := results[1]
+	if msg.Snippet != "best" || msg.Rank != 0.9 {
+		t.Errorf("expected best ranked snippet to be kept, got %q (%v)", msg.Snippet, msg.Rank)
+	}
+	if !reflect.DeepEqual(msg.Branches, []string{"main", "feature"}) {
+		t.Errorf("expected branches to be merged, got %v", msg.Branches)
+	}
+	if msg.PlanName != "plan one" || msg.ProjectId != "proj" {
+		t.Errorf("expected plan fields to be set, got %q %q", msg.PlanName, msg.ProjectId)
+	}
+
+	if results[2].Kind != shared.PlanSearchResultKindDescription || !reflect.DeepEqual(results[2].Branches, []string{"main"}) {
+		t.Errorf("expected description to be a separate result, got %s %v", results[2].Kind, results[2].Branches)
+	}
+
+	if results[3].PlanId != "p2" || results[3].MessageNum != 3 {
+		t.Errorf("expected message in other plan to be a separate result, got %s %d", results[3].PlanId, results[3].MessageNum)
+	}
+}
+
+func TestMergeSearchEntryRowsLimit(t *testing.T) {
+	plansById := map[string]*Plan{"p1": {Id: "p1"}}
+
+	var rows []searchEntryRow
+	for i := 0; i < maxPlanSearchResults+10; i++ {
+		rows = append(rows, searchEntryRow{PlanId: "p1", ConvoMessageId: fmt.Sprintf("m%d", i), Kind: "message", Branch: "main"})
+	}
+	// a later row for a message that's already included still adds its branch
+	rows = append(rows, searchEntryRow{PlanId: "p1", ConvoMessageId: "m0", Kind: "message", Branch: "feature"})
+
+	results := mergeSearchEntryRows(nil, rows, plansById)
+
+	if len(results) != maxPlanSearchResults {
+		t.Fatalf("expected %d results, got %d", maxPlanSearchResults, len(results))
+	}
+
+	if !reflect.DeepEqual(results[0].Branches, []string{"main", "feature"}) {
+		t.Errorf("expected branches to be merged past the limit, got %v", results[0].Branches)
+	}
+}
+
+func TestQueueBranchSearchIndex(t *testing.T) {
+	// drop anything queued by commits in other tests
+	takeQueuedSearchIndexItems()
+
+	QueueBranchSearchIndex("o", "p", "main")
+	QueueBranchSearchIndex("o", "p", "main")
+	QueueBranchSearchIndex("o", "p", "feature")
+
+	items := takeQueuedSearchIndexItems()
+
+	if len(items) != 2 {
+		t.Fatalf("expected repeated writes to a branch to be queued once, got %d items", len(items))
+	}
+
+	if len(takeQueuedSearchIndexItems()) != 0 {
+		t.Errorf("expected queue to be empty after taking items")
+	}
+
+	select {
+	case <-searchIndexQueue.signal:
+	default:
+		t.Errorf("expected worker to be signalled")
+	}
+}
+
+func TestCleanSearchSnippet(t *testing.T) {
+	start, stop := shared.PlanSearchMatchStart, shared.PlanSearchMatchStop
+
+	tests := []struct {
+		snippet  string
+		expected string
+	}{
+		{"the **" + start + "lock" + stop + "** is\n\n  held", "the " + start + "lock" + stop + " is held"},
+		{"use __bold__ and *" + start + "italic" + stop + "* and ~~old~~", "use bold and " + start + "italic" + stop + " and old"},
+		{"keep snake_case_names and a * b", "keep snake_case_names and a * b"},
+		{"2 ** 8 stays", "2 ** 8 stays"},
+	}
+
+	for _, test := range tests {
+		got := cleanSearchSnippet(test.snippet)
+		if got != test.expected {
+			t.Errorf("cleanSearchSnippet(%q) = %q, expected %q", test.snippet, got, test.expected)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package db
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/plandex/plandex/shared"
+)
+
+var tagNameRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+var allDigitsRegex = regexp.MustCompile(`^[0-9]+$`)
+
+func ValidatePlanTagName(name string) error {
+	if !tagNameRegex.MatchString(name) || strings.Contains(name, "..") || strings.HasSuffix(name, ".lock") {
+		return fmt.Errorf("invalid tag name '%s': use letters, numbers, dots, dashes, and underscores", name)
+	}
+	// 'plandex rewind 3' rewinds 3 steps, so a tag named '3' could never be rewound to
+	if allDigitsRegex.MatchString(name) {
+		return fmt.Errorf("invalid tag name '%s': tag names can't be all numbers", name)
+	}
+	return nil
+}
+
+// CreatePlanTag tags the latest commit on the checked out branch. It fails if the tag already exists.
+func CreatePlanTag(orgId, planId, name, message string) (*shared.PlanTag, error) {
+	dir := getPlanDir(orgId, planId)
+
+	if message == "" {
+		message = name
+	}
+
+	res, err := exec.Command("git", "-C", dir, "tag", "-a", name, "-m", message).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("error creating tag %s for dir: %s, err: %v, output: %s", name, dir, err, string(res))
+	}
+
+	return GetPlanTag(orgId, planId, name)
+}
+
+// GetPlanTag returns nil if the tag doesn't exist
+func GetPlanTag(orgId, planId, name string) (*shared.PlanTag, error) {
+	tags, err := ListPlanTags(orgId, planId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tag := range tags {
+		if tag.Name == name {
+			return tag, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// ListPlanTags returns the plan's tags, newest first, marking those in the checked out branch's history
+func ListPlanTags(orgId, planId string) ([]*shared.PlanTag, error) {
+	dir := getPlanDir(orgId, planId)
+
+	out, err := exec.Command("git", "-C", dir, "for-each-ref", "refs/tags", "--sort=-creatordate", "--format=%(refname:short)@@|@@%(objectname:short)@@|@@%(*objectname:short)@@|@@%(creatordate:unix)@@|@@%(contents)@>>>@").Output()
+	if err != nil {
+		return nil, fmt.Errorf("error listing tags for dir: %s, err: %v", dir, err)
+	}
+
+	merged, err := exec.Command("git", "-C", dir, "tag", "--merged", "HEAD").Output()
+	if err != nil {
+		return nil, fmt.Errorf("error listing tags merged into HEAD for dir: %s, err: %v", dir, err)
+	}
+
+	onBranch := map[string]bool{}
+	for _, name := range strings.Fields(string(merged)) {
+		onBranch[name] = true
+	}
+
+	var tags []*shared.PlanTag
+	for _, entry := range strings.Split(string(out), "@>>>@") {
+		parts := strings.Split(strings.TrimSpace(entry), "@@|@@")
+		if len(parts) != 5 {
+			continue
+		}
+
+		timestamp, err := strconv.ParseInt(parts[3], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing tag timestamp: %v", err)
+		}
+
+		// annotated tags point to a tag object, which points to the commit
+		sha := parts[2]
+		if sha == "" {
+			sha = parts[1]
+		}
+
+		tags = append(tags, &shared.PlanTag{
+			Name:      parts[0],
+			Sha:       sha,
+			Message:   strings.TrimSpace(parts[4]),
+			CreatedAt: time.Unix(timestamp, 0).UTC(),
+			OnBranch:  onBranch[parts[0]],
+		})
+	}
+
+	return tags, nil
+}
+
+func DeletePlanTag(orgId, planId, name string) error {
+	dir := getPlanDir(orgId, planId)
+
+	res, err := exec.Command("git", "-C", dir, "tag", "-d", name).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error deleting tag %s for dir: %s, err: %v, output: %s", name, dir, err, string(res))
+	}
+
+	return nil
+}
+
+// ResolvePlanTag returns the sha of the commit a tag points to. It fails if the commit isn't in the checked out branch's history, since resetting to it would replace the branch with another branch's history.
+func ResolvePlanTag(orgId, planId, name string) (string, error) {
+	dir := getPlanDir(orgId, planId)
+
+	res, err := exec.Command("git", "-C", dir, "rev-parse", "--short", "refs/tags/"+name+"^{commit}").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("tag %s not found", name)
+	}
+
+	sha := strings.TrimSpace(string(res))
+
+	onBranch, err := GitIsAncestorOfHead(orgId, planId, sha)
+	if err != nil {
+		return "", err
+	}
+
+	if !onBranch {
+		return "", fmt.Errorf("tag %s isn't in the history of the current branch", name)
+	}
+
+	return sha, nil
+}
+
+// getTagNamesByCommit returns tag names keyed by the full sha of the commit they point to
+func getTagNamesByCommit(dir string) (map[string][]string, error) {
+	out, err := exec.Command("git", "-C", dir, "for-each-ref", "refs/tags", "--sort=creatordate", "--format=%(refname:short) %(objectname) %(*objectname)").Output()
+	if err != nil {
+		return nil, fmt.Errorf("error listing tags for dir: %s, err: %v", dir, err)
+	}
+
+	res := map[string][]string{}
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		sha := fields[len(fields)-1]
+		res[sha] = append(res[sha], fields[0])
+	}
+
+	return res, nil
+}
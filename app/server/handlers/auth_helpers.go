@@ -201,34 +201,41 @@ func authorizeProjectDelete(w http.ResponseWriter, projectId string, auth *types
 	return true
 }
 
-func authorizePlan(w http.ResponseWriter, planId string, auth *types.ServerAuth) *db.Plan {
+func authorizePlanAccess(w http.ResponseWriter, planId string, auth *types.ServerAuth) (*db.Plan, shared.PlanAccess) {
 	log.Println("authorizing plan")
 
-	plan, err := db.ValidatePlanAccess(planId, auth.User.Id, auth.OrgId)
+	plan, access, err := db.GetPlanAccess(planId, auth.User.Id, auth.OrgId)
 
 	if err != nil {
 		log.Printf("error validating plan membership: %v\n", err)
 		http.Error(w, "error validating plan membership", http.StatusInternalServerError)
-		return nil
+		return nil, ""
 	}
 
 	if plan == nil {
 		log.Println("user doesn't have access the plan")
 		http.Error(w, "no access to plan", http.StatusUnauthorized)
-		return nil
+		return nil, ""
 	}
 
+	return plan, access
+}
+
+// authorizePlan allows anyone with read access to the plan
+func authorizePlan(w http.ResponseWriter, planId string, auth *types.ServerAuth) *db.Plan {
+	plan, _ := authorizePlanAccess(w, planId, auth)
 	return plan
 }
 
+// authorizePlanUpdate allows the owner, collaborators with write access, and users with permission to update any plan
 func authorizePlanUpdate(w http.ResponseWriter, planId string, auth *types.ServerAuth) *db.Plan {
-	plan := authorizePlan(w, planId, auth)
+	plan, access := authorizePlanAccess(w, planId, auth)
 
 	if plan == nil {
 		return nil
 	}
 
-	if plan.OwnerId != auth.User.Id && !auth.HasPermission(types.PermissionUpdateAnyPlan) {
+	if access != shared.PlanAccessWrite && !auth.HasPermission(types.PermissionUpdateAnyPlan) {
 		log.Println("User does not have permission to update plan")
 		http.Error(w, "User does not have permission to update plan", http.StatusForbidden)
 		return nil
@@ -237,6 +244,23 @@ func authorizePlanUpdate(w http.ResponseWriter, planId string, auth *types.Serve
 	return plan
 }
 
+// authorizePlanShare allows only the owner and users with permission to manage any plan's shares to change who a plan is shared with
+func authorizePlanShare(w http.ResponseWriter, planId string, auth *types.ServerAuth) *db.Plan {
+	plan := authorizePlan(w, planId, auth)
+
+	if plan == nil {
+		return nil
+	}
+
+	if plan.OwnerId != auth.User.Id && !auth.HasPermission(types.PermissionManageAnyPlanShares) {
+		log.Println("User does not have permission to share plan")
+		http.Error(w, "User does not have permission to share plan", http.StatusForbidden)
+		return nil
+	}
+
+	return plan
+}
+
 func authorizePlanDelete(w http.ResponseWriter, planId string, auth *types.ServerAuth) *db.Plan {
 	plan := authorizePlan(w, planId, auth)
 
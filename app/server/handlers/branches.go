@@ -3,10 +3,13 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"plandex-server/db"
+	modelPlan "plandex-server/model/plan"
+	"plandex-server/types"
 
 	"github.com/gorilla/mux"
 	"github.com/plandex/plandex/shared"
@@ -76,7 +79,7 @@ func CreateBranchHandler(w http.ResponseWriter, r *http.Request) {
 
 	log.Println("planId: ", planId)
 
-	plan := authorizePlan(w, planId, auth)
+	plan := authorizePlanUpdate(w, planId, auth)
 	if plan == nil {
 		return
 	}
@@ -167,7 +170,7 @@ func DeleteBranchHandler(w http.ResponseWriter, r *http.Request) {
 
 	log.Println("planId: ", planId)
 
-	if authorizePlan(w, planId, auth) == nil {
+	if authorizePlanUpdate(w, planId, auth) == nil {
 		return
 	}
 
@@ -213,3 +216,202 @@ func DeleteBranchHandler(w http.ResponseWriter, r *http.Request) {
 
 	log.Println("Successfully deleted branch")
 }
+
+func MergeBranchHandler(w http.ResponseWriter, r *http.Request) {
+	log.Println("Received request for MergeBranchHandler")
+
+	auth := authenticate(w, r, true)
+	if auth == nil {
+		return
+	}
+
+	vars := mux.Vars(r)
+	planId := vars["planId"]
+	branch := vars["branch"]
+
+	log.Println("planId: ", planId, "branch: ", branch)
+
+	if authorizePlanUpdate(w, planId, auth) == nil {
+		return
+	}
+
+	var req shared.MergeBranchRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		log.Printf("Error decoding request: %v\n", err)
+		http.Error(w, "Error decoding request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if req.Branch == branch {
+		log.Println("Cannot merge a branch into itself")
+		http.Error(w, "Cannot merge a branch into itself", http.StatusBadRequest)
+		return
+	}
+
+	sourceBranch, err := db.GetDbBranch(planId, req.Branch)
+
+	if err != nil {
+		log.Printf("Error getting branch: %v\n", err)
+		http.Error(w, "Error getting branch: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if sourceBranch == nil {
+		log.Printf("Branch not found: %s\n", req.Branch)
+		http.Error(w, "Branch not found: "+req.Branch, http.StatusNotFound)
+		return
+	}
+
+	for _, b := range []string{branch, req.Branch} {
+		if modelPlan.GetActivePlan(planId, b) != nil {
+			log.Printf("Plan is active on branch %s\n", b)
+			http.Error(w, fmt.Sprintf("Plan is active on branch %s -- wait for it to finish or stop it before merging", b), http.StatusConflict)
+			return
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	unlockFn := lockRepo(w, r, auth, db.LockScopeWrite, ctx, cancel, true)
+	if unlockFn == nil {
+		return
+	} else {
+		defer func() {
+			(*unlockFn)(err)
+		}()
+	}
+
+	conflictedPaths, err := db.GetMergeConflicts(auth.OrgId, planId, req.Branch)
+
+	if err != nil {
+		log.Printf("Error getting merge conflicts: %v\n", err)
+		http.Error(w, "Error getting merge conflicts: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	res := shared.MergeBranchResponse{
+		ConflictedPaths: conflictedPaths,
+	}
+
+	if len(conflictedPaths) == 0 || req.Force {
+		var msg string
+		msg, err = db.MergeBranch(db.MergeBranchParams{
+			OrgId:           auth.OrgId,
+			PlanId:          planId,
+			Branch:          branch,
+			SourceBranch:    req.Branch,
+			ConflictedPaths: conflictedPaths,
+		})
+
+		if err != nil {
+			log.Printf("Error merging branch: %v\n", err)
+			http.Error(w, "Error merging branch: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		res.Merged = msg != ""
+		res.Msg = msg
+	}
+
+	bytes, err := json.Marshal(res)
+
+	if err != nil {
+		log.Printf("Error marshalling response: %v\n", err)
+		http.Error(w, "Error marshalling response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Write(bytes)
+
+	log.Println("Successfully processed request for MergeBranchHandler")
+}
+
+func CompareBranchesHandler(w http.ResponseWriter, r *http.Request) {
+	log.Println("Received request for CompareBranchesHandler")
+
+	auth := authenticate(w, r, true)
+	if auth == nil {
+		return
+	}
+
+	vars := mux.Vars(r)
+	planId := vars["planId"]
+	branch := vars["branch"]
+	otherBranch := vars["otherBranch"]
+
+	log.Println("planId: ", planId, "branch: ", branch, "otherBranch: ", otherBranch)
+
+	if authorizePlan(w, planId, auth) == nil {
+		return
+	}
+
+	for _, b := range []string{branch, otherBranch} {
+		dbBranch, err := db.GetDbBranch(planId, b)
+
+		if err != nil {
+			log.Printf("Error getting branch: %v\n", err)
+			http.Error(w, "Error getting branch: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if dbBranch == nil {
+			log.Printf("Branch not found: %s\n", b)
+			http.Error(w, "Branch not found: "+b, http.StatusNotFound)
+			return
+		}
+	}
+
+	// each branch is read under its own lock since the branch has to be checked out to read it
+	a := getBranchCompareState(w, auth, planId, branch)
+	if a == nil {
+		return
+	}
+
+	b := getBranchCompareState(w, auth, planId, otherBranch)
+	if b == nil {
+		return
+	}
+
+	res, err := db.CompareBranches(auth.OrgId, a, b)
+
+	if err != nil {
+		log.Printf("Error comparing branches: %v\n", err)
+		http.Error(w, "Error comparing branches: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	bytes, err := json.Marshal(res)
+
+	if err != nil {
+		log.Printf("Error marshalling response: %v\n", err)
+		http.Error(w, "Error marshalling response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Write(bytes)
+
+	log.Println("Successfully processed request for CompareBranchesHandler")
+}
+
+func getBranchCompareState(w http.ResponseWriter, auth *types.ServerAuth, planId, branch string) *db.BranchCompareState {
+	var err error
+	ctx, cancel := context.WithCancel(context.Background())
+	unlockFn := lockRepoBranch(w, auth, db.LockScopeRead, ctx, cancel, planId, branch)
+	if unlockFn == nil {
+		return nil
+	} else {
+		defer func() {
+			(*unlockFn)(err)
+		}()
+	}
+
+	state, err := db.GetBranchCompareState(auth.OrgId, planId, branch)
+
+	if err != nil {
+		log.Printf("Error getting branch state: %v\n", err)
+		http.Error(w, "Error getting branch state: "+err.Error(), http.StatusInternalServerError)
+		return nil
+	}
+
+	return state
+}
@@ -82,6 +82,11 @@ func initClients(params initClientsParams) map[string]*openai.Client {
 			endpointsByApiKeyEnvVar[envVar] = planSettings.ModelPack.GetAutoFix().BaseModelConfig.BaseUrl
 			continue
 		}
+
+		if planSettings.ModelPack.GetReviewer().BaseModelConfig.ApiKeyEnvVar == envVar {
+			endpointsByApiKeyEnvVar[envVar] = planSettings.ModelPack.GetReviewer().BaseModelConfig.BaseUrl
+			continue
+		}
 	}
 
 	clients := model.InitClients(apiKeys, endpointsByApiKeyEnvVar, endpoint, openAIOrgId)
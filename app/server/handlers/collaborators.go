@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"plandex-server/db"
+	"plandex-server/types"
+
+	"github.com/gorilla/mux"
+	"github.com/plandex/plandex/shared"
+)
+
+func ListPlanCollaboratorsHandler(w http.ResponseWriter, r *http.Request) {
+	log.Println("Received request for ListPlanCollaboratorsHandler")
+
+	auth := authenticate(w, r, true)
+	if auth == nil {
+		return
+	}
+
+	planId := mux.Vars(r)["planId"]
+
+	log.Println("planId: ", planId)
+
+	plan := authorizePlan(w, planId, auth)
+	if plan == nil {
+		return
+	}
+
+	collaborators, err := db.ListPlanCollaborators(planId)
+
+	if err != nil {
+		log.Println("Error listing plan collaborators: ", err)
+		http.Error(w, "Error listing plan collaborators", http.StatusInternalServerError)
+		return
+	}
+
+	res := shared.ListPlanCollaboratorsResponse{
+		SharedWithOrgAccess: plan.ToApi().SharedWithOrgAccess,
+		Collaborators:       collaborators,
+	}
+
+	bytes, err := json.Marshal(res)
+
+	if err != nil {
+		log.Println("Error marshalling response: ", err)
+		http.Error(w, "Error marshalling response", http.StatusInternalServerError)
+		return
+	}
+
+	log.Println("ListPlanCollaboratorsHandler processed successfully")
+
+	w.Write(bytes)
+}
+
+func SharePlanHandler(w http.ResponseWriter, r *http.Request) {
+	log.Println("Received request for SharePlanHandler")
+
+	auth := authenticate(w, r, true)
+	if auth == nil {
+		return
+	}
+
+	planId := mux.Vars(r)["planId"]
+
+	log.Println("planId: ", planId)
+
+	plan := authorizePlanShare(w, planId, auth)
+	if plan == nil {
+		return
+	}
+
+	var req shared.SharePlanRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+
+	if err != nil {
+		log.Println("Error decoding request body: ", err)
+		http.Error(w, "Error decoding request body", http.StatusInternalServerError)
+		return
+	}
+
+	if req.Access != shared.PlanAccessRead && req.Access != shared.PlanAccessWrite {
+		log.Println("Invalid access: ", req.Access)
+		http.Error(w, "Invalid access", http.StatusBadRequest)
+		return
+	}
+
+	if req.Email == "" {
+		err = db.SharePlanWithOrg(planId, req.Access)
+
+		if err != nil {
+			log.Println("Error sharing plan with org: ", err)
+			http.Error(w, "Error sharing plan with org", http.StatusInternalServerError)
+			return
+		}
+
+		log.Println("SharePlanHandler processed successfully")
+		return
+	}
+
+	user := getCollaboratorUser(w, req.Email, auth)
+	if user == nil {
+		return
+	}
+
+	if user.Id == plan.OwnerId {
+		log.Println("Can't share plan with its owner")
+		http.Error(w, "Can't share a plan with its owner", http.StatusBadRequest)
+		return
+	}
+
+	err = db.StorePlanCollaborator(auth.OrgId, planId, user.Id, req.Access)
+
+	if err != nil {
+		log.Println("Error storing plan collaborator: ", err)
+		http.Error(w, "Error storing plan collaborator", http.StatusInternalServerError)
+		return
+	}
+
+	log.Println("SharePlanHandler processed successfully")
+}
+
+func UnsharePlanHandler(w http.ResponseWriter, r *http.Request) {
+	log.Println("Received request for UnsharePlanHandler")
+
+	auth := authenticate(w, r, true)
+	if auth == nil {
+		return
+	}
+
+	planId := mux.Vars(r)["planId"]
+
+	log.Println("planId: ", planId)
+
+	if authorizePlanShare(w, planId, auth) == nil {
+		return
+	}
+
+	var req shared.UnsharePlanRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+
+	if err != nil {
+		log.Println("Error decoding request body: ", err)
+		http.Error(w, "Error decoding request body", http.StatusInternalServerError)
+		return
+	}
+
+	if req.Email == "" {
+		err = db.UnsharePlanWithOrg(planId)
+
+		if err != nil {
+			log.Println("Error unsharing plan with org: ", err)
+			http.Error(w, "Error unsharing plan with org", http.StatusInternalServerError)
+			return
+		}
+
+		log.Println("UnsharePlanHandler processed successfully")
+		return
+	}
+
+	user := getCollaboratorUser(w, req.Email, auth)
+	if user == nil {
+		return
+	}
+
+	deleted, err := db.DeletePlanCollaborator(planId, user.Id)
+
+	if err != nil {
+		log.Println("Error deleting plan collaborator: ", err)
+		http.Error(w, "Error deleting plan collaborator", http.StatusInternalServerError)
+		return
+	}
+
+	if !deleted {
+		log.Println("Plan isn't shared with user")
+		http.Error(w, "Plan isn't shared with "+req.Email, http.StatusNotFound)
+		return
+	}
+
+	log.Println("UnsharePlanHandler processed successfully")
+}
+
+// getCollaboratorUser looks up a user by email, who must be a member of the org
+func getCollaboratorUser(w http.ResponseWriter, email string, auth *types.ServerAuth) *db.User {
+	user, err := db.GetUserByEmail(email)
+
+	if err != nil {
+		log.Println("Error getting user: ", err)
+		http.Error(w, "Error getting user", http.StatusInternalServerError)
+		return nil
+	}
+
+	var orgUser *db.OrgUser
+	if user != nil {
+		orgUser, err = db.GetOrgUser(user.Id, auth.OrgId)
+
+		if err != nil {
+			log.Println("Error getting org user: ", err)
+			http.Error(w, "Error getting org user", http.StatusInternalServerError)
+			return nil
+		}
+	}
+
+	if orgUser == nil {
+		log.Println("User isn't a member of the org: ", email)
+		http.Error(w, email+" isn't a member of the org", http.StatusNotFound)
+		return nil
+	}
+
+	return user
+}
@@ -19,6 +19,15 @@ func loadContexts(w http.ResponseWriter, r *http.Request, auth *types.ServerAuth
 	var settings *shared.PlanSettings
 	var client *openai.Client
 
+	for _, context := range *loadReq {
+		err = shared.ValidateContextPath(context.FilePath)
+		if err != nil {
+			log.Printf("Error loading context: %v\n", err)
+			http.Error(w, "Error loading context: "+err.Error(), http.StatusBadRequest)
+			return nil, nil
+		}
+	}
+
 	for _, context := range *loadReq {
 		if context.ContextType == shared.ContextPipedDataType || context.ContextType == shared.ContextNoteType || context.ContextType == shared.ContextImageType {
 			settings, err = db.GetPlanSettings(plan, true)
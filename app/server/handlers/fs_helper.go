@@ -27,6 +27,11 @@ func lockRepo(w http.ResponseWriter, r *http.Request, auth *types.ServerAuth, sc
         return nil
     }
 
+    return lockRepoBranch(w, auth, scope, ctx, cancelFn, planId, branch)
+}
+
+// lockRepoBranch locks the plan repo with a branch other than the one in the request path checked out
+func lockRepoBranch(w http.ResponseWriter, auth *types.ServerAuth, scope db.LockScope, ctx context.Context, cancelFn context.CancelFunc, planId, branch string) *func(err error) {
     lockCtx, lockCancel := context.WithTimeout(ctx, 60*time.Second)
     defer lockCancel()
 
@@ -9,6 +9,8 @@ import (
 	"net/http"
 	"plandex-server/db"
 	modelPlan "plandex-server/model/plan"
+	"plandex-server/types"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -25,6 +27,7 @@ func CurrentPlanHandler(w http.ResponseWriter, r *http.Request) {
 
 	vars := mux.Vars(r)
 	planId := vars["planId"]
+	branch := vars["branch"]
 
 	log.Println("planId: ", planId)
 
@@ -55,6 +58,22 @@ func CurrentPlanHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	planState.Review, err = db.GetCurrentPlanReview(auth.OrgId, planId, planState)
+
+	if err != nil {
+		log.Printf("Error getting plan review: %v\n", err)
+		http.Error(w, "Error getting plan review: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	planState.ReviewState, err = db.GetPlanReviewState(auth.OrgId, planId, branch)
+
+	if err != nil {
+		log.Printf("Error getting review state: %v\n", err)
+		http.Error(w, "Error getting review state: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	jsonBytes, err := json.Marshal(planState)
 
 	if err != nil {
@@ -81,7 +100,7 @@ func ApplyPlanHandler(w http.ResponseWriter, r *http.Request) {
 	branch := vars["branch"]
 	log.Println("planId: ", planId)
 
-	plan := authorizePlan(w, planId, auth)
+	plan := authorizePlanUpdate(w, planId, auth)
 	if plan == nil {
 		return
 	}
@@ -121,6 +140,23 @@ func ApplyPlanHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !auth.HasPermission(types.PermissionApplyWithoutReview) {
+		var pending []string
+		pending, err = getPendingReviewers(auth.OrgId, planId, branch)
+
+		if err != nil {
+			log.Printf("Error checking review requests: %v\n", err)
+			http.Error(w, "Error checking review requests: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if len(pending) > 0 {
+			log.Printf("Plan is waiting on review from %v\n", pending)
+			http.Error(w, "Changes can't be applied until they're approved by "+strings.Join(pending, ", "), http.StatusForbidden)
+			return
+		}
+	}
+
 	currentPlan, err := db.ApplyPlan(auth.OrgId, auth.User.Id, branch, plan)
 
 	if err != nil {
@@ -168,7 +204,7 @@ func RejectAllChangesHandler(w http.ResponseWriter, r *http.Request) {
 	branch := vars["branch"]
 	log.Println("planId: ", planId, "branch: ", branch)
 
-	if authorizePlan(w, planId, auth) == nil {
+	if authorizePlanUpdate(w, planId, auth) == nil {
 		return
 	}
 
@@ -216,7 +252,7 @@ func RejectFileHandler(w http.ResponseWriter, r *http.Request) {
 
 	log.Println("planId: ", planId, "branch: ", branch)
 
-	if authorizePlan(w, planId, auth) == nil {
+	if authorizePlanUpdate(w, planId, auth) == nil {
 		return
 	}
 
@@ -271,7 +307,7 @@ func RejectFilesHandler(w http.ResponseWriter, r *http.Request) {
 
 	log.Println("planId: ", planId, "branch: ", branch)
 
-	if authorizePlan(w, planId, auth) == nil {
+	if authorizePlanUpdate(w, planId, auth) == nil {
 		return
 	}
 
@@ -446,7 +482,7 @@ func GetPlanDiffsHandler(w http.ResponseWriter, r *http.Request) {
 		}()
 	}
 
-	diffs, err := db.GetPlanDiffs(auth.OrgId, planId)
+	diffs, err := db.GetPlanDiffs(auth.OrgId, planId, false)
 
 	if err != nil {
 		log.Printf("Error getting plan diffs: %v\n", err)
@@ -458,3 +494,32 @@ func GetPlanDiffsHandler(w http.ResponseWriter, r *http.Request) {
 
 	log.Println("Successfully retrieved plan diffs")
 }
+
+// getPendingReviewers returns the emails of requested reviewers who haven't yet approved every pending file
+func getPendingReviewers(orgId, planId, branch string) ([]string, error) {
+	reviewState, err := db.GetPlanReviewState(orgId, planId, branch)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(reviewState.Requests) == 0 {
+		return nil, nil
+	}
+
+	planState, err := db.GetCurrentPlanState(db.CurrentPlanStateParams{
+		OrgId:  orgId,
+		PlanId: planId,
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("error getting current plan state: %v", err)
+	}
+
+	var pending []string
+	for _, request := range reviewState.PendingRequests(planState.PlanResult) {
+		pending = append(pending, request.ReviewerEmail)
+	}
+
+	return pending, nil
+}
@@ -3,10 +3,13 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"path/filepath"
 	"plandex-server/db"
+	"strings"
 
 	"github.com/gorilla/mux"
 	"github.com/plandex/plandex/shared"
@@ -77,7 +80,7 @@ func LoadContextHandler(w http.ResponseWriter, r *http.Request) {
 	branchName := vars["branch"]
 	log.Println("planId: ", planId)
 
-	plan := authorizePlan(w, planId, auth)
+	plan := authorizePlanUpdate(w, planId, auth)
 	if plan == nil {
 		return
 	}
@@ -130,7 +133,7 @@ func UpdateContextHandler(w http.ResponseWriter, r *http.Request) {
 	branchName := vars["branch"]
 	log.Println("planId: ", planId)
 
-	plan := authorizePlan(w, planId, auth)
+	plan := authorizePlanUpdate(w, planId, auth)
 	if plan == nil {
 		return
 	}
@@ -222,7 +225,7 @@ func DeleteContextHandler(w http.ResponseWriter, r *http.Request) {
 	branchName := vars["branch"]
 	log.Println("planId: ", planId)
 
-	plan := authorizePlan(w, planId, auth)
+	plan := authorizePlanUpdate(w, planId, auth)
 
 	if plan == nil {
 		return
@@ -289,7 +292,9 @@ func DeleteContextHandler(w http.ResponseWriter, r *http.Request) {
 	var toRemoveApiContexts []*shared.Context
 	for _, dbContext := range toRemove {
 		toRemoveApiContexts = append(toRemoveApiContexts, dbContext.ToApi())
-		removeTokens += dbContext.NumTokens
+		if !dbContext.Disabled {
+			removeTokens += dbContext.NumTokens
+		}
 	}
 
 	commitMsg := shared.SummaryForRemoveContext(toRemoveApiContexts, branch.ContextTokens) + "\n\n" + shared.TableForRemoveContext(toRemoveApiContexts)
@@ -326,3 +331,300 @@ func DeleteContextHandler(w http.ResponseWriter, r *http.Request) {
 
 	w.Write(bytes)
 }
+
+func UpdateContextGroupHandler(w http.ResponseWriter, r *http.Request) {
+	log.Println("Received request for UpdateContextGroupHandler")
+
+	auth := authenticate(w, r, true)
+	if auth == nil {
+		return
+	}
+
+	vars := mux.Vars(r)
+	planId := vars["planId"]
+	branchName := vars["branch"]
+	log.Println("planId: ", planId)
+
+	plan := authorizePlanUpdate(w, planId, auth)
+	if plan == nil {
+		return
+	}
+
+	// read the request body
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("Error reading request body: %v\n", err)
+		http.Error(w, "Error reading request body", http.StatusInternalServerError)
+		return
+	}
+	defer r.Body.Close()
+
+	var requestBody shared.UpdateContextGroupRequest
+	if err := json.Unmarshal(body, &requestBody); err != nil {
+		log.Printf("Error parsing request body: %v\n", err)
+		http.Error(w, "Error parsing request body", http.StatusBadRequest)
+		return
+	}
+
+	if requestBody.Group == "" {
+		log.Println("Missing context group")
+		http.Error(w, "Missing context group", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	unlockFn := lockRepo(w, r, auth, db.LockScopeWrite, ctx, cancel, true)
+	if unlockFn == nil {
+		return
+	} else {
+		defer func() {
+			(*unlockFn)(err)
+		}()
+	}
+
+	branch, err := db.GetDbBranch(planId, branchName)
+
+	if err != nil {
+		log.Printf("Error getting branch: %v\n", err)
+		http.Error(w, "Error getting branch: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	dbContexts, err := db.GetPlanContexts(auth.OrgId, planId, false)
+
+	if err != nil {
+		log.Printf("Error getting contexts: %v\n", err)
+		http.Error(w, "Error getting contexts: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var toUpdate []*db.Context
+	numInGroup := 0
+	tokensChanged := 0
+	for _, dbContext := range dbContexts {
+		if dbContext.Group != requestBody.Group {
+			continue
+		}
+		numInGroup++
+
+		if dbContext.Disabled != requestBody.Disabled {
+			toUpdate = append(toUpdate, dbContext)
+			tokensChanged += dbContext.NumTokens
+		}
+	}
+
+	if numInGroup == 0 {
+		log.Printf("Context group '%s' not found\n", requestBody.Group)
+		http.Error(w, "Context group not found: "+requestBody.Group, http.StatusNotFound)
+		return
+	}
+
+	totalTokens := branch.ContextTokens
+	if requestBody.Disabled {
+		totalTokens -= tokensChanged
+	} else {
+		totalTokens += tokensChanged
+	}
+
+	settings, err := db.GetPlanSettings(plan, true)
+	if err != nil {
+		log.Printf("Error getting plan settings: %v\n", err)
+		http.Error(w, "Error getting plan settings: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	maxTokens := settings.GetPlannerEffectiveMaxTokens()
+
+	res := shared.UpdateContextGroupResponse{
+		NumContexts:   len(toUpdate),
+		TokensChanged: tokensChanged,
+		TotalTokens:   totalTokens,
+		MaxTokens:     maxTokens,
+	}
+
+	if !requestBody.Disabled && totalTokens > maxTokens {
+		log.Printf("The total number of tokens (%d) exceeds the maximum allowed (%d)", totalTokens, maxTokens)
+		res.MaxTokensExceeded = true
+	} else if len(toUpdate) > 0 {
+		for _, dbContext := range toUpdate {
+			dbContext.Disabled = requestBody.Disabled
+			err = db.StoreContextMeta(dbContext)
+
+			if err != nil {
+				log.Printf("Error storing context: %v\n", err)
+				http.Error(w, "Error storing context: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+
+		res.Msg = shared.SummaryForUpdateContextGroup(requestBody.Group, requestBody.Disabled, len(toUpdate), tokensChanged, totalTokens)
+
+		err = db.GitAddAndCommit(auth.OrgId, planId, branchName, res.Msg)
+
+		if err != nil {
+			log.Printf("Error committing changes: %v\n", err)
+			http.Error(w, "Error committing changes: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		addTokens := tokensChanged
+		if requestBody.Disabled {
+			addTokens = -tokensChanged
+		}
+
+		err = db.AddPlanContextTokens(planId, branchName, addTokens)
+		if err != nil {
+			log.Printf("Error updating plan tokens: %v\n", err)
+			http.Error(w, "Error updating plan tokens: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	bytes, err := json.Marshal(res)
+
+	if err != nil {
+		log.Printf("Error marshalling response: %v\n", err)
+		http.Error(w, "Error marshalling response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Println("Successfully processed UpdateContextGroupHandler request")
+
+	w.Write(bytes)
+}
+
+func ReplayContextChangesHandler(w http.ResponseWriter, r *http.Request) {
+	log.Println("Received request for ReplayContextChangesHandler")
+
+	auth := authenticate(w, r, true)
+	if auth == nil {
+		return
+	}
+
+	vars := mux.Vars(r)
+	planId := vars["planId"]
+	branchName := vars["branch"]
+	log.Println("planId: ", planId, "branch: ", branchName)
+
+	if authorizePlanUpdate(w, planId, auth) == nil {
+		return
+	}
+
+	// read the request body
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("Error reading request body: %v\n", err)
+		http.Error(w, "Error reading request body", http.StatusInternalServerError)
+		return
+	}
+	defer r.Body.Close()
+
+	var requestBody shared.ReplayContextChangesRequest
+	if err := json.Unmarshal(body, &requestBody); err != nil {
+		log.Printf("Error parsing request body: %v\n", err)
+		http.Error(w, "Error parsing request body", http.StatusBadRequest)
+		return
+	}
+
+	if requestBody.SourceBranch == "" || requestBody.FromMessageId == "" || requestBody.ToMessageId == "" {
+		log.Println("Missing source branch or message ids")
+		http.Error(w, "Missing source branch or message ids", http.StatusBadRequest)
+		return
+	}
+
+	sourceBranch, err := db.GetDbBranch(planId, requestBody.SourceBranch)
+
+	if err != nil {
+		log.Printf("Error getting branch: %v\n", err)
+		http.Error(w, "Error getting branch: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if sourceBranch == nil {
+		log.Printf("Branch %s not found\n", requestBody.SourceBranch)
+		http.Error(w, "Branch not found: "+requestBody.SourceBranch, http.StatusNotFound)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	unlockFn := lockRepo(w, r, auth, db.LockScopeWrite, ctx, cancel, true)
+	if unlockFn == nil {
+		return
+	} else {
+		defer func() {
+			(*unlockFn)(err)
+		}()
+	}
+
+	// the source branch's context just before each prompt was sent
+	fromSha, err := db.GitShaBeforeConvoMessage(auth.OrgId, planId, "refs/heads/"+requestBody.SourceBranch, requestBody.FromMessageId)
+
+	if err != nil {
+		log.Printf("Error getting sha before message: %v\n", err)
+		http.Error(w, "Error getting sha before message: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	toSha, err := db.GitShaBeforeConvoMessage(auth.OrgId, planId, "refs/heads/"+requestBody.SourceBranch, requestBody.ToMessageId)
+
+	if err != nil {
+		log.Printf("Error getting sha before message: %v\n", err)
+		http.Error(w, "Error getting sha before message: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	changedPaths, err := db.GitCheckoutChangedFiles(auth.OrgId, planId, fromSha, toSha, "context")
+
+	if err != nil {
+		log.Printf("Error replaying context changes: %v\n", err)
+		http.Error(w, "Error replaying context changes: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// each context is stored as a .meta and a .body file
+	changedIds := map[string]bool{}
+	for _, path := range changedPaths {
+		name := filepath.Base(path)
+		changedIds[strings.TrimSuffix(name, filepath.Ext(name))] = true
+	}
+
+	res := shared.ReplayContextChangesResponse{
+		NumChanged: len(changedIds),
+	}
+
+	if len(changedIds) > 0 {
+		suffix := "s"
+		if len(changedIds) == 1 {
+			suffix = ""
+		}
+		res.Msg = fmt.Sprintf("Replayed %d context change%s from branch %s", len(changedIds), suffix, requestBody.SourceBranch)
+
+		err = db.GitAddAndCommit(auth.OrgId, planId, branchName, res.Msg)
+
+		if err != nil {
+			log.Printf("Error committing changes: %v\n", err)
+			http.Error(w, "Error committing changes: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		err = db.SyncPlanTokens(auth.OrgId, planId, branchName)
+
+		if err != nil {
+			log.Printf("Error syncing plan tokens: %v\n", err)
+			http.Error(w, "Error syncing plan tokens: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	bytes, err := json.Marshal(res)
+
+	if err != nil {
+		log.Printf("Error marshalling response: %v\n", err)
+		http.Error(w, "Error marshalling response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Println("Successfully processed ReplayContextChangesHandler request")
+
+	w.Write(bytes)
+}
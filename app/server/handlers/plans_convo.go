@@ -58,6 +58,53 @@ func ListConvoHandler(w http.ResponseWriter, r *http.Request) {
 
 }
 
+func ListConvoDiffsHandler(w http.ResponseWriter, r *http.Request) {
+	log.Println("Received a request for ListConvoDiffsHandler")
+	auth := authenticate(w, r, true)
+	if auth == nil {
+		return
+	}
+
+	vars := mux.Vars(r)
+	planId := vars["planId"]
+
+	log.Println("planId: ", planId)
+
+	if authorizePlan(w, planId, auth) == nil {
+		return
+	}
+
+	var err error
+	ctx, cancel := context.WithCancel(context.Background())
+	unlockFn := lockRepo(w, r, auth, db.LockScopeRead, ctx, cancel, true)
+	if unlockFn == nil {
+		return
+	} else {
+		defer func() {
+			(*unlockFn)(err)
+		}()
+	}
+
+	diffsByMessageId, err := db.GetConvoMessageDiffs(auth.OrgId, planId)
+
+	if err != nil {
+		log.Println("Error getting convo diffs: ", err)
+		http.Error(w, "Error getting convo diffs: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	bytes, err := json.Marshal(diffsByMessageId)
+
+	if err != nil {
+		log.Println("Error marshalling convo diffs: ", err)
+		http.Error(w, "Error marshalling convo diffs: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Println("Successfully processed request for ListConvoDiffsHandler")
+	w.Write(bytes)
+}
+
 func GetPlanStatusHandler(w http.ResponseWriter, r *http.Request) {
 	log.Println("Received a request for GetPlanStatusHandler")
 
@@ -185,7 +185,7 @@ func RenamePlanHandler(w http.ResponseWriter, r *http.Request) {
 
 	log.Println("planId: ", planId)
 
-	plan := authorizePlan(w, planId, auth)
+	plan := authorizePlanRename(w, planId, auth)
 
 	if plan == nil {
 		return
@@ -337,7 +337,7 @@ func ListPlansHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	plans, err := db.ListOwnedPlans(authorizedProjectIds, auth.User.Id, false)
+	plans, err := db.ListAccessiblePlans(authorizedProjectIds, auth.User.Id, false)
 
 	if err != nil {
 		log.Printf("Error listing plans: %v\n", err)
@@ -384,7 +384,7 @@ func ListArchivedPlansHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	plans, err := db.ListOwnedPlans(projectIds, auth.User.Id, true)
+	plans, err := db.ListAccessiblePlans(projectIds, auth.User.Id, true)
 
 	if err != nil {
 		log.Printf("Error listing plans: %v\n", err)
@@ -433,7 +433,7 @@ func ListPlansRunningHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	plans, err := db.ListOwnedPlans(projectIds, auth.User.Id, false)
+	plans, err := db.ListAccessiblePlans(projectIds, auth.User.Id, false)
 
 	if err != nil {
 		log.Printf("Error listing plans: %v\n", err)
@@ -590,7 +590,7 @@ func GetCurrentBranchByPlanIdHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	plans, err := db.ListOwnedPlans([]string{projectId}, auth.User.Id, false)
+	plans, err := db.ListAccessiblePlans([]string{projectId}, auth.User.Id, false)
 
 	if err != nil {
 		log.Printf("Error listing plans: %v\n", err)
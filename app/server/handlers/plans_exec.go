@@ -33,7 +33,7 @@ func TellPlanHandler(w http.ResponseWriter, r *http.Request) {
 
 	log.Println("planId: ", planId)
 
-	plan := authorizePlanExecUpdate(w, planId, auth)
+	plan := authorizePlanUpdate(w, planId, auth)
 	if plan == nil {
 		return
 	}
@@ -124,7 +124,7 @@ func BuildPlanHandler(w http.ResponseWriter, r *http.Request) {
 	branch := vars["branch"]
 
 	log.Println("planId: ", planId)
-	plan := authorizePlanExecUpdate(w, planId, auth)
+	plan := authorizePlanUpdate(w, planId, auth)
 	if plan == nil {
 		return
 	}
@@ -164,7 +164,7 @@ func BuildPlanHandler(w http.ResponseWriter, r *http.Request) {
 			plan:        plan,
 		},
 	)
-	numBuilds, err := modelPlan.Build(clients, plan, branch, auth)
+	numBuilds, err := modelPlan.Build(clients, plan, branch, auth, requestBody.ConfigSchemas, requestBody.ProjectInstructions)
 
 	if err != nil {
 		log.Printf("Error building plan: %v\n", err)
@@ -252,7 +252,7 @@ func StopPlanHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if authorizePlan(w, planId, auth) == nil {
+	if authorizePlanUpdate(w, planId, auth) == nil {
 		return
 	}
 
@@ -326,7 +326,7 @@ func RespondMissingFileHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	plan := authorizePlan(w, planId, auth)
+	plan := authorizePlanUpdate(w, planId, auth)
 	if plan == nil {
 		return
 	}
@@ -379,18 +379,3 @@ func RespondMissingFileHandler(w http.ResponseWriter, r *http.Request) {
 
 	log.Println("Successfully processed request for RespondMissingFileHandler")
 }
-
-func authorizePlanExecUpdate(w http.ResponseWriter, planId string, auth *types.ServerAuth) *db.Plan {
-	plan := authorizePlan(w, planId, auth)
-	if plan == nil {
-		return nil
-	}
-
-	if plan.OwnerId != auth.User.Id && !auth.HasPermission(types.PermissionUpdateAnyPlan) {
-		log.Println("User does not have permission to update plan")
-		http.Error(w, "User does not have permission to update plan", http.StatusForbidden)
-		return nil
-	}
-
-	return plan
-}
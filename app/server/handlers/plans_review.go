@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"plandex-server/db"
+	modelPlan "plandex-server/model/plan"
+
+	"github.com/gorilla/mux"
+	"github.com/plandex/plandex/shared"
+)
+
+func ReviewPlanHandler(w http.ResponseWriter, r *http.Request) {
+	log.Println("Received request for ReviewPlanHandler")
+
+	auth := authenticate(w, r, true)
+	if auth == nil {
+		return
+	}
+
+	vars := mux.Vars(r)
+	planId := vars["planId"]
+	branch := vars["branch"]
+	log.Println("planId: ", planId, "branch: ", branch)
+
+	// the review is committed to the plan's git repo, so it needs write access
+	plan := authorizePlanUpdate(w, planId, auth)
+	if plan == nil {
+		return
+	}
+
+	var err error
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("Error reading request body: %v\n", err)
+		http.Error(w, "Error reading request body", http.StatusInternalServerError)
+		return
+	}
+	defer r.Body.Close()
+
+	var requestBody shared.ReviewPlanRequest
+	if err := json.Unmarshal(body, &requestBody); err != nil {
+		log.Printf("Error parsing request body: %v\n", err)
+		http.Error(w, "Error parsing request body", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	unlockFn := lockRepo(w, r, auth, db.LockScopeWrite, ctx, cancel, true)
+	if unlockFn == nil {
+		return
+	} else {
+		defer func() {
+			(*unlockFn)(err)
+		}()
+	}
+
+	settings, err := db.GetPlanSettings(plan, true)
+	if err != nil {
+		log.Printf("Error getting plan settings: %v\n", err)
+		http.Error(w, "Error getting plan settings: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	planState, err := db.GetCurrentPlanState(db.CurrentPlanStateParams{
+		OrgId:  auth.OrgId,
+		PlanId: planId,
+	})
+
+	if err != nil {
+		log.Printf("Error getting current plan state: %v\n", err)
+		http.Error(w, "Error getting current plan state: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if len(planState.PlanResult.SortedPaths) == 0 {
+		log.Println("No pending changes to review")
+		http.Error(w, "No pending changes to review", http.StatusBadRequest)
+		return
+	}
+
+	clients := initClients(
+		initClientsParams{
+			w:           w,
+			apiKeys:     requestBody.ApiKeys,
+			openAIBase:  requestBody.OpenAIBase,
+			openAIOrgId: requestBody.OpenAIOrgId,
+			plan:        plan,
+		},
+	)
+
+	config := settings.ModelPack.GetReviewer()
+	client := clients[config.BaseModelConfig.ApiKeyEnvVar]
+
+	if client == nil {
+		log.Printf("No client for reviewer api key env var %s\n", config.BaseModelConfig.ApiKeyEnvVar)
+		http.Error(w, fmt.Sprintf("No api key set for the reviewer model (%s)", config.BaseModelConfig.ApiKeyEnvVar), http.StatusBadRequest)
+		return
+	}
+
+	review, err := modelPlan.ReviewPendingChanges(client, config, auth.OrgId, planId, planState, requestBody.ProjectInstructions, r.Context())
+
+	if err != nil {
+		log.Printf("Error reviewing pending changes: %v\n", err)
+		http.Error(w, "Error reviewing pending changes: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = db.StorePlanReview(auth.OrgId, planId, review)
+
+	if err != nil {
+		log.Printf("Error storing review: %v\n", err)
+		http.Error(w, "Error storing review: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = db.GitAddAndCommit(auth.OrgId, planId, branch, fmt.Sprintf("🔎 Reviewed pending changes | %d findings", len(review.Findings)))
+
+	if err != nil {
+		log.Printf("Error committing review: %v\n", err)
+		http.Error(w, "Error committing review: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	bytes, err := json.Marshal(review)
+
+	if err != nil {
+		log.Printf("Error marshalling review: %v\n", err)
+		http.Error(w, "Error marshalling review: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Write(bytes)
+
+	log.Println("Successfully reviewed plan", planId)
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"log"
 	"net/http"
@@ -81,7 +82,7 @@ func RewindPlanHandler(w http.ResponseWriter, r *http.Request) {
 
 	log.Println("planId: ", planId)
 
-	if authorizePlan(w, planId, auth) == nil {
+	if authorizePlanUpdate(w, planId, auth) == nil {
 		return
 	}
 
@@ -111,7 +112,43 @@ func RewindPlanHandler(w http.ResponseWriter, r *http.Request) {
 		}()
 	}
 
-	err = db.GitRewindToSha(auth.OrgId, planId, branch, requestBody.Sha)
+	targetSha := requestBody.Sha
+	if requestBody.Tag != "" {
+		targetSha, err = db.ResolvePlanTag(auth.OrgId, planId, requestBody.Tag)
+
+		if err != nil {
+			log.Println("Error resolving tag: ", err)
+			http.Error(w, "Error resolving tag: "+err.Error(), http.StatusNotFound)
+			return
+		}
+	} else if requestBody.BeforeMessageId != "" {
+		targetSha, err = db.GitShaBeforeConvoMessage(auth.OrgId, planId, "HEAD", requestBody.BeforeMessageId)
+
+		if err != nil {
+			log.Println("Error getting sha before convo message: ", err)
+			http.Error(w, "Error getting sha before convo message: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	// a sha from another branch would replace this branch's history
+	var onBranch bool
+	onBranch, err = db.GitIsAncestorOfHead(auth.OrgId, planId, targetSha)
+
+	if err != nil {
+		log.Println("Error checking target sha: ", err)
+		http.Error(w, "Error checking target sha: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !onBranch {
+		msg := fmt.Sprintf("%s isn't in the history of branch %s", targetSha, branch)
+		log.Println(msg)
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	err = db.GitRewindToSha(auth.OrgId, planId, branch, targetSha)
 
 	if err != nil {
 		log.Println("Error rewinding plan: ", err)
@@ -152,3 +189,200 @@ func RewindPlanHandler(w http.ResponseWriter, r *http.Request) {
 
 	log.Println("Successfully processed request for RewindPlanHandler")
 }
+
+func ListPlanTagsHandler(w http.ResponseWriter, r *http.Request) {
+	log.Println("Received request for ListPlanTagsHandler")
+
+	auth := authenticate(w, r, true)
+	if auth == nil {
+		return
+	}
+
+	vars := mux.Vars(r)
+	planId := vars["planId"]
+	branch := vars["branch"]
+
+	log.Println("planId: ", planId, "branch: ", branch)
+
+	if authorizePlan(w, planId, auth) == nil {
+		return
+	}
+
+	var err error
+	ctx, cancel := context.WithCancel(context.Background())
+	unlockFn := lockRepo(w, r, auth, db.LockScopeRead, ctx, cancel, true)
+	if unlockFn == nil {
+		return
+	} else {
+		defer func() {
+			(*unlockFn)(err)
+		}()
+	}
+
+	tags, err := db.ListPlanTags(auth.OrgId, planId)
+
+	if err != nil {
+		log.Println("Error listing tags: ", err)
+		http.Error(w, "Error listing tags: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	bytes, err := json.Marshal(tags)
+
+	if err != nil {
+		log.Println("Error marshalling tags: ", err)
+		http.Error(w, "Error marshalling tags: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Write(bytes)
+
+	log.Println("Successfully processed request for ListPlanTagsHandler")
+}
+
+func CreatePlanTagHandler(w http.ResponseWriter, r *http.Request) {
+	log.Println("Received request for CreatePlanTagHandler")
+
+	auth := authenticate(w, r, true)
+	if auth == nil {
+		return
+	}
+
+	vars := mux.Vars(r)
+	planId := vars["planId"]
+	branch := vars["branch"]
+
+	log.Println("planId: ", planId, "branch: ", branch)
+
+	if authorizePlanUpdate(w, planId, auth) == nil {
+		return
+	}
+
+	var req shared.CreatePlanTagRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		log.Printf("Error decoding request: %v\n", err)
+		http.Error(w, "Error decoding request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = db.ValidatePlanTagName(req.Name)
+
+	if err != nil {
+		log.Println("Invalid tag name: ", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	unlockFn := lockRepo(w, r, auth, db.LockScopeWrite, ctx, cancel, true)
+	if unlockFn == nil {
+		return
+	} else {
+		defer func() {
+			(*unlockFn)(err)
+		}()
+	}
+
+	existing, err := db.GetPlanTag(auth.OrgId, planId, req.Name)
+
+	if err != nil {
+		log.Println("Error getting tag: ", err)
+		http.Error(w, "Error getting tag: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if existing != nil {
+		log.Printf("Tag %s already exists\n", req.Name)
+		http.Error(w, fmt.Sprintf("Tag %s already exists at %s", req.Name, existing.Sha), http.StatusConflict)
+		return
+	}
+
+	tag, err := db.CreatePlanTag(auth.OrgId, planId, req.Name, req.Message)
+
+	if err != nil {
+		log.Println("Error creating tag: ", err)
+		http.Error(w, "Error creating tag: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	bytes, err := json.Marshal(tag)
+
+	if err != nil {
+		log.Println("Error marshalling tag: ", err)
+		http.Error(w, "Error marshalling tag: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Write(bytes)
+
+	log.Println("Successfully processed request for CreatePlanTagHandler")
+}
+
+func DeletePlanTagHandler(w http.ResponseWriter, r *http.Request) {
+	log.Println("Received request for DeletePlanTagHandler")
+
+	auth := authenticate(w, r, true)
+	if auth == nil {
+		return
+	}
+
+	vars := mux.Vars(r)
+	planId := vars["planId"]
+	branch := vars["branch"]
+	tagName := vars["tag"]
+
+	log.Println("planId: ", planId, "branch: ", branch, "tag: ", tagName)
+
+	if authorizePlanUpdate(w, planId, auth) == nil {
+		return
+	}
+
+	var req shared.DeletePlanTagRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		log.Printf("Error decoding request: %v\n", err)
+		http.Error(w, "Error decoding request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !req.Force {
+		log.Printf("Tag %s is protected\n", tagName)
+		http.Error(w, fmt.Sprintf("Tag %s is protected -- deleting it must be forced", tagName), http.StatusForbidden)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	unlockFn := lockRepo(w, r, auth, db.LockScopeWrite, ctx, cancel, true)
+	if unlockFn == nil {
+		return
+	} else {
+		defer func() {
+			(*unlockFn)(err)
+		}()
+	}
+
+	tag, err := db.GetPlanTag(auth.OrgId, planId, tagName)
+
+	if err != nil {
+		log.Println("Error getting tag: ", err)
+		http.Error(w, "Error getting tag: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if tag == nil {
+		log.Printf("Tag %s not found\n", tagName)
+		http.Error(w, "Tag not found: "+tagName, http.StatusNotFound)
+		return
+	}
+
+	err = db.DeletePlanTag(auth.OrgId, planId, tagName)
+
+	if err != nil {
+		log.Println("Error deleting tag: ", err)
+		http.Error(w, "Error deleting tag: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Println("Successfully processed request for DeletePlanTagHandler")
+}
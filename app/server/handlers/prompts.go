@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"plandex-server/db"
+	"plandex-server/model"
+	"plandex-server/model/prompts"
+
+	"github.com/gorilla/mux"
+	"github.com/plandex/plandex/shared"
+)
+
+// Prompt routes are registered both at the org level and under /plans/{planId}. When there's a planId, overrides apply to that plan only.
+
+func ListPromptsHandler(w http.ResponseWriter, r *http.Request) {
+	log.Println("Received request for ListPromptsHandler")
+
+	auth := authenticate(w, r, true)
+	if auth == nil {
+		return
+	}
+
+	planId := mux.Vars(r)["planId"]
+
+	if planId != "" && authorizePlan(w, planId, auth) == nil {
+		return
+	}
+
+	res, err := model.ListPrompts(auth.OrgId, planId)
+
+	if err != nil {
+		log.Println("Error listing prompts: ", err)
+		http.Error(w, "Error listing prompts", http.StatusInternalServerError)
+		return
+	}
+
+	bytes, err := json.Marshal(res)
+
+	if err != nil {
+		log.Println("Error marshalling prompts: ", err)
+		http.Error(w, "Error marshalling prompts", http.StatusInternalServerError)
+		return
+	}
+
+	log.Println("ListPromptsHandler processed successfully")
+
+	w.Write(bytes)
+}
+
+func GetPromptVersionHandler(w http.ResponseWriter, r *http.Request) {
+	log.Println("Received request for GetPromptVersionHandler")
+
+	auth := authenticate(w, r, true)
+	if auth == nil {
+		return
+	}
+
+	version := mux.Vars(r)["version"]
+	name := shared.PromptName(r.URL.Query().Get("name"))
+
+	res, err := model.GetPromptByVersion(auth.OrgId, name, version)
+
+	if err != nil {
+		log.Println("Error getting prompt version: ", err)
+		http.Error(w, "Error getting prompt version", http.StatusInternalServerError)
+		return
+	}
+
+	if res == nil {
+		log.Println("Prompt version not found: ", version)
+		http.Error(w, "Prompt version not found", http.StatusNotFound)
+		return
+	}
+
+	bytes, err := json.Marshal(res)
+
+	if err != nil {
+		log.Println("Error marshalling prompt: ", err)
+		http.Error(w, "Error marshalling prompt", http.StatusInternalServerError)
+		return
+	}
+
+	log.Println("GetPromptVersionHandler processed successfully")
+
+	w.Write(bytes)
+}
+
+func UpdatePromptHandler(w http.ResponseWriter, r *http.Request) {
+	log.Println("Received request for UpdatePromptHandler")
+
+	auth := authenticate(w, r, true)
+	if auth == nil {
+		return
+	}
+
+	vars := mux.Vars(r)
+	planId := vars["planId"]
+	name := vars["name"]
+
+	if !shared.IsValidPromptName(name) {
+		log.Println("Invalid prompt name: ", name)
+		http.Error(w, "Invalid prompt name", http.StatusBadRequest)
+		return
+	}
+
+	if planId != "" && authorizePlanUpdate(w, planId, auth) == nil {
+		return
+	}
+
+	var req shared.UpdatePromptTemplateRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+
+	if err != nil {
+		log.Println("Error decoding request body: ", err)
+		http.Error(w, "Error decoding request body", http.StatusInternalServerError)
+		return
+	}
+
+	_, err = prompts.RenderTemplate(shared.PromptName(name), req.Template)
+
+	if err != nil {
+		log.Println("Invalid prompt template: ", err)
+		http.Error(w, "Invalid prompt template: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tmpl, err := db.StorePromptTemplate(auth.OrgId, planId, shared.PromptName(name), req.Template)
+
+	if err != nil {
+		log.Println("Error storing prompt template: ", err)
+		http.Error(w, "Error storing prompt template", http.StatusInternalServerError)
+		return
+	}
+
+	bytes, err := json.Marshal(tmpl.ToApi())
+
+	if err != nil {
+		log.Println("Error marshalling prompt: ", err)
+		http.Error(w, "Error marshalling prompt", http.StatusInternalServerError)
+		return
+	}
+
+	log.Println("UpdatePromptHandler processed successfully")
+
+	w.Write(bytes)
+}
+
+func DeletePromptHandler(w http.ResponseWriter, r *http.Request) {
+	log.Println("Received request for DeletePromptHandler")
+
+	auth := authenticate(w, r, true)
+	if auth == nil {
+		return
+	}
+
+	vars := mux.Vars(r)
+	planId := vars["planId"]
+	name := vars["name"]
+
+	if !shared.IsValidPromptName(name) {
+		log.Println("Invalid prompt name: ", name)
+		http.Error(w, "Invalid prompt name", http.StatusBadRequest)
+		return
+	}
+
+	if planId != "" && authorizePlanUpdate(w, planId, auth) == nil {
+		return
+	}
+
+	err := db.DeletePromptTemplate(auth.OrgId, planId, shared.PromptName(name))
+
+	if err != nil {
+		log.Println("Error deleting prompt template: ", err)
+		http.Error(w, "Error deleting prompt template", http.StatusInternalServerError)
+		return
+	}
+
+	log.Println("DeletePromptHandler processed successfully")
+}
@@ -0,0 +1,350 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"plandex-server/db"
+	"plandex-server/types"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/plandex/plandex/shared"
+)
+
+func RequestReviewHandler(w http.ResponseWriter, r *http.Request) {
+	log.Println("Received request for RequestReviewHandler")
+
+	auth := authenticate(w, r, true)
+	if auth == nil {
+		return
+	}
+
+	vars := mux.Vars(r)
+	planId := vars["planId"]
+	branch := vars["branch"]
+	log.Println("planId: ", planId, "branch: ", branch)
+
+	plan := authorizePlanUpdate(w, planId, auth)
+	if plan == nil {
+		return
+	}
+
+	var req shared.RequestReviewRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		log.Printf("Error decoding request: %v\n", err)
+		http.Error(w, "Error decoding request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if len(req.Reviewers) == 0 {
+		log.Println("No reviewers in request")
+		http.Error(w, "At least one reviewer is required", http.StatusBadRequest)
+		return
+	}
+
+	reviewers := resolveReviewers(w, req.Reviewers, plan, auth)
+	if reviewers == nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	unlockFn := lockRepo(w, r, auth, db.LockScopeWrite, ctx, cancel, true)
+	if unlockFn == nil {
+		return
+	} else {
+		defer func() {
+			(*unlockFn)(err)
+		}()
+	}
+
+	planState, err := db.GetCurrentPlanState(db.CurrentPlanStateParams{
+		OrgId:  auth.OrgId,
+		PlanId: planId,
+	})
+
+	if err != nil {
+		log.Printf("Error getting current plan state: %v\n", err)
+		http.Error(w, "Error getting current plan state: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if len(planState.PlanResult.PendingResultIds()) == 0 {
+		log.Println("No pending changes to review")
+		http.Error(w, "There are no pending changes to review", http.StatusBadRequest)
+		return
+	}
+
+	reviewState, err := db.GetPlanReviewState(auth.OrgId, planId, branch)
+
+	if err != nil {
+		log.Printf("Error getting review state: %v\n", err)
+		http.Error(w, "Error getting review state: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var added []string
+	for _, reviewer := range reviewers {
+		if reviewState.RequestForReviewer(reviewer.Id) != nil {
+			continue
+		}
+
+		request := &shared.ReviewRequest{
+			Id:              uuid.New().String(),
+			ReviewerId:      reviewer.Id,
+			ReviewerName:    reviewer.Name,
+			ReviewerEmail:   reviewer.Email,
+			RequestedById:   auth.User.Id,
+			RequestedByName: auth.User.Name,
+			CreatedAt:       time.Now(),
+		}
+
+		err = db.AddPlanReviewRequest(auth.OrgId, planId, branch, request)
+
+		if err != nil {
+			log.Printf("Error storing review request: %v\n", err)
+			http.Error(w, "Error storing review request: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		reviewState.Requests = append(reviewState.Requests, request)
+		added = append(added, reviewer.Email)
+	}
+
+	if len(added) > 0 {
+		err = db.GitAddAndCommitAllowEmpty(auth.OrgId, planId, branch, fmt.Sprintf("👀 %s requested review from %s", auth.User.Name, strings.Join(added, ", ")))
+
+		if err != nil {
+			log.Printf("Error committing review request: %v\n", err)
+			http.Error(w, "Error committing review request: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	bytes, err := json.Marshal(reviewState)
+
+	if err != nil {
+		log.Printf("Error marshalling review state: %v\n", err)
+		http.Error(w, "Error marshalling review state: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Write(bytes)
+
+	log.Println("Successfully requested review for plan", planId)
+}
+
+func ReviewFeedbackHandler(w http.ResponseWriter, r *http.Request) {
+	log.Println("Received request for ReviewFeedbackHandler")
+
+	auth := authenticate(w, r, true)
+	if auth == nil {
+		return
+	}
+
+	vars := mux.Vars(r)
+	planId := vars["planId"]
+	branch := vars["branch"]
+	log.Println("planId: ", planId, "branch: ", branch)
+
+	// reviewers may only have read access to the plan
+	if authorizePlan(w, planId, auth) == nil {
+		return
+	}
+
+	var req shared.ReviewFeedbackRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		log.Printf("Error decoding request: %v\n", err)
+		http.Error(w, "Error decoding request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	req.Comment = strings.TrimSpace(req.Comment)
+	if !req.Approve && req.Comment == "" {
+		log.Println("Feedback has no approval or comment")
+		http.Error(w, "Feedback must approve or include a comment", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	unlockFn := lockRepo(w, r, auth, db.LockScopeWrite, ctx, cancel, true)
+	if unlockFn == nil {
+		return
+	} else {
+		defer func() {
+			(*unlockFn)(err)
+		}()
+	}
+
+	reviewState, err := db.GetPlanReviewState(auth.OrgId, planId, branch)
+
+	if err != nil {
+		log.Printf("Error getting review state: %v\n", err)
+		http.Error(w, "Error getting review state: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if req.Approve && reviewState.RequestForReviewer(auth.User.Id) == nil {
+		log.Println("User wasn't asked to review plan")
+		http.Error(w, "You haven't been asked to review this plan", http.StatusForbidden)
+		return
+	}
+
+	planState, err := db.GetCurrentPlanState(db.CurrentPlanStateParams{
+		OrgId:  auth.OrgId,
+		PlanId: planId,
+	})
+
+	if err != nil {
+		log.Printf("Error getting current plan state: %v\n", err)
+		http.Error(w, "Error getting current plan state: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	resultIds := planState.PlanResult.PendingResultIdsForPath(req.Path)
+	if len(resultIds) == 0 {
+		log.Printf("No pending changes for path %s\n", req.Path)
+		http.Error(w, "No pending changes for "+req.Path, http.StatusNotFound)
+		return
+	}
+
+	if req.ReplacementId != "" {
+		var found bool
+		for _, rep := range planState.PlanResult.ReplacementsByPath[req.Path] {
+			if rep.Id == req.ReplacementId && rep.IsPending() {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			log.Printf("Replacement %s not pending for path %s\n", req.ReplacementId, req.Path)
+			http.Error(w, "Change isn't pending for "+req.Path, http.StatusNotFound)
+			return
+		}
+	}
+
+	feedback := &shared.ReviewFeedback{
+		Id:            uuid.New().String(),
+		UserId:        auth.User.Id,
+		UserName:      auth.User.Name,
+		Path:          req.Path,
+		ReplacementId: req.ReplacementId,
+		ResultIds:     resultIds,
+		Approved:      req.Approve,
+		Comment:       req.Comment,
+		CreatedAt:     time.Now(),
+	}
+	err = db.AddPlanReviewFeedback(auth.OrgId, planId, branch, feedback)
+
+	if err != nil {
+		log.Printf("Error storing review feedback: %v\n", err)
+		http.Error(w, "Error storing review feedback: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	reviewState.Feedback = append(reviewState.Feedback, feedback)
+
+	target := "file"
+	if req.ReplacementId != "" {
+		target = "change to"
+	}
+
+	var msg string
+	if req.Approve {
+		msg = fmt.Sprintf("✅ %s approved %s %s", auth.User.Name, target, req.Path)
+	} else {
+		msg = fmt.Sprintf("💬 %s commented on %s %s", auth.User.Name, target, req.Path)
+	}
+	if req.Comment != "" {
+		msg += "\n\n" + req.Comment
+	}
+
+	err = db.GitAddAndCommitAllowEmpty(auth.OrgId, planId, branch, msg)
+
+	if err != nil {
+		log.Printf("Error committing review feedback: %v\n", err)
+		http.Error(w, "Error committing review feedback: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	bytes, err := json.Marshal(reviewState)
+
+	if err != nil {
+		log.Printf("Error marshalling review state: %v\n", err)
+		http.Error(w, "Error marshalling review state: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Write(bytes)
+
+	log.Println("Successfully stored review feedback for plan", planId)
+}
+
+// resolveReviewers matches each reviewer to an org member with access to the plan by email, name, or the local part of their email
+func resolveReviewers(w http.ResponseWriter, reviewers []string, plan *db.Plan, auth *types.ServerAuth) []*db.User {
+	users, err := db.ListUsers(auth.OrgId)
+
+	if err != nil {
+		log.Println("Error listing users: ", err)
+		http.Error(w, "Error listing users", http.StatusInternalServerError)
+		return nil
+	}
+
+	var res []*db.User
+	for _, reviewer := range reviewers {
+		reviewer = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(reviewer), "@"))
+
+		var matches []*db.User
+		for _, user := range users {
+			email := strings.ToLower(user.Email)
+			localPart := strings.SplitN(email, "@", 2)[0]
+
+			if email == reviewer || strings.ToLower(user.Name) == reviewer || localPart == reviewer {
+				matches = append(matches, user)
+			}
+		}
+
+		if len(matches) == 0 {
+			log.Println("Reviewer isn't a member of the org: ", reviewer)
+			http.Error(w, reviewer+" isn't a member of the org", http.StatusNotFound)
+			return nil
+		} else if len(matches) > 1 {
+			log.Println("Reviewer is ambiguous: ", reviewer)
+			http.Error(w, fmt.Sprintf("%s matches more than one member of the org -- use their email instead", reviewer), http.StatusBadRequest)
+			return nil
+		}
+
+		user := matches[0]
+
+		if user.Id == auth.User.Id {
+			log.Println("User requested review from themselves")
+			http.Error(w, "You can't request a review from yourself", http.StatusBadRequest)
+			return nil
+		}
+
+		_, access, err := db.GetPlanAccess(plan.Id, user.Id, auth.OrgId)
+
+		if err != nil {
+			log.Println("Error getting plan access: ", err)
+			http.Error(w, "Error getting plan access", http.StatusInternalServerError)
+			return nil
+		}
+
+		if access == "" {
+			log.Println("Reviewer doesn't have access to plan: ", user.Email)
+			http.Error(w, user.Email+" doesn't have access to this plan -- share it with them first", http.StatusForbidden)
+			return nil
+		}
+
+		res = append(res, user)
+	}
+
+	return res
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"plandex-server/db"
+	"strings"
+
+	"github.com/plandex/plandex/shared"
+)
+
+func SearchPlansHandler(w http.ResponseWriter, r *http.Request) {
+	log.Println("Received request for SearchPlansHandler")
+
+	auth := authenticate(w, r, true)
+	if auth == nil {
+		return
+	}
+
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+
+	if query == "" {
+		log.Println("No search query provided")
+		http.Error(w, "No search query provided", http.StatusBadRequest)
+		return
+	}
+
+	projectIds := r.URL.Query()["projectId"]
+
+	if len(projectIds) == 0 {
+		log.Println("No project ids provided")
+		http.Error(w, "No project ids provided", http.StatusBadRequest)
+		return
+	}
+
+	authorizedProjectIds := []string{}
+	for _, projectId := range projectIds {
+		if authorizeProjectOptional(w, projectId, auth, false) {
+			authorizedProjectIds = append(authorizedProjectIds, projectId)
+		}
+	}
+
+	if len(authorizedProjectIds) == 0 {
+		log.Println("No authorized project ids provided")
+		http.Error(w, "No authorized project ids provided", http.StatusForbidden)
+		return
+	}
+
+	plans, err := db.ListAccessiblePlans(authorizedProjectIds, auth.User.Id, false)
+
+	if err != nil {
+		log.Printf("Error listing plans: %v\n", err)
+		http.Error(w, "Error listing plans: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	res := shared.SearchPlansResponse{Results: []*shared.PlanSearchResult{}}
+
+	if len(plans) > 0 {
+		results, err := db.SearchPlans(plans, query)
+
+		if err != nil {
+			log.Printf("Error searching plans: %v\n", err)
+			http.Error(w, "Error searching plans: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if results != nil {
+			res.Results = results
+		}
+	}
+
+	bytes, err := json.Marshal(res)
+
+	if err != nil {
+		log.Printf("Error marshalling search results: %v\n", err)
+		http.Error(w, "Error marshalling search results: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Successfully searched plans: %d results\n", len(res.Results))
+
+	w.Write(bytes)
+}
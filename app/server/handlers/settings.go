@@ -78,7 +78,7 @@ func UpdateSettingsHandler(w http.ResponseWriter, r *http.Request) {
 
 	log.Println("planId: ", planId, "branch: ", branch)
 
-	plan := authorizePlan(w, planId, auth)
+	plan := authorizePlanUpdate(w, planId, auth)
 
 	if plan == nil {
 		return
@@ -329,6 +329,18 @@ func compareAny(a, b interface{}, path string, changes *[]string) {
 		return // No difference found
 	}
 
+	// instructions can be long, so only say how they changed
+	if path == "instructions" {
+		change := "instructions | updated"
+		if aVal.String() == "" {
+			change = "instructions | set"
+		} else if bVal.String() == "" {
+			change = "instructions | cleared"
+		}
+		*changes = append(*changes, change)
+		return
+	}
+
 	switch aVal.Kind() {
 	case reflect.Struct:
 		for i := 0; i < aVal.NumField(); i++ {
@@ -32,6 +32,8 @@ func main() {
 		log.Fatal("Error running migrations: ", err)
 	}
 
+	db.StartSearchIndexWorker()
+
 	if os.Getenv("GOENV") == "development" {
 		log.Println("In development mode.")
 	}
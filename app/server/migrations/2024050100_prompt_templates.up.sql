@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS prompt_templates (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  org_id UUID NOT NULL REFERENCES orgs(id) ON DELETE CASCADE,
+  plan_id UUID REFERENCES plans(id) ON DELETE CASCADE,
+  name VARCHAR(64) NOT NULL,
+  template TEXT NOT NULL,
+  version VARCHAR(64) NOT NULL,
+
+  updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+CREATE TRIGGER update_prompt_templates_modtime BEFORE UPDATE ON prompt_templates FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- org-wide overrides have no plan id
+CREATE UNIQUE INDEX prompt_templates_org_name_idx ON prompt_templates(org_id, name) WHERE plan_id IS NULL;
+CREATE UNIQUE INDEX prompt_templates_plan_name_idx ON prompt_templates(plan_id, name) WHERE plan_id IS NOT NULL;
+
+-- every template text that has been set, so a version recorded on a convo message can always be looked up
+CREATE TABLE IF NOT EXISTS prompt_template_versions (
+  org_id UUID NOT NULL REFERENCES orgs(id) ON DELETE CASCADE,
+  version VARCHAR(64) NOT NULL,
+  name VARCHAR(64) NOT NULL,
+  template TEXT NOT NULL,
+
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+  PRIMARY KEY (org_id, version)
+);
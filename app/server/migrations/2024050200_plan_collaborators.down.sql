@@ -0,0 +1,3 @@
+ALTER TABLE plans DROP COLUMN IF EXISTS shared_with_org_access;
+
+DROP TABLE IF EXISTS plan_collaborators;
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS plan_collaborators (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  org_id UUID NOT NULL REFERENCES orgs(id) ON DELETE CASCADE,
+  plan_id UUID NOT NULL REFERENCES plans(id) ON DELETE CASCADE,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  access VARCHAR(16) NOT NULL,
+
+  updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+CREATE TRIGGER update_plan_collaborators_modtime BEFORE UPDATE ON plan_collaborators FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE UNIQUE INDEX plan_collaborators_plan_user_idx ON plan_collaborators(plan_id, user_id);
+CREATE INDEX plan_collaborators_user_idx ON plan_collaborators(user_id);
+
+ALTER TABLE plans ADD COLUMN shared_with_org_access VARCHAR(16);
+UPDATE plans SET shared_with_org_access = 'read' WHERE shared_with_org_at IS NOT NULL;
@@ -0,0 +1,13 @@
+INSERT INTO permissions (name, description) VALUES
+  ('apply_without_review', 'Apply a plan''s pending changes before requested reviews are approved');
+
+INSERT INTO org_roles_permissions (org_role_id, permission_id)
+SELECT 
+    r.id AS org_role_id, 
+    p.id AS permission_id
+FROM 
+    org_roles r, permissions p
+WHERE 
+    r.org_id IS NULL
+    AND r.name IN ('owner', 'admin')
+    AND p.name = 'apply_without_review';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS plans_name_search_idx;
+
+DROP TABLE IF EXISTS plan_search_branches;
+DROP TABLE IF EXISTS plan_search_entries;
@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS plan_search_entries (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  org_id UUID NOT NULL REFERENCES orgs(id) ON DELETE CASCADE,
+  plan_id UUID NOT NULL REFERENCES plans(id) ON DELETE CASCADE,
+  branch_id UUID NOT NULL REFERENCES branches(id) ON DELETE CASCADE,
+  kind VARCHAR(32) NOT NULL,
+  convo_message_id UUID NOT NULL,
+  message_num INTEGER NOT NULL,
+  role VARCHAR(32) NOT NULL,
+  body TEXT NOT NULL,
+  search_vector tsvector GENERATED ALWAYS AS (to_tsvector('english', body)) STORED,
+
+  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX plan_search_entries_vector_idx ON plan_search_entries USING GIN (search_vector);
+CREATE INDEX plan_search_entries_branch_idx ON plan_search_entries(branch_id);
+CREATE INDEX plan_search_entries_plan_idx ON plan_search_entries(plan_id);
+
+-- the commit each branch's entries were indexed from, so branches are only reindexed when they have new commits
+CREATE TABLE IF NOT EXISTS plan_search_branches (
+  branch_id UUID PRIMARY KEY REFERENCES branches(id) ON DELETE CASCADE,
+  indexed_sha VARCHAR(64) NOT NULL,
+
+  updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX plans_name_search_idx ON plans USING GIN (to_tsvector('english', name));
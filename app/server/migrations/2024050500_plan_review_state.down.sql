@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS plan_review_feedback;
+DROP TABLE IF EXISTS plan_review_requests;
@@ -0,0 +1,34 @@
+-- review state is kept out of the plan's git repo so rewinding or merging can't drop open review requests
+CREATE TABLE IF NOT EXISTS plan_review_requests (
+  id UUID PRIMARY KEY,
+  org_id UUID NOT NULL REFERENCES orgs(id) ON DELETE CASCADE,
+  plan_id UUID NOT NULL REFERENCES plans(id) ON DELETE CASCADE,
+  branch VARCHAR(255) NOT NULL,
+  reviewer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  reviewer_name VARCHAR(255) NOT NULL,
+  reviewer_email VARCHAR(255) NOT NULL,
+  requested_by_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  requested_by_name VARCHAR(255) NOT NULL,
+
+  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX plan_review_requests_reviewer_idx ON plan_review_requests(plan_id, branch, reviewer_id);
+
+CREATE TABLE IF NOT EXISTS plan_review_feedback (
+  id UUID PRIMARY KEY,
+  org_id UUID NOT NULL REFERENCES orgs(id) ON DELETE CASCADE,
+  plan_id UUID NOT NULL REFERENCES plans(id) ON DELETE CASCADE,
+  branch VARCHAR(255) NOT NULL,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  user_name VARCHAR(255) NOT NULL,
+  path TEXT NOT NULL,
+  replacement_id VARCHAR(255) NOT NULL DEFAULT '',
+  result_ids TEXT[] NOT NULL,
+  approved BOOLEAN NOT NULL,
+  comment TEXT NOT NULL DEFAULT '',
+
+  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX plan_review_feedback_branch_idx ON plan_review_feedback(plan_id, branch);
@@ -0,0 +1,6 @@
+-- keep the earliest name for each version
+DELETE FROM prompt_template_versions a USING prompt_template_versions b
+WHERE a.org_id = b.org_id AND a.version = b.version AND (a.created_at, a.name) > (b.created_at, b.name);
+
+ALTER TABLE prompt_template_versions DROP CONSTRAINT prompt_template_versions_pkey;
+ALTER TABLE prompt_template_versions ADD PRIMARY KEY (org_id, version);
@@ -0,0 +1,3 @@
+-- the same template text can be set for more than one prompt, so versions are unique per prompt name
+ALTER TABLE prompt_template_versions DROP CONSTRAINT prompt_template_versions_pkey;
+ALTER TABLE prompt_template_versions ADD PRIMARY KEY (org_id, name, version);
@@ -12,6 +12,10 @@ func FormatModelContext(context []*db.Context) (string, int, error) {
 	var contextMessages []string
 	var numTokens int
 	for _, part := range context {
+		if part.Disabled {
+			continue
+		}
+
 		var message string
 		var fmtStr string
 		var args []any
@@ -19,9 +23,24 @@ func FormatModelContext(context []*db.Context) (string, int, error) {
 		if part.ContextType == shared.ContextDirectoryTreeType {
 			fmtStr = "\n\n- %s | directory tree:\n\n```\n%s\n```"
 			args = append(args, part.FilePath, part.Body)
+		} else if part.ContextType == shared.ContextFileType && part.LineRange != nil {
+			fmtStr = "\n\n- %s | lines %s (excerpt, not the full file):\n\n```\n%s\n```"
+			args = append(args, part.FilePath, part.LineRange.Label(), part.Body)
+		} else if part.ContextType == shared.ContextFileType && shared.IsNotebookFile(part.FilePath) {
+			fmtStr = "\n\n- %s | jupyter notebook (cell view, outputs omitted):\n\n```\n%s\n```"
+			args = append(args, part.FilePath, part.Body)
+		} else if part.ContextType == shared.ContextFileType && shared.IsDocumentFile(part.FilePath) {
+			fmtStr = "\n\n- %s | text extracted from a %s document (read-only, don't edit this file):\n\n```\n%s\n```"
+			args = append(args, part.FilePath, shared.GetDocumentType(part.FilePath), part.Body)
 		} else if part.ContextType == shared.ContextFileType {
 			fmtStr = "\n\n- %s:\n\n```\n%s\n```"
 			args = append(args, part.FilePath, part.Body)
+		} else if shared.IsGitContextType(part.ContextType) {
+			fmtStr = "\n\n- %s | unified diff:\n\n```diff\n%s\n```"
+			args = append(args, part.Name, part.Body)
+		} else if part.ContextType == shared.ContextCommandType {
+			fmtStr = "\n\n- output of `%s` (run in the project root):\n\n```\n%s\n```"
+			args = append(args, part.Command, part.Body)
 		} else if part.Url != "" {
 			fmtStr = "\n\n- %s:\n\n```\n%s\n```"
 			args = append(args, part.Url, part.Body)
@@ -20,6 +20,8 @@ func Build(
 	plan *db.Plan,
 	branch string,
 	auth *types.ServerAuth,
+	configSchemas map[string]string,
+	projectInstructions string,
 ) (int, error) {
 	log.Printf("Build: Called with plan ID %s on branch %s\n", plan.Id, branch)
 	log.Println("Build: Starting Build operation")
@@ -51,6 +53,17 @@ func Build(
 		return onErr(err)
 	}
 
+	instructions, instructionsTokens, err := getInstructions(auth.OrgId, projectInstructions)
+	if err != nil {
+		return onErr(err)
+	}
+
+	UpdateActivePlan(plan.Id, branch, func(ap *types.ActivePlan) {
+		ap.ConfigSchemas = configSchemas
+		ap.Instructions = instructions
+		ap.InstructionsTokens = instructionsTokens
+	})
+
 	if len(pendingBuildsByPath) == 0 {
 		log.Println("No pending builds")
 		streamDone()
@@ -241,6 +254,28 @@ func (fileState *activeBuildStreamFileState) buildFile() {
 			return
 		}
 
+		willCheckSyntax := validationRes.HasParser && !validationRes.TimedOut
+		syntaxValid := validationRes.Valid
+		syntaxErrors := validationRes.Errors
+		var schemaErrors []string
+
+		if len(activePlan.ConfigSchemas) > 0 && !(willCheckSyntax && !syntaxValid) {
+			schemaRes, err := syntax.ValidateSchema(filePath, activeBuild.FileContent, activePlan.ConfigSchemas)
+
+			if err != nil {
+				log.Printf("Error validating schema for new file '%s': %v\n", filePath, err)
+				fileState.onBuildFileError(fmt.Errorf("error validating schema for new file '%s': %v", filePath, err))
+				return
+			}
+
+			if schemaRes.HasSchema {
+				willCheckSyntax = true
+				syntaxValid = schemaRes.Valid
+				syntaxErrors = schemaRes.Errors
+				schemaErrors = schemaRes.Violations
+			}
+		}
+
 		// new file
 		planRes := &db.PlanFileResult{
 			OrgId:           currentOrgId,
@@ -249,9 +284,10 @@ func (fileState *activeBuildStreamFileState) buildFile() {
 			ConvoMessageId:  build.ConvoMessageId,
 			Path:            filePath,
 			Content:         activeBuild.FileContent,
-			WillCheckSyntax: validationRes.HasParser && !validationRes.TimedOut,
-			SyntaxValid:     validationRes.Valid,
-			SyntaxErrors:    validationRes.Errors,
+			WillCheckSyntax: willCheckSyntax,
+			SyntaxValid:     syntaxValid,
+			SyntaxErrors:    syntaxErrors,
+			SchemaErrors:    schemaErrors,
 		}
 
 		log.Println("build exec - Plan file result:")
@@ -299,7 +335,15 @@ func (fileState *activeBuildStreamFileState) buildFileLineNums() {
 
 	// log.Println("currentState:", currentState)
 
-	sysPrompt := prompts.GetBuildLineNumbersSysPrompt(filePath, originalFile, fmt.Sprintf("%s\n\n```%s```", activeBuild.FileDescription, activeBuild.FileContent))
+	builderPrompt, builderPromptVersion, err := model.GetPrompt(activePlan.OrgId, planId, shared.PromptBuilder)
+	if err != nil {
+		log.Printf("Error getting builder prompt: %v\n", err)
+		fileState.onBuildFileError(fmt.Errorf("error getting builder prompt: %v", err))
+		return
+	}
+	fileState.builderPromptVersion = builderPromptVersion
+
+	sysPrompt := prompts.GetBuildLineNumbersSysPrompt(builderPrompt, filePath, originalFile, fmt.Sprintf("%s\n\n```%s```", activeBuild.FileDescription, activeBuild.FileContent)) + activePlan.Instructions
 
 	fileMessages := []openai.ChatCompletionMessage{
 		{
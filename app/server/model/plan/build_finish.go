@@ -133,6 +133,7 @@ func (state *activeBuildStreamFileState) onFinishBuild() {
 			if len(desc.Files) > 0 {
 				desc.DidBuild = true
 				desc.BuildPathsInvalidated = map[string]bool{}
+				desc.BuildPromptVersions = buildPromptVersions(currentPlan.PlanResult.Results, desc.ConvoMessageId)
 			}
 
 			go func(desc *db.ConvoMessageDescription) {
@@ -285,6 +286,7 @@ func (fileState *activeBuildStreamFileState) onFinishBuildFile(planRes *db.PlanF
 				fileState.syntaxNumRetry = 0
 				fileState.isFixingSyntax = true
 				fileState.syntaxErrors = planRes.SyntaxErrors
+				fileState.schemaErrors = planRes.SchemaErrors
 				fileState.preBuildState = fileState.updated
 				fileState.updated = updated
 				go fileState.fixFileLineNums()
@@ -293,6 +295,7 @@ func (fileState *activeBuildStreamFileState) onFinishBuildFile(planRes *db.PlanF
 		} else {
 			fileState.isFixingSyntax = true
 			fileState.syntaxErrors = planRes.SyntaxErrors
+			fileState.schemaErrors = planRes.SchemaErrors
 			fileState.preBuildState = fileState.updated
 			fileState.updated = updated
 			go fileState.fixFileLineNums()
@@ -404,3 +407,21 @@ func (fileState *activeBuildStreamFileState) onBuildFileError(err error) {
 		log.Printf("Error clearing uncommitted changes: %v\n", err)
 	}
 }
+
+// buildPromptVersions returns the distinct builder, fixer, and verifier prompt versions used for a message's results
+func buildPromptVersions(results []*shared.PlanFileResult, convoMessageId string) []string {
+	var versions []string
+	seen := map[string]bool{}
+	for _, result := range results {
+		if result.ConvoMessageId != convoMessageId {
+			continue
+		}
+		for _, version := range []string{result.PromptVersion, result.VerifierPromptVersion} {
+			if version != "" && !seen[version] {
+				seen[version] = true
+				versions = append(versions, version)
+			}
+		}
+	}
+	return versions
+}
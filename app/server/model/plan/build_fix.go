@@ -41,9 +41,15 @@ func (fileState *activeBuildStreamFileState) fixFileLineNums() {
 	if len(fileState.syntaxErrors) > 0 {
 		reasoning += "The following are syntax errors identified by the tree-sitter library. Here are line numbers:\n\n" + strings.Join(fileState.syntaxErrors, "\n")
 	}
+	if len(fileState.schemaErrors) > 0 {
+		if reasoning != "" {
+			reasoning += "\n\n"
+		}
+		reasoning += "The following are violations of the JSON Schema configured for this file in the project. Locations are JSON pointers into the document:\n\n" + strings.Join(fileState.schemaErrors, "\n")
+	}
 
 	if fileState.verificationErrors != "" {
-		if len(fileState.syntaxErrors) > 0 {
+		if reasoning != "" {
 			reasoning += "\n\n"
 			reasoning += "The following are other problems identified in the file:\n\n"
 		} else {
@@ -52,7 +58,15 @@ func (fileState *activeBuildStreamFileState) fixFileLineNums() {
 		reasoning += fileState.verificationErrors
 	}
 
-	sysPrompt := prompts.GetBuildFixesLineNumbersSysPrompt(fileState.preBuildState, fmt.Sprintf("%s\n\n```%s```", activeBuild.FileDescription, activeBuild.FileContent), incorrectlyUpdated, reasoning)
+	fixerPrompt, fixerPromptVersion, err := model.GetPrompt(activePlan.OrgId, planId, shared.PromptBuildFixer)
+	if err != nil {
+		log.Printf("Error getting build fixer prompt: %v\n", err)
+		fileState.onBuildFileError(fmt.Errorf("error getting build fixer prompt: %v", err))
+		return
+	}
+	fileState.fixerPromptVersion = fixerPromptVersion
+
+	sysPrompt := prompts.GetBuildFixesLineNumbersSysPrompt(fixerPrompt, fileState.preBuildState, fmt.Sprintf("%s\n\n```%s```", activeBuild.FileDescription, activeBuild.FileContent), incorrectlyUpdated, reasoning) + activePlan.Instructions
 
 	fileMessages := []openai.ChatCompletionMessage{
 		{
@@ -85,7 +85,9 @@ func (fileState *activeBuildStreamFileState) onFixResult(res types.ChangesWithLi
 
 			FixEpoch: fileState.syntaxNumEpoch,
 
-			CheckSyntax: true,
+			CheckSyntax:   true,
+			ConfigSchemas: activePlan.ConfigSchemas,
+			PromptVersion: fileState.fixerPromptVersion,
 		},
 	)
 
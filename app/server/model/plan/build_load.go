@@ -106,7 +106,8 @@ func (state *activeBuildStreamState) loadPendingBuilds() (map[string][]*types.Ac
 	UpdateActivePlan(plan.Id, branch, func(ap *types.ActivePlan) {
 		ap.Contexts = modelContext
 		for _, context := range modelContext {
-			if context.FilePath != "" {
+			// line range contexts only hold part of a file, so they can't be built against
+			if context.FilePath != "" && context.LineRange == nil {
 				ap.ContextsByPath[context.FilePath] = context
 			}
 		}
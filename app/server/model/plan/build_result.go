@@ -36,10 +36,16 @@ type PlanResultParams struct {
 
 	CheckSyntax bool
 
+	// glob -> json schema, checked regardless of CheckSyntax
+	ConfigSchemas map[string]string
+
 	IsFix       bool
 	IsSyntaxFix bool
 	IsOtherFix  bool
 	FixEpoch    int
+
+	// version of the builder or fixer prompt that produced the changes
+	PromptVersion string
 }
 
 func GetPlanResult(ctx context.Context, params PlanResultParams) (*db.PlanFileResult, string, bool, error) {
@@ -191,6 +197,7 @@ func GetPlanResult(ctx context.Context, params PlanResultParams) (*db.PlanFileRe
 		IsSyntaxFix:         params.IsSyntaxFix,
 		IsOtherFix:          params.IsOtherFix,
 		FixEpoch:            params.FixEpoch,
+		PromptVersion:       params.PromptVersion,
 	}
 
 	if params.CheckSyntax {
@@ -208,6 +215,23 @@ func GetPlanResult(ctx context.Context, params PlanResultParams) (*db.PlanFileRe
 
 	}
 
+	// schema violations go through the same fix path as syntax errors
+	if len(params.ConfigSchemas) > 0 && !(res.WillCheckSyntax && !res.SyntaxValid) {
+		schemaRes, err := syntax.ValidateSchema(filePath, updated, params.ConfigSchemas)
+
+		if err != nil {
+			log.Println("Error validating schema:", err)
+			return nil, "", false, fmt.Errorf("error validating schema: %v", err)
+		}
+
+		if schemaRes.HasSchema {
+			res.WillCheckSyntax = true
+			res.SyntaxValid = schemaRes.Valid
+			res.SyntaxErrors = schemaRes.Errors
+			res.SchemaErrors = schemaRes.Violations
+		}
+	}
+
 	// spew.Dump(res)
 
 	return &res, updated, allSucceeded, nil
@@ -281,6 +305,8 @@ func (fileState *activeBuildStreamFileState) onBuildResult(res types.ChangesWith
 			ChangesWithLineNums: res.Changes,
 			OverlapStrategy:     overlapStrategy,
 			CheckSyntax:         false,
+			ConfigSchemas:       activePlan.ConfigSchemas,
+			PromptVersion:       fileState.builderPromptVersion,
 		},
 	)
 
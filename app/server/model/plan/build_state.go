@@ -48,6 +48,11 @@ type activeBuildStreamFileState struct {
 
 	verificationErrors string
 	syntaxErrors       []string
+	schemaErrors       []string
+
+	builderPromptVersion  string
+	fixerPromptVersion    string
+	verifierPromptVersion string
 
 	isNewFile bool
 }
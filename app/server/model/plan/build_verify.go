@@ -65,7 +65,16 @@ func (fileState *activeBuildStreamFileState) verifyFileBuild() {
 
 	log.Println("verifyFileBuild - got diff for file: " + filePath)
 
+	verifierPrompt, verifierPromptVersion, err := model.GetPrompt(fileState.plan.OrgId, planId, shared.PromptVerifier)
+	if err != nil {
+		log.Printf("Error getting verifier prompt: %v\n", err)
+		fileState.onBuildFileError(fmt.Errorf("error getting verifier prompt: %v", err))
+		return
+	}
+	fileState.verifierPromptVersion = verifierPromptVersion
+
 	sysPrompt := prompts.GetVerifyPrompt(
+		verifierPrompt,
 		verifyState.preBuildFileState,
 		updated,
 		verifyState.proposedChanges,
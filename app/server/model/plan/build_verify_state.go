@@ -147,6 +147,7 @@ func (fileState *activeBuildStreamFileState) MarkLatestResultVerified(passed boo
 		now := time.Now()
 		latestPlanRes.RanVerifyAt = &now
 		latestPlanRes.VerifyPassed = passed
+		latestPlanRes.VerifierPromptVersion = fileState.verifierPromptVersion
 
 		err = db.StorePlanResult(latestPlanRes)
 		if err != nil {
@@ -0,0 +1,29 @@
+package plan
+
+import (
+	"fmt"
+	"plandex-server/db"
+	"plandex-server/model/prompts"
+
+	"github.com/plandex/plandex/shared"
+)
+
+// getInstructions combines the org's instructions from its default settings with the project's instructions sent by the client. It returns the prompt section and its number of tokens.
+func getInstructions(orgId, projectInstructions string) (string, int, error) {
+	settings, err := db.GetOrgDefaultSettings(orgId, false)
+	if err != nil {
+		return "", 0, fmt.Errorf("error getting org default settings: %v", err)
+	}
+
+	prompt := prompts.GetInstructionsPrompt(settings.Instructions, projectInstructions)
+	if prompt == "" {
+		return "", 0, nil
+	}
+
+	numTokens, err := shared.GetNumTokens(prompt)
+	if err != nil {
+		return "", 0, fmt.Errorf("error getting num tokens for instructions: %v", err)
+	}
+
+	return prompt, numTokens, nil
+}
@@ -0,0 +1,139 @@
+package plan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"plandex-server/db"
+	"plandex-server/model"
+	"plandex-server/model/prompts"
+	"strings"
+
+	"github.com/plandex/plandex/shared"
+	"github.com/sashabaranov/go-openai"
+)
+
+// leave room for the findings in the reviewer's context window
+const reviewReservedOutputTokens = 4096
+
+func ReviewPendingChanges(client *openai.Client, config shared.ModelRoleConfig, orgId, planId string, planState *shared.CurrentPlanState, projectInstructions string, ctx context.Context) (*shared.PlanReview, error) {
+	diffs, err := db.GetPlanDiffs(orgId, planId, true)
+	if err != nil {
+		return nil, fmt.Errorf("error getting plan diffs: %v", err)
+	}
+
+	if strings.TrimSpace(diffs) == "" {
+		return nil, fmt.Errorf("no pending changes to review")
+	}
+
+	instructions, _, err := getInstructions(orgId, projectInstructions)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := planState.PlanResult.SortedPaths
+	updatedFiles := planState.CurrentPlanFiles.Files
+
+	prompt := prompts.GetReviewPrompt(diffs, updatedFiles, paths) + instructions
+	numTokens, err := shared.GetNumTokens(prompt)
+	if err != nil {
+		return nil, fmt.Errorf("error getting num tokens for review prompt: %v", err)
+	}
+
+	maxTokens := config.BaseModelConfig.MaxTokens - reviewReservedOutputTokens
+	if numTokens > maxTokens {
+		log.Printf("Review prompt with full files is %d tokens, over the %d limit. Sending the diff only.\n", numTokens, maxTokens)
+		prompt = prompts.GetReviewPrompt(diffs, nil, nil) + instructions
+
+		numTokens, err = shared.GetNumTokens(prompt)
+		if err != nil {
+			return nil, fmt.Errorf("error getting num tokens for review prompt: %v", err)
+		}
+
+		if numTokens > maxTokens {
+			return nil, fmt.Errorf("pending changes are too large to review: %d tokens, reviewer model limit is %d", numTokens, maxTokens)
+		}
+	}
+
+	var responseFormat *openai.ChatCompletionResponseFormat
+	if config.BaseModelConfig.HasJsonResponseMode {
+		responseFormat = &openai.ChatCompletionResponseFormat{Type: "json_object"}
+	}
+
+	resp, err := model.CreateChatCompletionWithRetries(
+		client,
+		ctx,
+		openai.ChatCompletionRequest{
+			Model: config.BaseModelConfig.ModelName,
+			Tools: []openai.Tool{
+				{
+					Type:     "function",
+					Function: &prompts.ReviewChangesFn,
+				},
+			},
+			ToolChoice: openai.ToolChoice{
+				Type: "function",
+				Function: openai.ToolFunction{
+					Name: prompts.ReviewChangesFn.Name,
+				},
+			},
+			Messages: []openai.ChatCompletionMessage{
+				{
+					Role:    openai.ChatMessageRoleSystem,
+					Content: prompt,
+				},
+			},
+			Temperature:    config.Temperature,
+			TopP:           config.TopP,
+			ResponseFormat: responseFormat,
+		},
+	)
+
+	if err != nil {
+		log.Printf("Error during review model call: %v\n", err)
+		return nil, err
+	}
+
+	var res string
+	for _, choice := range resp.Choices {
+		if len(choice.Message.ToolCalls) == 1 &&
+			choice.Message.ToolCalls[0].Function.Name == prompts.ReviewChangesFn.Name {
+			res = choice.Message.ToolCalls[0].Function.Arguments
+			break
+		}
+	}
+
+	if res == "" {
+		return nil, fmt.Errorf("no reviewChanges function call found in response")
+	}
+
+	var reviewRes prompts.ReviewPlanRes
+	err = json.Unmarshal([]byte(res), &reviewRes)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling review response: %v", err)
+	}
+
+	review := &shared.PlanReview{
+		Summary:   reviewRes.Summary,
+		ResultIds: planState.PlanResult.PendingResultIds(),
+	}
+
+	for _, finding := range reviewRes.Findings {
+		if finding == nil || finding.Message == "" {
+			continue
+		}
+
+		switch finding.Severity {
+		case shared.ReviewSeverityLow, shared.ReviewSeverityMedium, shared.ReviewSeverityHigh:
+		default:
+			finding.Severity = shared.ReviewSeverityMedium
+		}
+
+		review.Findings = append(review.Findings, finding)
+	}
+
+	review.SortFindings()
+
+	return review, nil
+}
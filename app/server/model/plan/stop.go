@@ -31,14 +31,16 @@ func StorePartialReply(planId, branch, currentUserId, currentOrgId string) error
 		num := active.MessageNum + 1
 
 		userMsg := db.ConvoMessage{
-			OrgId:   currentOrgId,
-			PlanId:  planId,
-			UserId:  currentUserId,
-			Role:    openai.ChatMessageRoleAssistant,
-			Tokens:  active.NumTokens,
-			Num:     num,
-			Stopped: true,
-			Message: active.CurrentReplyContent,
+			OrgId:         currentOrgId,
+			PlanId:        planId,
+			UserId:        currentUserId,
+			Role:          openai.ChatMessageRoleAssistant,
+			Tokens:        active.NumTokens,
+			Num:           num,
+			Stopped:       true,
+			Message:       active.CurrentReplyContent,
+			IsChat:        active.IsChat,
+			PromptVersion: active.PromptVersion,
 		}
 
 		_, err := db.StoreConvoMessage(&userMsg, currentUserId, branch, true)
@@ -20,13 +20,52 @@ import (
 func Tell(clients map[string]*openai.Client, plan *db.Plan, branch string, auth *types.ServerAuth, req *shared.TellPlanRequest) error {
 	log.Printf("Tell: Called with plan ID %s on branch %s\n", plan.Id, branch)
 
-	_, err := activatePlan(clients, plan, branch, auth, req.Prompt, false)
+	instructions, instructionsTokens, err := getInstructions(auth.OrgId, req.ProjectInstructions)
+
+	if err != nil {
+		log.Printf("Error getting instructions: %v\n", err)
+		return err
+	}
+
+	promptName := shared.PromptPlanner
+	if req.IsChat {
+		// chat replies are answered in a single response and never build
+		promptName = shared.PromptChat
+		req.AutoContinue = false
+		req.BuildMode = shared.BuildModeNone
+	}
+
+	sysPrompt, promptVersion, err := model.GetPrompt(auth.OrgId, plan.Id, promptName)
+
+	if err != nil {
+		log.Printf("Error getting %s prompt: %v\n", promptName, err)
+		return err
+	}
+
+	sysPromptTokens, err := shared.GetNumTokens(sysPrompt)
+
+	if err != nil {
+		log.Printf("Error getting num tokens for %s prompt: %v\n", promptName, err)
+		return err
+	}
+
+	_, err = activatePlan(clients, plan, branch, auth, req.Prompt, false)
 
 	if err != nil {
 		log.Printf("Error activating plan: %v\n", err)
 		return err
 	}
 
+	UpdateActivePlan(plan.Id, branch, func(ap *types.ActivePlan) {
+		ap.ConfigSchemas = req.ConfigSchemas
+		ap.Instructions = instructions
+		ap.InstructionsTokens = instructionsTokens
+		ap.SysPrompt = sysPrompt
+		ap.SysPromptTokens = sysPromptTokens
+		ap.PromptVersion = promptVersion
+		ap.IsChat = req.IsChat
+	})
+
 	go execTellPlan(
 		clients,
 		plan,
@@ -122,7 +161,8 @@ func execTellPlan(
 			ap.Contexts = state.modelContext
 
 			for _, context := range state.modelContext {
-				if context.FilePath != "" {
+				// line range contexts only hold part of a file, so they can't be built against
+				if context.FilePath != "" && context.LineRange == nil {
 					ap.ContextsByPath[context.FilePath] = context
 				}
 			}
@@ -165,7 +205,7 @@ func execTellPlan(
 		return
 	}
 
-	systemMessageText := prompts.SysCreate + modelContextText
+	systemMessageText := active.SysPrompt + active.Instructions + modelContextText
 	systemMessage := openai.ChatCompletionMessage{
 		Role:    openai.ChatMessageRoleSystem,
 		Content: systemMessageText,
@@ -184,7 +224,7 @@ func execTellPlan(
 
 	// Add a separate message for image contexts
 	for _, context := range state.modelContext {
-		if context.ContextType == shared.ContextImageType {
+		if context.ContextType == shared.ContextImageType && !context.Disabled {
 			if !state.settings.ModelPack.Planner.BaseModelConfig.HasImageSupport {
 				err = fmt.Errorf("%s does not support images in context", state.settings.ModelPack.Planner.BaseModelConfig.ModelName)
 				log.Println(err)
@@ -232,13 +272,18 @@ func execTellPlan(
 			}
 			return
 		}
-		promptTokens = prompts.PromptWrapperTokens + numPromptTokens
+		if req.IsChat {
+			promptTokens = prompts.ChatPromptWrapperTokens + numPromptTokens
+		} else {
+			promptTokens = prompts.PromptWrapperTokens + numPromptTokens
+		}
 	}
 
-	state.tokensBeforeConvo = prompts.CreateSysMsgNumTokens + modelContextTokens + state.latestSummaryTokens + promptTokens
+	state.tokensBeforeConvo = active.SysPromptTokens + active.InstructionsTokens + modelContextTokens + state.latestSummaryTokens + promptTokens
 
 	// print out breakdown of token usage
-	log.Printf("System message tokens: %d\n", prompts.CreateSysMsgNumTokens)
+	log.Printf("System message tokens: %d\n", active.SysPromptTokens)
+	log.Printf("Instructions tokens: %d\n", active.InstructionsTokens)
 	log.Printf("Context tokens: %d\n", modelContextTokens)
 	log.Printf("Prompt tokens: %d\n", promptTokens)
 	log.Printf("Latest summary tokens: %d\n", state.latestSummaryTokens)
@@ -325,9 +370,14 @@ func execTellPlan(
 
 			state.userPrompt = prompt
 
+			wrapped := prompts.GetWrappedPrompt(prompt)
+			if req.IsChat {
+				wrapped = prompts.GetWrappedChatPrompt(prompt)
+			}
+
 			promptMessage = &openai.ChatCompletionMessage{
 				Role:    openai.ChatMessageRoleUser,
-				Content: prompts.GetWrappedPrompt(prompt),
+				Content: wrapped,
 			}
 		}
 
@@ -180,6 +180,7 @@ func (state *activeTellStreamState) loadTellPlan() error {
 					Tokens:  promptTokens,
 					Num:     num,
 					Message: req.Prompt,
+					IsChat:  req.IsChat,
 				}
 
 				_, err = db.StoreConvoMessage(userMsg, auth.User.Id, branch, true)
@@ -142,6 +142,12 @@ func (state *activeTellStreamState) listenStream(stream *openai.ChatCompletionSt
 				}()
 
 				go func() {
+					if req.IsChat {
+						// chat replies never continue
+						errCh <- nil
+						return
+					}
+
 					log.Println("Getting exec status")
 					shouldContinue, nextTask, err = state.execStatusShouldContinue(active.CurrentReplyContent, latestSummaryCh, active.Ctx)
 					if err != nil {
@@ -367,9 +373,15 @@ func (state *activeTellStreamState) listenStream(stream *openai.ChatCompletionSt
 
 			replyParser.AddChunk(content, true)
 			parserRes := replyParser.Read()
+			state.replyNumTokens = parserRes.TotalTokens
+
+			// chat replies are stored as-is, so any file blocks are neither parsed nor built
+			if req.IsChat {
+				continue
+			}
+
 			files := parserRes.Files
 			fileContents := parserRes.FileContents
-			state.replyNumTokens = parserRes.TotalTokens
 			currentFile := parserRes.CurrentFilePath
 			fileDescriptions := parserRes.FileDescriptions
 
@@ -531,14 +543,16 @@ func (state *activeTellStreamState) storeAssistantReply() (*db.ConvoMessage, str
 	}
 
 	assistantMsg := db.ConvoMessage{
-		Id:      replyId,
-		OrgId:   currentOrgId,
-		PlanId:  planId,
-		UserId:  currentUserId,
-		Role:    openai.ChatMessageRoleAssistant,
-		Tokens:  replyNumTokens,
-		Num:     num,
-		Message: activePlan.CurrentReplyContent,
+		Id:            replyId,
+		OrgId:         currentOrgId,
+		PlanId:        planId,
+		UserId:        currentUserId,
+		Role:          openai.ChatMessageRoleAssistant,
+		Tokens:        replyNumTokens,
+		Num:           num,
+		Message:       activePlan.CurrentReplyContent,
+		IsChat:        activePlan.IsChat,
+		PromptVersion: activePlan.PromptVersion,
 	}
 
 	commitMsg, err := db.StoreConvoMessage(&assistantMsg, auth.User.Id, branch, false)
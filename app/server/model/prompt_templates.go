@@ -0,0 +1,77 @@
+package model
+
+import (
+	"plandex-server/db"
+	"plandex-server/model/prompts"
+
+	"github.com/plandex/plandex/shared"
+)
+
+// GetPrompt resolves a prompt template from the plan's override, then the org's, then the default, and renders it. It returns the rendered prompt and the template's version.
+func GetPrompt(orgId, planId string, name shared.PromptName) (string, string, error) {
+	tmpl := prompts.DefaultTemplate(name)
+
+	override, err := db.GetPromptTemplate(orgId, planId, name)
+	if err != nil {
+		return "", "", err
+	}
+	if override != nil {
+		tmpl = override.Template
+	}
+
+	rendered, err := prompts.RenderTemplate(name, tmpl)
+	if err != nil {
+		return "", "", err
+	}
+
+	return rendered, shared.GetPromptVersion(tmpl), nil
+}
+
+// ListPrompts returns the template that's in effect for each prompt, for the plan if planId isn't empty, otherwise for the org
+func ListPrompts(orgId, planId string) ([]*shared.PromptTemplate, error) {
+	overrides, err := db.ListPromptTemplates(orgId, planId)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := map[shared.PromptName]*shared.PromptTemplate{}
+	for _, override := range overrides {
+		apiTemplate := override.ToApi()
+		// plan overrides take precedence over org overrides
+		if existing, ok := byName[apiTemplate.Name]; ok && existing.Scope == shared.PromptScopePlan {
+			continue
+		}
+		byName[apiTemplate.Name] = apiTemplate
+	}
+
+	var res []*shared.PromptTemplate
+	for _, name := range shared.AllPromptNames {
+		if tmpl, ok := byName[name]; ok {
+			res = append(res, tmpl)
+			continue
+		}
+		tmpl := prompts.DefaultTemplate(name)
+		res = append(res, &shared.PromptTemplate{
+			Name:     name,
+			Scope:    shared.PromptScopeDefault,
+			Template: tmpl,
+			Version:  shared.GetPromptVersion(tmpl),
+		})
+	}
+
+	return res, nil
+}
+
+// GetPromptByVersion returns the template with the given version, whether it's a default or was ever set in the org, or nil if there's none. name narrows the lookup to one prompt if it isn't empty.
+func GetPromptByVersion(orgId string, name shared.PromptName, version string) (*shared.PromptTemplate, error) {
+	if defaultName, tmpl, ok := prompts.GetDefaultTemplateByVersion(version); ok && (name == "" || name == defaultName) {
+		return &shared.PromptTemplate{
+			Name:     defaultName,
+			Scope:    shared.PromptScopeDefault,
+			Template: tmpl,
+			Version:  version,
+		}, nil
+	}
+
+	return db.GetPromptTemplateVersion(orgId, name, version)
+}
@@ -8,7 +8,8 @@ import (
 	"github.com/sashabaranov/go-openai/jsonschema"
 )
 
-func GetBuildLineNumbersSysPrompt(filePath, preBuildState, changes string) string {
+// GetBuildLineNumbersSysPrompt takes the rendered builder prompt template as instructions
+func GetBuildLineNumbersSysPrompt(instructions, filePath, preBuildState, changes string) string {
 	// hash := sha256.Sum256([]byte(currentState))
 	// sha := hex.EncodeToString(hash[:])
 
@@ -16,17 +17,18 @@ func GetBuildLineNumbersSysPrompt(filePath, preBuildState, changes string) strin
 
 	preBuildStateWithLineNums := shared.AddLineNums(preBuildState)
 
-	return getListChangesLineNumsPrompt() + "\n\n" + getPreBuildStatePrompt(filePath, preBuildStateWithLineNums) + "\n\n" + getBuildPromptWithLineNums(changes)
+	return instructions + "\n\n" + getPreBuildStatePrompt(filePath, preBuildStateWithLineNums) + "\n\n" + getBuildPromptWithLineNums(changes)
 }
 
-func GetBuildFixesLineNumbersSysPrompt(original, changes, updated, reasoning string) string {
+// GetBuildFixesLineNumbersSysPrompt takes the rendered build-fixer prompt template as instructions
+func GetBuildFixesLineNumbersSysPrompt(instructions, original, changes, updated, reasoning string) string {
 	// hash := sha256.Sum256([]byte(updated))
 	// sha := hex.EncodeToString(hash[:])
 	// log.Println("GetBuildFixesLineNumbersSysPrompt updated sha:", sha)
 
 	updatedWithLineNums := shared.AddLineNums(updated)
 
-	return getFixChangesLineNumsPrompt() + "\n\n" + getBuildPromptForFixesWithLineNums(original, changes, updatedWithLineNums, reasoning)
+	return instructions + "\n\n" + getBuildPromptForFixesWithLineNums(original, changes, updatedWithLineNums, reasoning)
 }
 
 func getBuildPromptWithLineNums(changes string) string {
@@ -60,9 +62,19 @@ func getPreBuildStatePrompt(filePath, preBuildState string) string {
 		return ""
 	}
 
-	return fmt.Sprintf("**The current file is %s. Original state of the file:**\n```\n%s\n```", filePath, preBuildState) + "\n\n"
+	s := fmt.Sprintf("**The current file is %s. Original state of the file:**\n```\n%s\n```", filePath, preBuildState) + "\n\n"
+
+	if shared.IsNotebookFile(filePath) {
+		s += notebookPrompt + "\n\n"
+	}
+
+	return s
 }
 
+const notebookPrompt = `
+This file is a Jupyter notebook shown as a cell-delimited text view. Each cell begins with a marker line like '# %% [cell 3] code' that gives the cell's index in the original notebook and its type (code, markdown, or raw). Edit cells by index: change the source lines below a cell's marker to update that cell, and remove a cell's marker and source to delete it. *Never* change the index in an existing marker line. To add a new cell, insert a marker line with 'new' in place of the index, like '# %% [cell new] code', followed by the cell's source. Cell outputs aren't shown and must not be added.
+`
+
 const replacementIntro = `
 You are an AI that analyzes a code file and an AI-generated plan to update the code file and produces a list of changes.
 `
@@ -360,8 +372,7 @@ var ListReplacementsFn = openai.FunctionDefinition{
 	},
 }
 
-func GetVerifyPrompt(preBuildState, updated, changes, diff string) string {
-	s := `
+const verifyPrompt = `
 Based on an original file (if one exists), an AI-generated plan, an updated file, and a diff between the original and updated file, determine whether the updated file's syntax is correct and whether the proposed updates were applied correctly to the updated file.
 
 You must consider whether any of the following problems are present in the updated file:
@@ -408,6 +419,10 @@ If there are multiple identical comments in the *updated file*, you MUST list th
 In each of the reasoning keys above, be exhaustive and include *every* problem that is present in the file. But if there are no problems in a reasoning key, do NOT invent problems--explain according to your instructions for each key that there are no problems in that category.
 `
 
+// GetVerifyPrompt takes the rendered verifier prompt template as instructions
+func GetVerifyPrompt(instructions, preBuildState, updated, changes, diff string) string {
+	s := instructions
+
 	if preBuildState != "" {
 		s += `
 --
@@ -0,0 +1,31 @@
+package prompts
+
+import (
+	"fmt"
+
+	"github.com/plandex/plandex/shared"
+)
+
+// sysChatTemplate is the default chat prompt template, used by `plandex chat` to answer questions without making a plan
+const sysChatTemplate = "{{.Identity}}" + ` Right now, the user wants to chat rather than make changes. You will be given the plan's context and conversation so far, and you must answer the user's latest message.
+
+[YOUR INSTRUCTIONS:]
+
+Answer questions, explain code, discuss approaches, and help the user think through the task at hand. Use the context and the conversation so far to give accurate, specific answers. If you don't have enough context to answer well, say what's missing and which files or information the user could load to help.
+
+You *must not* make a plan or implement changes in this mode. Do not write file blocks labelled with a file path, and do not present changes as if they will be applied to the user's project. Short code snippets that illustrate an answer are fine, but label them only with a language, never with a file path.
+
+If the user asks you to make changes, briefly describe what you would do, then let them know they can send the task with 'plandex tell' to make a plan and build the changes.
+
+This exchange is stored in the plan's conversation, so later prompts can refer to it.
+
+[END OF YOUR INSTRUCTIONS]
+`
+
+const chatPromptWrapperFormatStr = "# The user's latest message:\n```\n%s\n```\n\n" + `Please respond according to the 'Your instructions' section above. Don't make a plan or write any file blocks.`
+
+func GetWrappedChatPrompt(prompt string) string {
+	return fmt.Sprintf(chatPromptWrapperFormatStr, prompt)
+}
+
+var ChatPromptWrapperTokens, _ = shared.GetNumTokens(fmt.Sprintf(chatPromptWrapperFormatStr, ""))
@@ -6,7 +6,8 @@ import (
 	"github.com/plandex/plandex/shared"
 )
 
-const SysCreate = "Please avoid executing tasks in a loop. Ensure each step progresses towards the goal without repetition." + Identity + ` A plan is a set of files with an attached context.` +
+// sysCreateTemplate is the default planner prompt template
+const sysCreateTemplate = "Please avoid executing tasks in a loop. Ensure each step progresses towards the goal without repetition.{{.Identity}}" + ` A plan is a set of files with an attached context.` +
 
     "[YOUR INSTRUCTIONS:]" +
 
@@ -77,6 +78,8 @@ const SysCreate = "Please avoid executing tasks in a loop. Ensure each step prog
 
 		If a change is related to code in an existing file in context, make the change as an update to the existing file. Do NOT create a new file for a change that applies to an existing file in context. For example, if there is an 'Page.tsx' file in the existing context and the user has asked you to update the structure of the page component, make the change in the existing 'Page.tsx' file. Do NOT create a new file like 'page.tsx' or 'NewPage.tsx' for the change. If the user has specifically asked you to apply a change to a new file, then you can create a new file. If there is no existing file that makes sense to apply a change to, then you can create a new file.
 
+		Jupyter notebooks (.ipynb files) in context are shown as a cell-delimited text view, with a marker line like '# %% [cell 3] code' before each cell. When updating a notebook, write the file block in this same text view, *not* as notebook JSON. Refer to existing cells by their marker, keep the index in existing markers unchanged, and use '# %% [cell new] code' or '# %% [cell new] markdown' for new cells.
+
 		For code in markdown blocks, always include the language name after the opening triple backticks.
 
 		If there are triple backticks within any file in context, they will be escaped with backslashes like this '` + "\\`\\`\\`" + `'. If you are outputting triple backticks in a code block, you MUST escape them in exactly the same way.
@@ -224,8 +227,6 @@ const SysCreate = "Please avoid executing tasks in a loop. Ensure each step prog
 	[END OF YOUR INSTRUCTIONS]
 	`
 
-var CreateSysMsgNumTokens, _ = shared.GetNumTokens(SysCreate)
-
 const promptWrapperFormatStr = "# The user's latest prompt:\n```\n%s\n```\n\n" + `Please respond according to the 'Your instructions' section above.
 
 If you're making a plan, remember to label code blocks with the file path *exactly* as described in 2a, and do not use any other formatting for file paths. **Do not include explanations or any other text apart from the file path in code block labels.**
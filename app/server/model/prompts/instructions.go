@@ -0,0 +1,27 @@
+package prompts
+
+import "strings"
+
+// GetInstructionsPrompt returns a section with the org's and project's standing instructions to add to a system prompt, or an empty string if there are none
+func GetInstructionsPrompt(orgInstructions, projectInstructions string) string {
+	orgInstructions = strings.TrimSpace(orgInstructions)
+	projectInstructions = strings.TrimSpace(projectInstructions)
+
+	if orgInstructions == "" && projectInstructions == "" {
+		return ""
+	}
+
+	s := "\n\n[INSTRUCTIONS]\nThe user has set the following standing instructions. They apply to all work on this project. Follow them unless the user's prompt explicitly overrides them. If project instructions conflict with organization instructions, the project instructions take precedence.\n"
+
+	if orgInstructions != "" {
+		s += "\nOrganization instructions:\n\n" + orgInstructions + "\n"
+	}
+
+	if projectInstructions != "" {
+		s += "\nProject instructions:\n\n" + projectInstructions + "\n"
+	}
+
+	s += "[END INSTRUCTIONS]\n\n"
+
+	return s
+}
@@ -0,0 +1,85 @@
+package prompts
+
+import (
+	"github.com/plandex/plandex/shared"
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/jsonschema"
+)
+
+type ReviewPlanRes struct {
+	Summary  string                  `json:"summary"`
+	Findings []*shared.ReviewFinding `json:"findings"`
+}
+
+const SysReview = `You are an AI code reviewer. Another AI has proposed changes to a user's project. You will be given a diff of all pending changes, and usually the full updated state of each changed file with line numbers. Your job is to audit the changes for bugs and security issues before they are applied.
+
+Look for:
+- Logic errors, off-by-one errors, incorrect conditions, unhandled errors or edge cases, and nil/null dereferences
+- Code that won't compile or run: undefined references, wrong types or signatures, missing imports
+- Security issues: injection, unsafe deserialization, hardcoded secrets, missing authorization checks, unsafe file or shell operations, and leaking sensitive data
+- Concurrency issues such as races and deadlocks
+- Changes that break existing callers or behavior in ways that weren't intended
+
+Only report real problems introduced or touched by the pending changes. Don't report style preferences, naming, or formatting. Don't report problems in code the changes don't touch. If there are no problems, return an empty 'findings' array.
+
+For each finding, include:
+- 'path': the file path exactly as it appears in the diff
+- 'line': the line number in the *updated* file where the problem is. Use the line numbers from the updated file if they were provided. Use 0 if the problem isn't tied to a specific line.
+- 'severity': 'high' for bugs that will break functionality or security vulnerabilities, 'medium' for likely bugs or risky code, and 'low' for minor issues worth a look.
+- 'message': a concise explanation of the problem and how to fix it.
+
+Also include a 'summary' with a one or two sentence overall assessment of the changes.
+
+You MUST call the 'reviewChanges' function with a valid JSON object that includes the 'summary' and 'findings' keys. You must ALWAYS call the 'reviewChanges' function. Never call any other function.`
+
+var ReviewChangesFn = openai.FunctionDefinition{
+	Name: "reviewChanges",
+	Parameters: &jsonschema.Definition{
+		Type: jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{
+			"summary": {
+				Type: jsonschema.String,
+			},
+			"findings": {
+				Type: jsonschema.Array,
+				Items: &jsonschema.Definition{
+					Type: jsonschema.Object,
+					Properties: map[string]jsonschema.Definition{
+						"path": {
+							Type: jsonschema.String,
+						},
+						"line": {
+							Type: jsonschema.Integer,
+						},
+						"severity": {
+							Type: jsonschema.String,
+							Enum: shared.AllReviewSeverities,
+						},
+						"message": {
+							Type: jsonschema.String,
+						},
+					},
+					Required: []string{"path", "line", "severity", "message"},
+				},
+			},
+		},
+		Required: []string{"summary", "findings"},
+	},
+}
+
+func GetReviewPrompt(diffs string, updatedFiles map[string]string, paths []string) string {
+	s := SysReview + "\n\n**Pending changes:**\n\n```diff\n" + diffs + "\n```"
+
+	if len(updatedFiles) > 0 {
+		s += "\n\n**Updated files with line numbers:**\n"
+		for _, path := range paths {
+			content, ok := updatedFiles[path]
+			if !ok {
+				continue
+			}
+			s += "\n- " + path + ":\n\n```\n" + shared.AddLineNums(content) + "\n```\n"
+		}
+	}
+
+	return s
+}
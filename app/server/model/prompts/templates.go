@@ -0,0 +1,54 @@
+package prompts
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/plandex/plandex/shared"
+)
+
+// Prompt templates can be overridden per org or per plan. They're rendered with text/template, so overrides can reference the variables in TemplateData, like {{.Identity}}.
+
+type TemplateData struct {
+	Identity string
+}
+
+var defaultTemplates = map[shared.PromptName]string{
+	shared.PromptPlanner:    sysCreateTemplate,
+	shared.PromptBuilder:    getListChangesLineNumsPrompt(),
+	shared.PromptBuildFixer: getFixChangesLineNumsPrompt(),
+	shared.PromptVerifier:   verifyPrompt,
+	shared.PromptSummarizer: PlanSummary,
+	shared.PromptChat:       sysChatTemplate,
+}
+
+func DefaultTemplate(name shared.PromptName) string {
+	return defaultTemplates[name]
+}
+
+// GetDefaultTemplateByVersion returns the default template with the given version, if there is one
+func GetDefaultTemplateByVersion(version string) (shared.PromptName, string, bool) {
+	for _, name := range shared.AllPromptNames {
+		tmpl := defaultTemplates[name]
+		if shared.GetPromptVersion(tmpl) == version {
+			return name, tmpl, true
+		}
+	}
+	return "", "", false
+}
+
+func RenderTemplate(name shared.PromptName, tmpl string) (string, error) {
+	t, err := template.New(string(name)).Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("error parsing %s prompt template: %v", name, err)
+	}
+
+	var sb strings.Builder
+	err = t.Execute(&sb, TemplateData{Identity: Identity})
+	if err != nil {
+		return "", fmt.Errorf("error rendering %s prompt template: %v", name, err)
+	}
+
+	return sb.String(), nil
+}
@@ -0,0 +1,29 @@
+package prompts
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/plandex/plandex/shared"
+)
+
+func TestRenderDefaultTemplates(t *testing.T) {
+	for _, name := range shared.AllPromptNames {
+		rendered, err := RenderTemplate(name, DefaultTemplate(name))
+		if err != nil {
+			t.Fatalf("error rendering default %s template: %v", name, err)
+		}
+		if strings.Contains(rendered, "{{") {
+			t.Errorf("expected %s template to be fully rendered", name)
+		}
+	}
+
+	planner, _ := RenderTemplate(shared.PromptPlanner, DefaultTemplate(shared.PromptPlanner))
+	if !strings.Contains(planner, Identity) {
+		t.Errorf("expected planner prompt to include the identity")
+	}
+
+	if _, err := RenderTemplate(shared.PromptPlanner, "{{.Unknown}}"); err == nil {
+		t.Errorf("expected an error for an unknown variable")
+	}
+}
@@ -21,6 +21,11 @@ type PlanSummaryParams struct {
 }
 
 func PlanSummary(client *openai.Client, config shared.ModelRoleConfig, params PlanSummaryParams, ctx context.Context) (*db.ConvoSummary, error) {
+	summaryPrompt, _, err := GetPrompt(params.OrgId, params.PlanId, shared.PromptSummarizer)
+	if err != nil {
+		return nil, err
+	}
+
 	messages := []openai.ChatCompletionMessage{
 		{
 			Role:    openai.ChatMessageRoleSystem,
@@ -34,7 +39,7 @@ func PlanSummary(client *openai.Client, config shared.ModelRoleConfig, params Pl
 
 	messages = append(messages, openai.ChatCompletionMessage{
 		Role:    openai.ChatMessageRoleUser,
-		Content: prompts.PlanSummary,
+		Content: summaryPrompt,
 	})
 
 	fmt.Println("summarizing messages:")
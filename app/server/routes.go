@@ -59,6 +59,7 @@ func routes() *mux.Router {
 	r.HandleFunc("/plans", handlers.ListPlansHandler).Methods("GET")
 	r.HandleFunc("/plans/archive", handlers.ListArchivedPlansHandler).Methods("GET")
 	r.HandleFunc("/plans/ps", handlers.ListPlansRunningHandler).Methods("GET")
+	r.HandleFunc("/plans/search", handlers.SearchPlansHandler).Methods("GET")
 
 	r.HandleFunc("/projects/{projectId}/plans", handlers.CreatePlanHandler).Methods("POST")
 
@@ -85,19 +86,34 @@ func routes() *mux.Router {
 	r.HandleFunc("/plans/{planId}/{branch}/reject_file", handlers.RejectFileHandler).Methods("PATCH")
 	r.HandleFunc("/plans/{planId}/{branch}/reject_files", handlers.RejectFilesHandler).Methods("PATCH")
 	r.HandleFunc("/plans/{planId}/{branch}/diffs", handlers.GetPlanDiffsHandler).Methods("GET")
+	r.HandleFunc("/plans/{planId}/{branch}/review", handlers.ReviewPlanHandler).Methods("POST")
+	r.HandleFunc("/plans/{planId}/{branch}/review_requests", handlers.RequestReviewHandler).Methods("POST")
+	r.HandleFunc("/plans/{planId}/{branch}/review_feedback", handlers.ReviewFeedbackHandler).Methods("POST")
 
 	r.HandleFunc("/plans/{planId}/{branch}/context", handlers.ListContextHandler).Methods("GET")
 	r.HandleFunc("/plans/{planId}/{branch}/context", handlers.LoadContextHandler).Methods("POST")
 	r.HandleFunc("/plans/{planId}/{branch}/context", handlers.UpdateContextHandler).Methods("PUT")
 	r.HandleFunc("/plans/{planId}/{branch}/context", handlers.DeleteContextHandler).Methods("DELETE")
+	r.HandleFunc("/plans/{planId}/{branch}/context/group", handlers.UpdateContextGroupHandler).Methods("PUT")
+	r.HandleFunc("/plans/{planId}/{branch}/context/replay", handlers.ReplayContextChangesHandler).Methods("PUT")
 
 	r.HandleFunc("/plans/{planId}/{branch}/convo", handlers.ListConvoHandler).Methods("GET")
+	r.HandleFunc("/plans/{planId}/{branch}/convo/diffs", handlers.ListConvoDiffsHandler).Methods("GET")
 	r.HandleFunc("/plans/{planId}/{branch}/rewind", handlers.RewindPlanHandler).Methods("PATCH")
 	r.HandleFunc("/plans/{planId}/{branch}/logs", handlers.ListLogsHandler).Methods("GET")
+	r.HandleFunc("/plans/{planId}/{branch}/tags", handlers.ListPlanTagsHandler).Methods("GET")
+	r.HandleFunc("/plans/{planId}/{branch}/tags", handlers.CreatePlanTagHandler).Methods("POST")
+	r.HandleFunc("/plans/{planId}/{branch}/tags/{tag}", handlers.DeletePlanTagHandler).Methods("DELETE")
+
+	r.HandleFunc("/plans/{planId}/collaborators", handlers.ListPlanCollaboratorsHandler).Methods("GET")
+	r.HandleFunc("/plans/{planId}/share", handlers.SharePlanHandler).Methods("POST")
+	r.HandleFunc("/plans/{planId}/unshare", handlers.UnsharePlanHandler).Methods("POST")
 
 	r.HandleFunc("/plans/{planId}/branches", handlers.ListBranchesHandler).Methods("GET")
 	r.HandleFunc("/plans/{planId}/branches/{branch}", handlers.DeleteBranchHandler).Methods("DELETE")
 	r.HandleFunc("/plans/{planId}/{branch}/branches", handlers.CreateBranchHandler).Methods("POST")
+	r.HandleFunc("/plans/{planId}/{branch}/merge", handlers.MergeBranchHandler).Methods("POST")
+	r.HandleFunc("/plans/{planId}/{branch}/compare/{otherBranch}", handlers.CompareBranchesHandler).Methods("GET")
 
 	r.HandleFunc("/plans/{planId}/{branch}/settings", handlers.GetSettingsHandler).Methods("GET")
 	r.HandleFunc("/plans/{planId}/{branch}/settings", handlers.UpdateSettingsHandler).Methods("PUT")
@@ -115,6 +131,14 @@ func routes() *mux.Router {
 	r.HandleFunc("/default_settings", handlers.GetDefaultSettingsHandler).Methods("GET")
 	r.HandleFunc("/default_settings", handlers.UpdateDefaultSettingsHandler).Methods("PUT")
 
+	r.HandleFunc("/prompts", handlers.ListPromptsHandler).Methods("GET")
+	r.HandleFunc("/prompts/versions/{version}", handlers.GetPromptVersionHandler).Methods("GET")
+	r.HandleFunc("/prompts/{name}", handlers.UpdatePromptHandler).Methods("PUT")
+	r.HandleFunc("/prompts/{name}", handlers.DeletePromptHandler).Methods("DELETE")
+	r.HandleFunc("/plans/{planId}/prompts", handlers.ListPromptsHandler).Methods("GET")
+	r.HandleFunc("/plans/{planId}/prompts/{name}", handlers.UpdatePromptHandler).Methods("PUT")
+	r.HandleFunc("/plans/{planId}/prompts/{name}", handlers.DeletePromptHandler).Methods("DELETE")
+
 	return r
 
 }
@@ -0,0 +1,38 @@
+package syntax
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+func validateJson(ext, file string) *ValidationRes {
+	var v interface{}
+	err := json.Unmarshal([]byte(file), &v)
+	if err == nil {
+		return &ValidationRes{Ext: ext, Lang: "json", HasParser: true, Valid: true}
+	}
+
+	var msg string
+	switch e := err.(type) {
+	case *json.SyntaxError:
+		msg = fmt.Sprintf("Invalid syntax on line %d: %s", jsonLineNumber(file, e.Offset), e.Error())
+	default:
+		msg = fmt.Sprintf("Invalid syntax: %s", err.Error())
+	}
+
+	return &ValidationRes{
+		Ext:       ext,
+		Lang:      "json",
+		HasParser: true,
+		Valid:     false,
+		Errors:    []string{msg},
+	}
+}
+
+func jsonLineNumber(source string, offset int64) int {
+	if offset > int64(len(source)) {
+		offset = int64(len(source))
+	}
+	return strings.Count(source[:offset], "\n") + 1
+}
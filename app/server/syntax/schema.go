@@ -0,0 +1,203 @@
+package syntax
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"gopkg.in/yaml.v3"
+)
+
+type SchemaValidationRes = struct {
+	Glob      string
+	HasSchema bool
+	Valid     bool
+	// Errors are syntax errors that kept the file from being decoded
+	Errors []string
+	// Violations are the places the decoded file doesn't match the schema
+	Violations []string
+}
+
+// ValidateSchema checks a json, yaml, or toml file against the first schema (by glob, in sorted order) whose glob matches its path.
+// Globs without a slash are matched against the file's base name, so 'package.json' matches at any depth.
+func ValidateSchema(path, file string, schemasByGlob map[string]string) (*SchemaValidationRes, error) {
+	glob, schemaStr := getSchemaForPath(path, schemasByGlob)
+	if glob == "" {
+		return &SchemaValidationRes{}, nil
+	}
+
+	docs, err := decodeConfigDocs(path, file)
+	if err != nil {
+		return &SchemaValidationRes{
+			Glob:      glob,
+			HasSchema: true,
+			Valid:     false,
+			Errors:    []string{fmt.Sprintf("Invalid syntax: %v", err)},
+		}, nil
+	}
+
+	if docs == nil {
+		// not a config format we can decode
+		return &SchemaValidationRes{}, nil
+	}
+
+	compiler := jsonschema.NewCompiler()
+	schemaUrl := "file:///plandex/schemas/" + schemaResourceName(glob) + ".json"
+	err = compiler.AddResource(schemaUrl, strings.NewReader(schemaStr))
+	if err != nil {
+		return nil, fmt.Errorf("error adding schema for '%s': %v", glob, err)
+	}
+
+	schema, err := compiler.Compile(schemaUrl)
+	if err != nil {
+		return nil, fmt.Errorf("error compiling schema for '%s': %v", glob, err)
+	}
+
+	var violations []string
+	for i, doc := range docs {
+		err := schema.Validate(doc)
+		if err == nil {
+			continue
+		}
+
+		var validationErr *jsonschema.ValidationError
+		if !errors.As(err, &validationErr) {
+			return nil, fmt.Errorf("error validating against schema for '%s': %v", glob, err)
+		}
+
+		prefix := ""
+		if len(docs) > 1 {
+			prefix = fmt.Sprintf("document %d, ", i+1)
+		}
+
+		for _, leaf := range schemaErrorLeaves(validationErr) {
+			location := leaf.InstanceLocation
+			if location == "" {
+				location = "/"
+			}
+			violations = append(violations, fmt.Sprintf("Schema violation at %s'%s': %s", prefix, location, leaf.Message))
+		}
+	}
+
+	return &SchemaValidationRes{
+		Glob:       glob,
+		HasSchema:  true,
+		Valid:      len(violations) == 0,
+		Violations: violations,
+	}, nil
+}
+
+func getSchemaForPath(path string, schemasByGlob map[string]string) (string, string) {
+	globs := make([]string, 0, len(schemasByGlob))
+	for glob := range schemasByGlob {
+		globs = append(globs, glob)
+	}
+	sort.Strings(globs)
+
+	path = filepath.ToSlash(filepath.Clean(path))
+
+	for _, glob := range globs {
+		toMatch := path
+		if !strings.Contains(glob, "/") {
+			toMatch = filepath.Base(path)
+		}
+
+		matched, err := filepath.Match(filepath.ToSlash(glob), toMatch)
+		if err == nil && matched {
+			return glob, schemasByGlob[glob]
+		}
+	}
+
+	return "", ""
+}
+
+// decodeConfigDocs returns each document in the file as a plain json value suitable for schema validation
+// yaml files can contain multiple documents separated by '---'
+func decodeConfigDocs(path, file string) ([]interface{}, error) {
+	var raw []interface{}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		var v interface{}
+		err := json.Unmarshal([]byte(file), &v)
+		if err != nil {
+			return nil, err
+		}
+		raw = append(raw, v)
+
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(strings.NewReader(file))
+		for {
+			var v interface{}
+			err := decoder.Decode(&v)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			if v != nil {
+				raw = append(raw, v)
+			}
+		}
+		if raw == nil {
+			// an empty file is validated as an empty document
+			raw = append(raw, nil)
+		}
+
+	case ".toml":
+		var v map[string]interface{}
+		_, err := toml.Decode(file, &v)
+		if err != nil {
+			return nil, err
+		}
+		raw = append(raw, v)
+
+	default:
+		return nil, nil
+	}
+
+	// round trip through json so that yaml/toml values (ints, dates, etc.) become json types
+	var docs []interface{}
+	for _, v := range raw {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("document can't be represented as json: %v", err)
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(b))
+		decoder.UseNumber()
+		var doc interface{}
+		err = decoder.Decode(&doc)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+
+	return docs, nil
+}
+
+func schemaErrorLeaves(err *jsonschema.ValidationError) []*jsonschema.ValidationError {
+	if len(err.Causes) == 0 {
+		return []*jsonschema.ValidationError{err}
+	}
+
+	var leaves []*jsonschema.ValidationError
+	for _, cause := range err.Causes {
+		leaves = append(leaves, schemaErrorLeaves(cause)...)
+	}
+	return leaves
+}
+
+func schemaResourceName(glob string) string {
+	r := strings.NewReplacer("/", "_", "*", "_", "?", "_", "[", "_", "]", "_", " ", "_", "#", "_", "%", "_")
+	return r.Replace(glob)
+}
@@ -0,0 +1,135 @@
+package syntax
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testSchema = `{
+	"type": "object",
+	"required": ["name"],
+	"properties": {
+		"name": {"type": "string"},
+		"replicas": {"type": "integer", "minimum": 1}
+	}
+}`
+
+func TestGetSchemaForPath(t *testing.T) {
+	schemas := map[string]string{
+		"package.json":  "package",
+		"k8s/*.yaml":    "k8s",
+		"*.yaml":        "any yaml",
+		"config/*.toml": "config",
+	}
+
+	tests := map[string]string{
+		// globs without a slash match the base name at any depth
+		"package.json":               "package.json",
+		"web/app/package.json":       "package.json",
+		"k8s/deploy.yaml":            "*.yaml",
+		"k8s/nested/svc.yaml":        "*.yaml",
+		"config/app.toml":            "config/*.toml",
+		"other/config/app.toml":      "",
+		"./config/../config/db.toml": "config/*.toml",
+		"main.go":                    "",
+	}
+
+	for path, expected := range tests {
+		glob, schema := getSchemaForPath(path, schemas)
+		if glob != expected {
+			t.Errorf("getSchemaForPath(%q) matched %q, expected %q", path, glob, expected)
+		}
+		if glob != "" && schema != schemas[glob] {
+			t.Errorf("getSchemaForPath(%q) returned the wrong schema", path)
+		}
+	}
+
+	// the first matching glob in sorted order wins
+	glob, _ := getSchemaForPath("k8s/deploy.yaml", map[string]string{"k8s/*.yaml": "k8s", "*.yaml": "any yaml"})
+	if glob != "*.yaml" {
+		t.Errorf("expected '*.yaml' to sort before 'k8s/*.yaml', got %q", glob)
+	}
+}
+
+func TestValidateSchema(t *testing.T) {
+	schemas := map[string]string{"*.json": testSchema, "*.yaml": testSchema, "*.toml": testSchema}
+
+	tests := []struct {
+		path       string
+		file       string
+		valid      bool
+		errors     []string
+		violations []string
+	}{
+		{path: "app.json", file: `{"name": "web", "replicas": 2}`, valid: true},
+		{path: "app.json", file: `{"replicas": 0}`, violations: []string{
+			"Schema violation at '/': missing properties: 'name'",
+			"Schema violation at '/replicas': must be >= 1 but found 0",
+		}},
+		{path: "app.toml", file: "name = \"web\"\nreplicas = 3\n", valid: true},
+		{path: "app.toml", file: "name = 5\n", violations: []string{
+			"Schema violation at '/name': expected string, but got number",
+		}},
+		// each yaml document is validated separately
+		{path: "app.yaml", file: "name: web\n---\nreplicas: 2\n", violations: []string{
+			"Schema violation at document 2, '/': missing properties: 'name'",
+		}},
+		// files that can't be decoded are reported as syntax errors rather than violations
+		{path: "app.yaml", file: "name: [web\n", errors: []string{"Invalid syntax"}},
+	}
+
+	for _, test := range tests {
+		res, err := ValidateSchema(test.path, test.file, schemas)
+		if err != nil {
+			t.Fatalf("ValidateSchema(%s, %q) failed: %v", test.path, test.file, err)
+		}
+
+		if !res.HasSchema || res.Valid != test.valid {
+			t.Errorf("ValidateSchema(%s, %q): expected valid=%v, got %+v", test.path, test.file, test.valid, res)
+		}
+
+		if !reflect.DeepEqual(res.Violations, test.violations) {
+			t.Errorf("ValidateSchema(%s, %q): expected violations %q, got %q", test.path, test.file, test.violations, res.Violations)
+		}
+
+		if len(res.Errors) != len(test.errors) {
+			t.Errorf("ValidateSchema(%s, %q): expected errors %q, got %q", test.path, test.file, test.errors, res.Errors)
+		}
+		for i := range test.errors {
+			if i < len(res.Errors) && !strings.HasPrefix(res.Errors[i], test.errors[i]) {
+				t.Errorf("ValidateSchema(%s, %q): expected error starting with %q, got %q", test.path, test.file, test.errors[i], res.Errors[i])
+			}
+		}
+	}
+
+	res, err := ValidateSchema("main.go", "package main", schemas)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.HasSchema {
+		t.Errorf("expected no schema for a file that doesn't match any glob")
+	}
+
+	_, err = ValidateSchema("app.json", `{}`, map[string]string{"*.json": `{"type": 5}`})
+	if err == nil {
+		t.Errorf("expected an invalid schema to return an error")
+	}
+}
+
+func TestValidateJson(t *testing.T) {
+	res := validateJson(".json", "{\n  \"a\": 1\n}")
+	if !res.Valid || !res.HasParser || res.Lang != "json" {
+		t.Errorf("expected valid json, got %+v", res)
+	}
+
+	res = validateJson(".json", "{\n  \"a\": 1,\n  \"b\": \n}")
+	if res.Valid || len(res.Errors) != 1 || !strings.HasPrefix(res.Errors[0], "Invalid syntax on line 4:") {
+		t.Errorf("expected a syntax error on line 4, got %+v", res)
+	}
+
+	res = validateJson(".json", "{\"a\": 1")
+	if res.Valid || len(res.Errors) != 1 || !strings.HasPrefix(res.Errors[0], "Invalid syntax") {
+		t.Errorf("expected a syntax error for truncated json, got %+v", res)
+	}
+}
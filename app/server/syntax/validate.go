@@ -25,6 +25,11 @@ type ValidationRes = struct {
 func Validate(ctx context.Context, path, file string) (*ValidationRes, error) {
 	ext := filepath.Ext(path)
 
+	// no tree-sitter grammar for json, but the standard library parser reports error offsets
+	if strings.ToLower(ext) == ".json" {
+		return validateJson(ext, file), nil
+	}
+
 	parser, lang, fallbackParser, fallbackLang := getParserForExt(ext)
 
 	if parser == nil {
@@ -78,6 +78,13 @@ type ActivePlan struct {
 	MissingFileResponseCh   chan shared.RespondMissingFileChoice
 	AllowOverwritePaths     map[string]bool
 	SkippedPaths            map[string]bool
+	ConfigSchemas           map[string]string
+	Instructions            string
+	InstructionsTokens      int
+	SysPrompt               string
+	SysPromptTokens         int
+	PromptVersion           string
+	IsChat                  bool
 	StoredReplyIds          []string
 
 	subscriptions  map[string]*subscription
@@ -82,6 +82,10 @@ var RequiredCompatibilityByRole = map[ModelRole]ModelCompatibility{
 		HasStreaming:       true,
 		HasFunctionCalling: true,
 	},
+	ModelRoleReviewer: {
+		IsOpenAICompatible: true,
+		HasFunctionCalling: true,
+	},
 }
 
 var DefaultConfigByRole = map[ModelRole]ModelRoleConfig{
@@ -117,6 +121,10 @@ var DefaultConfigByRole = map[ModelRole]ModelRoleConfig{
 		Temperature: 0.1,
 		TopP:        0.1,
 	},
+	ModelRoleReviewer: {
+		Temperature: 0.2,
+		TopP:        0.2,
+	},
 }
 var DefaultModelPack *ModelPack
 var fullCompatibility = ModelCompatibility{
@@ -3,6 +3,7 @@ package shared
 import (
 	"fmt"
 	"math"
+	"path"
 	"strconv"
 	"strings"
 
@@ -18,9 +19,45 @@ type ContextUpdateResult struct {
 	NumUrls         int
 	NumImages       int
 	NumTrees        int
+	NumGitDiffs     int
+	NumCommands     int
 	MaxTokens       int
 }
 
+// ValidateContextPath checks that a context's file path is relative and stays inside the project root. Paths are stored on the server and read on the machine of anyone the plan is shared with, so this is checked both when context is loaded and before it's refreshed.
+func ValidateContextPath(filePath string) error {
+	if filePath == "" {
+		return nil
+	}
+
+	// checked with forward slashes so windows paths are caught on any os
+	slashed := strings.ReplaceAll(filePath, "\\", "/")
+
+	if strings.HasPrefix(slashed, "/") || (len(slashed) > 1 && slashed[1] == ':') {
+		return fmt.Errorf("%s is an absolute path -- context paths must be relative to the project root", filePath)
+	}
+
+	cleaned := path.Clean(slashed)
+	if cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return fmt.Errorf("%s is outside the project root", filePath)
+	}
+
+	return nil
+}
+
+// git contexts hold unified diff text and are regenerated from their GitRef when checked for updates
+func (c *Context) IsGit() bool {
+	return IsGitContextType(c.ContextType)
+}
+
+func IsGitContextType(contextType ContextType) bool {
+	switch contextType {
+	case ContextGitDiffType, ContextGitCommitType, ContextGitStagedType:
+		return true
+	}
+	return false
+}
+
 func (c *Context) TypeAndIcon() (string, string) {
 	var icon string
 	var t string
@@ -43,6 +80,18 @@ func (c *Context) TypeAndIcon() (string, string) {
 	case ContextImageType:
 		icon = "🖼️ "
 		t = "image"
+	case ContextGitDiffType:
+		icon = "🔀"
+		t = "diff"
+	case ContextGitCommitType:
+		icon = "🔀"
+		t = "commit"
+	case ContextGitStagedType:
+		icon = "🔀"
+		t = "staged"
+	case ContextCommandType:
+		icon = "💻"
+		t = "command"
 	}
 
 	return t, icon
@@ -82,6 +131,8 @@ func SummaryForLoadContext(contexts []*Context, tokensAdded, totalTokens int) st
 	var numFiles int
 	var numTrees int
 	var numUrls int
+	var numGitDiffs int
+	var numCommands int
 
 	for _, context := range contexts {
 		switch context.ContextType {
@@ -91,6 +142,10 @@ func SummaryForLoadContext(contexts []*Context, tokensAdded, totalTokens int) st
 			numUrls++
 		case ContextDirectoryTreeType:
 			numTrees++
+		case ContextGitDiffType, ContextGitCommitType, ContextGitStagedType:
+			numGitDiffs++
+		case ContextCommandType:
+			numCommands++
 		case ContextNoteType:
 			hasNote = true
 		case ContextPipedDataType:
@@ -127,6 +182,20 @@ func SummaryForLoadContext(contexts []*Context, tokensAdded, totalTokens int) st
 		}
 		added = append(added, fmt.Sprintf("%d %s", numUrls, label))
 	}
+	if numGitDiffs > 0 {
+		label := "git diff"
+		if numGitDiffs > 1 {
+			label = "git diffs"
+		}
+		added = append(added, fmt.Sprintf("%d %s", numGitDiffs, label))
+	}
+	if numCommands > 0 {
+		label := "command output"
+		if numCommands > 1 {
+			label = "command outputs"
+		}
+		added = append(added, fmt.Sprintf("%d %s", numCommands, label))
+	}
 
 	msg := "Loaded "
 
@@ -177,7 +246,10 @@ func SummaryForRemoveContext(contexts []*Context, previousTotalTokens int) strin
 	removedTokens := 0
 
 	for _, context := range contexts {
-		removedTokens += context.NumTokens
+		// disabled contexts already don't count toward the total
+		if !context.Disabled {
+			removedTokens += context.NumTokens
+		}
 	}
 
 	totalTokens := previousTotalTokens - removedTokens
@@ -190,10 +262,25 @@ func SummaryForRemoveContext(contexts []*Context, previousTotalTokens int) strin
 	return fmt.Sprintf("Removed %d piece%s of context | removed → %d 🪙 | total → %d 🪙", len(contexts), suffix, removedTokens, totalTokens)
 }
 
+func SummaryForUpdateContextGroup(group string, disabled bool, numContexts, tokensChanged, totalTokens int) string {
+	suffix := ""
+	if numContexts > 1 {
+		suffix = "s"
+	}
+
+	if disabled {
+		return fmt.Sprintf("Disabled context group '%s' | %d piece%s of context | removed → %d 🪙 | total → %d 🪙", group, numContexts, suffix, tokensChanged, totalTokens)
+	}
+
+	return fmt.Sprintf("Enabled context group '%s' | %d piece%s of context | added → %d 🪙 | total → %d 🪙", group, numContexts, suffix, tokensChanged, totalTokens)
+}
+
 func SummaryForUpdateContext(updateRes *ContextUpdateResult) string {
 	numFiles := updateRes.NumFiles
 	numTrees := updateRes.NumTrees
 	numUrls := updateRes.NumUrls
+	numGitDiffs := updateRes.NumGitDiffs
+	numCommands := updateRes.NumCommands
 	tokensDiff := updateRes.TokensDiff
 	totalTokens := updateRes.TotalTokens
 
@@ -220,6 +307,20 @@ func SummaryForUpdateContext(updateRes *ContextUpdateResult) string {
 		}
 		toAdd = append(toAdd, fmt.Sprintf("%d url%s", numUrls, postfix))
 	}
+	if numGitDiffs > 0 {
+		postfix := "s"
+		if numGitDiffs == 1 {
+			postfix = ""
+		}
+		toAdd = append(toAdd, fmt.Sprintf("%d git diff%s", numGitDiffs, postfix))
+	}
+	if numCommands > 0 {
+		postfix := "s"
+		if numCommands == 1 {
+			postfix = ""
+		}
+		toAdd = append(toAdd, fmt.Sprintf("%d command output%s", numCommands, postfix))
+	}
 
 	if len(toAdd) <= 2 {
 		msg += " " + strings.Join(toAdd, " and ")
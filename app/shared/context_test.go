@@ -0,0 +1,19 @@
+package shared
+
+import "testing"
+
+func TestValidateContextPath(t *testing.T) {
+	valid := []string{"", "main.go", "src/lib/file.go", ".", "./src/file.go", "src/../main.go", "..hidden/file"}
+	for _, p := range valid {
+		if err := ValidateContextPath(p); err != nil {
+			t.Errorf("expected %q to be valid, got %v", p, err)
+		}
+	}
+
+	invalid := []string{"/home/owner/.ssh/id_rsa", "../../.aws/credentials", "..", "src/../../x", `..\..\x`, `C:\Users\owner\x`, `\\server\share\x`}
+	for _, p := range invalid {
+		if err := ValidateContextPath(p); err == nil {
+			t.Errorf("expected %q to be rejected", p)
+		}
+	}
+}
@@ -52,11 +52,28 @@ type Plan struct {
 	ProjectId       string     `json:"projectId"`
 	Name            string     `json:"name"`
 	SharedWithOrgAt *time.Time `json:"sharedWithOrgAt,omitempty"`
-	TotalReplies    int        `json:"totalReplies"`
-	ActiveBranches  int        `json:"activeBranches"`
-	ArchivedAt      *time.Time `json:"archivedAt,omitempty"`
-	CreatedAt       time.Time  `json:"createdAt"`
-	UpdatedAt       time.Time  `json:"updatedAt"`
+	// SharedWithOrgAccess is the access everyone in the org has when the plan is shared with the org
+	SharedWithOrgAccess PlanAccess `json:"sharedWithOrgAccess,omitempty"`
+	TotalReplies        int        `json:"totalReplies"`
+	ActiveBranches      int        `json:"activeBranches"`
+	ArchivedAt          *time.Time `json:"archivedAt,omitempty"`
+	CreatedAt           time.Time  `json:"createdAt"`
+	UpdatedAt           time.Time  `json:"updatedAt"`
+}
+
+type PlanAccess string
+
+const (
+	PlanAccessRead  PlanAccess = "read"
+	PlanAccessWrite PlanAccess = "write"
+)
+
+type PlanCollaborator struct {
+	UserId    string     `json:"userId"`
+	UserName  string     `json:"userName"`
+	UserEmail string     `json:"userEmail"`
+	Access    PlanAccess `json:"access"`
+	CreatedAt time.Time  `json:"createdAt"`
 }
 
 type Branch struct {
@@ -66,6 +83,7 @@ type Branch struct {
 	ParentBranchId  *string    `json:"parentBranchId"`
 	Name            string     `json:"name"`
 	Status          PlanStatus `json:"status"`
+	Error           string     `json:"error,omitempty"`
 	ContextTokens   int        `json:"contextTokens"`
 	ConvoTokens     int        `json:"convoTokens"`
 	SharedWithOrgAt *time.Time `json:"sharedWithOrgAt,omitempty"`
@@ -74,6 +92,16 @@ type Branch struct {
 	UpdatedAt       time.Time  `json:"updatedAt"`
 }
 
+// PlanTag is a named checkpoint in a plan's history
+type PlanTag struct {
+	Name      string    `json:"name"`
+	Sha       string    `json:"sha"`
+	Message   string    `json:"message"`
+	CreatedAt time.Time `json:"createdAt"`
+	// OnBranch is true if the tagged commit is in the branch's history
+	OnBranch bool `json:"onBranch"`
+}
+
 type ContextType string
 
 const (
@@ -83,6 +111,10 @@ const (
 	ContextDirectoryTreeType ContextType = "directory tree"
 	ContextPipedDataType     ContextType = "piped data"
 	ContextImageType         ContextType = "image"
+	ContextGitDiffType       ContextType = "git diff"
+	ContextGitCommitType     ContextType = "git commit"
+	ContextGitStagedType     ContextType = "git staged"
+	ContextCommandType       ContextType = "command"
 )
 
 type Context struct {
@@ -93,23 +125,34 @@ type Context struct {
 	Url             string                `json:"url"`
 	FilePath        string                `json:"file_path"`
 	Sha             string                `json:"sha"`
+	SourceSha       string                `json:"sourceSha,omitempty"`
+	LoadedFrom      string                `json:"loadedFrom,omitempty"`
 	NumTokens       int                   `json:"numTokens"`
 	Body            string                `json:"body,omitempty"`
 	ForceSkipIgnore bool                  `json:"forceSkipIgnore"`
 	ImageDetail     openai.ImageURLDetail `json:"imageDetail,omitempty"`
+	Group           string                `json:"group,omitempty"`
+	Disabled        bool                  `json:"disabled,omitempty"`
+	LineRange       *LineRange            `json:"lineRange,omitempty"`
+	GitRef          string                `json:"gitRef,omitempty"`
+	Command         string                `json:"command,omitempty"`
 	CreatedAt       time.Time             `json:"createdAt"`
 	UpdatedAt       time.Time             `json:"updatedAt"`
 }
 
 type ConvoMessage struct {
-	Id        string    `json:"id"`
-	UserId    string    `json:"userId"`
-	Role      string    `json:"role"`
-	Tokens    int       `json:"tokens"`
-	Num       int       `json:"num"`
-	Message   string    `json:"message"`
-	Stopped   bool      `json:"stopped"`
-	CreatedAt time.Time `json:"createdAt"`
+	Id      string `json:"id"`
+	UserId  string `json:"userId"`
+	Role    string `json:"role"`
+	Tokens  int    `json:"tokens"`
+	Num     int    `json:"num"`
+	Message string `json:"message"`
+	Stopped bool   `json:"stopped"`
+	// IsChat marks messages sent with `plandex chat`, which are answered without making changes to files
+	IsChat bool `json:"isChat,omitempty"`
+	// PromptVersion is the version of the system prompt that produced an assistant message
+	PromptVersion string    `json:"promptVersion,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
 }
 
 type ConvoSummary struct {
@@ -135,6 +178,9 @@ type ConvoMessageDescription struct {
 	AppliedAt             *time.Time      `json:"appliedAt,omitempty"`
 	CreatedAt             time.Time       `json:"createdAt"`
 	UpdatedAt             time.Time       `json:"updatedAt"`
+
+	// BuildPromptVersions are the versions of the builder, fixer, and verifier prompts used to build the message's files
+	BuildPromptVersions []string `json:"buildPromptVersions,omitempty"`
 }
 
 type PlanBuild struct {
@@ -177,6 +223,11 @@ type PlanFileResult struct {
 	IsSyntaxFix bool `json:"isSyntaxFix"`
 	IsOtherFix  bool `json:"isOtherFix"`
 
+	// PromptVersion is the version of the builder prompt, or the fixer prompt for fixes, that produced the result
+	PromptVersion string `json:"promptVersion,omitempty"`
+	// VerifierPromptVersion is the version of the verifier prompt that checked the result
+	VerifierPromptVersion string `json:"verifierPromptVersion,omitempty"`
+
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
 }
@@ -200,6 +251,8 @@ type CurrentPlanState struct {
 	CurrentPlanFiles         *CurrentPlanFiles          `json:"currentPlanFiles"`
 	ConvoMessageDescriptions []*ConvoMessageDescription `json:"convoMessageDescriptions"`
 	ContextsByPath           map[string]*Context        `json:"contextsByPath"`
+	Review                   *PlanReview                `json:"review,omitempty"`
+	ReviewState              *PlanReviewState           `json:"reviewState,omitempty"`
 }
 
 type OrgRole struct {
@@ -305,6 +358,7 @@ type ModelPack struct {
 	// optional for backwards compatibility
 	Verifier *ModelRoleConfig `json:"verifier"`
 	AutoFix  *ModelRoleConfig `json:"autoFix"`
+	Reviewer *ModelRoleConfig `json:"reviewer"`
 }
 
 func (m *ModelPack) GetVerifier() ModelRoleConfig {
@@ -321,6 +375,17 @@ func (m *ModelPack) GetAutoFix() ModelRoleConfig {
 	return *m.AutoFix
 }
 
+// reviewing is closer to planning than building, so the reviewer falls back to the planner model if it supports function calls
+func (m *ModelPack) GetReviewer() ModelRoleConfig {
+	if m.Reviewer == nil {
+		if m.Planner.BaseModelConfig.HasFunctionCalling {
+			return m.Planner.ModelRoleConfig
+		}
+		return m.Builder
+	}
+	return *m.Reviewer
+}
+
 type ModelOverrides struct {
 	MaxConvoTokens       *int `json:"maxConvoTokens"`
 	MaxTokens            *int `json:"maxContextTokens"`
@@ -330,7 +395,9 @@ type ModelOverrides struct {
 type PlanSettings struct {
 	ModelOverrides ModelOverrides `json:"modelOverrides"`
 	ModelPack      *ModelPack     `json:"modelPack"`
-	UpdatedAt      time.Time      `json:"updatedAt"`
+	// Instructions are only read from the org's default settings. They're included in planner, builder, and reviewer prompts for every plan in the org.
+	Instructions string    `json:"instructions,omitempty"`
+	UpdatedAt    time.Time `json:"updatedAt"`
 }
 
 func (p *PlanSettings) Scan(src interface{}) error {
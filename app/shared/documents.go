@@ -0,0 +1,422 @@
+package shared
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// PDF, DOCX and ODT documents are loaded into context as extracted text. Headings are kept as markdown headings and page boundaries as marker lines.
+// The sha of the original document is kept as the context's SourceSha so outdated checks don't need to re-extract the text.
+
+const (
+	DocumentTypePdf  = "pdf"
+	DocumentTypeDocx = "docx"
+	DocumentTypeOdt  = "odt"
+)
+
+var documentContentTypes = map[string]string{
+	"application/pdf": DocumentTypePdf,
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document": DocumentTypeDocx,
+	"application/vnd.oasis.opendocument.text":                                 DocumentTypeOdt,
+}
+
+// GetDocumentType returns the document type for a file path or url, or an empty string if it isn't a supported document
+func GetDocumentType(path string) string {
+	// drop any query string or fragment from urls
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".pdf":
+		return DocumentTypePdf
+	case ".docx":
+		return DocumentTypeDocx
+	case ".odt":
+		return DocumentTypeOdt
+	}
+	return ""
+}
+
+func IsDocumentFile(path string) bool {
+	return GetDocumentType(path) != ""
+}
+
+// GetDocumentTypeForContentType returns the document type for an http Content-Type header, or an empty string if it isn't a supported document
+func GetDocumentTypeForContentType(contentType string) string {
+	mediaType := strings.TrimSpace(strings.Split(contentType, ";")[0])
+	return documentContentTypes[strings.ToLower(mediaType)]
+}
+
+func GetDocumentSha(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+func DocumentToText(documentType string, data []byte) (string, error) {
+	switch documentType {
+	case DocumentTypePdf:
+		return pdfToText(data)
+	case DocumentTypeDocx:
+		return docxToText(data)
+	case DocumentTypeOdt:
+		return odtToText(data)
+	}
+	return "", fmt.Errorf("unsupported document type: %s", documentType)
+}
+
+func documentPageMarker(page int) string {
+	return fmt.Sprintf("--- page %d ---", page)
+}
+
+const documentPageBreakMarker = "--- page break ---"
+
+func pdfToText(data []byte) (string, error) {
+	reader, err := pdf.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("error reading pdf: %v", err)
+	}
+
+	var sb strings.Builder
+
+	// pdfs don't mark headings in their text, but the outline (bookmarks) lists them
+	outline := reader.Outline()
+	if len(outline.Child) > 0 {
+		sb.WriteString("# Outline\n\n")
+		writePdfOutline(&sb, outline.Child, 0)
+		sb.WriteString("\n")
+	}
+
+	numPages := reader.NumPage()
+	for i := 1; i <= numPages; i++ {
+		page := reader.Page(i)
+		if page.V.IsNull() {
+			continue
+		}
+
+		rows, err := page.GetTextByRow()
+		if err != nil {
+			return "", fmt.Errorf("error reading pdf page %d: %v", i, err)
+		}
+
+		sb.WriteString(documentPageMarker(i) + "\n\n")
+		for _, row := range rows {
+			var line strings.Builder
+			for _, text := range row.Content {
+				line.WriteString(text.S)
+			}
+			if s := strings.TrimRight(line.String(), " \t"); s != "" {
+				sb.WriteString(s + "\n")
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	return strings.TrimSpace(sb.String()), nil
+}
+
+func writePdfOutline(sb *strings.Builder, entries []pdf.Outline, depth int) {
+	for _, entry := range entries {
+		if entry.Title != "" {
+			sb.WriteString(strings.Repeat("  ", depth) + "- " + entry.Title + "\n")
+		}
+		writePdfOutline(sb, entry.Child, depth+1)
+	}
+}
+
+// maxDocumentXmlSize caps how much of a document's xml is read, so a small archive that decompresses to a huge file can't exhaust memory
+var maxDocumentXmlSize int64 = 50 * 1024 * 1024
+
+func readZipFile(data []byte, name string) ([]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("error reading document archive: %v", err)
+	}
+
+	for _, f := range zr.File {
+		if f.Name != name {
+			continue
+		}
+
+		if f.UncompressedSize64 > uint64(maxDocumentXmlSize) {
+			return nil, fmt.Errorf("%s is too large (%d bytes, max %d)", name, f.UncompressedSize64, maxDocumentXmlSize)
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("error opening %s: %v", name, err)
+		}
+		defer rc.Close()
+
+		// the size in the archive's header can't be trusted, so limit the read too
+		content, err := io.ReadAll(io.LimitReader(rc, maxDocumentXmlSize+1))
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %v", name, err)
+		}
+		if int64(len(content)) > maxDocumentXmlSize {
+			return nil, fmt.Errorf("%s is too large (max %d bytes)", name, maxDocumentXmlSize)
+		}
+
+		return content, nil
+	}
+
+	return nil, fmt.Errorf("%s not found in document", name)
+}
+
+func xmlAttr(el xml.StartElement, local string) string {
+	for _, attr := range el.Attr {
+		if attr.Name.Local == local {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// documentWriter assembles paragraphs into lines of text with markdown heading and list prefixes
+type documentWriter struct {
+	sb        strings.Builder
+	paragraph strings.Builder
+	prefix    string
+	inTable   bool
+	cells     []string
+}
+
+func (w *documentWriter) endParagraph() {
+	text := strings.TrimSpace(w.paragraph.String())
+	w.paragraph.Reset()
+
+	if w.inTable {
+		// a cell can hold several paragraphs
+		if text != "" && len(w.cells) > 0 {
+			i := len(w.cells) - 1
+			if w.cells[i] != "" {
+				w.cells[i] += " "
+			}
+			w.cells[i] += text
+		}
+		return
+	}
+
+	if text != "" {
+		w.sb.WriteString(w.prefix + text + "\n\n")
+	}
+	w.prefix = ""
+}
+
+func (w *documentWriter) startCell() {
+	w.cells = append(w.cells, "")
+}
+
+func (w *documentWriter) endRow() {
+	if len(w.cells) > 0 {
+		w.sb.WriteString("| " + strings.Join(w.cells, " | ") + " |\n")
+	}
+	w.cells = nil
+}
+
+func (w *documentWriter) endTable() {
+	w.inTable = false
+	w.sb.WriteString("\n")
+}
+
+func (w *documentWriter) pageBreak() {
+	w.endParagraph()
+	w.sb.WriteString(documentPageBreakMarker + "\n\n")
+}
+
+func (w *documentWriter) String() string {
+	return strings.TrimSpace(w.sb.String())
+}
+
+func headingPrefix(level int) string {
+	if level < 1 {
+		level = 1
+	}
+	if level > 6 {
+		level = 6
+	}
+	return strings.Repeat("#", level) + " "
+}
+
+func docxToText(data []byte) (string, error) {
+	content, err := readZipFile(data, "word/document.xml")
+	if err != nil {
+		return "", err
+	}
+
+	w := &documentWriter{}
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	inText := false
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("error parsing docx: %v", err)
+		}
+
+		switch el := token.(type) {
+		case xml.StartElement:
+			switch el.Name.Local {
+			case "tbl":
+				w.inTable = true
+			case "tc":
+				w.startCell()
+			case "pStyle":
+				style := xmlAttr(el, "val")
+				if style == "Title" {
+					w.prefix = headingPrefix(1)
+				} else if strings.HasPrefix(style, "Heading") {
+					level, err := strconv.Atoi(strings.TrimPrefix(style, "Heading"))
+					if err == nil {
+						w.prefix = headingPrefix(level)
+					}
+				}
+			case "numPr":
+				if w.prefix == "" {
+					w.prefix = "- "
+				}
+			case "pageBreakBefore":
+				if xmlAttr(el, "val") != "false" && xmlAttr(el, "val") != "0" {
+					w.sb.WriteString(documentPageBreakMarker + "\n\n")
+				}
+			case "t":
+				inText = true
+			case "tab":
+				w.paragraph.WriteString("\t")
+			case "br":
+				if xmlAttr(el, "type") == "page" {
+					w.pageBreak()
+				} else {
+					w.paragraph.WriteString("\n")
+				}
+			}
+		case xml.EndElement:
+			switch el.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				w.endParagraph()
+			case "tr":
+				w.endRow()
+			case "tbl":
+				w.endTable()
+			}
+		case xml.CharData:
+			if inText {
+				w.paragraph.Write(el)
+			}
+		}
+	}
+
+	return w.String(), nil
+}
+
+func odtToText(data []byte) (string, error) {
+	content, err := readZipFile(data, "content.xml")
+	if err != nil {
+		return "", err
+	}
+
+	w := &documentWriter{}
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+
+	// automatic paragraph styles that start a new page
+	pageBreakStyles := map[string]bool{}
+	var currentStyle string
+	depth := 0
+	textDepth := 0
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("error parsing odt: %v", err)
+		}
+
+		switch el := token.(type) {
+		case xml.StartElement:
+			depth++
+			switch el.Name.Local {
+			case "style":
+				currentStyle = xmlAttr(el, "name")
+			case "paragraph-properties":
+				if currentStyle != "" && xmlAttr(el, "break-before") == "page" {
+					pageBreakStyles[currentStyle] = true
+				}
+			case "table":
+				w.inTable = true
+			case "table-cell":
+				w.startCell()
+			case "h":
+				if pageBreakStyles[xmlAttr(el, "style-name")] {
+					w.pageBreak()
+				}
+				level, err := strconv.Atoi(xmlAttr(el, "outline-level"))
+				if err != nil {
+					level = 1
+				}
+				w.prefix = headingPrefix(level)
+				if textDepth == 0 {
+					textDepth = depth
+				}
+			case "p":
+				if pageBreakStyles[xmlAttr(el, "style-name")] {
+					w.pageBreak()
+				}
+				if textDepth == 0 {
+					textDepth = depth
+				}
+			case "list-item":
+				w.prefix = "- "
+			case "soft-page-break":
+				w.pageBreak()
+			case "s":
+				n, err := strconv.Atoi(xmlAttr(el, "c"))
+				if err != nil {
+					n = 1
+				}
+				w.paragraph.WriteString(strings.Repeat(" ", n))
+			case "tab":
+				w.paragraph.WriteString("\t")
+			case "line-break":
+				w.paragraph.WriteString("\n")
+			}
+		case xml.EndElement:
+			switch el.Name.Local {
+			case "style":
+				currentStyle = ""
+			case "h", "p":
+				if depth == textDepth {
+					textDepth = 0
+					w.endParagraph()
+				}
+			case "table-row":
+				w.endRow()
+			case "table":
+				w.endTable()
+			}
+			depth--
+		case xml.CharData:
+			if textDepth > 0 {
+				w.paragraph.Write(el)
+			}
+		}
+	}
+
+	return w.String(), nil
+}
@@ -0,0 +1,101 @@
+package shared
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func zipDocument(t *testing.T, name, content string) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	f, err := zw.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDocxToText(t *testing.T) {
+	data := zipDocument(t, "word/document.xml", `<?xml version="1.0" encoding="UTF-8"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>
+<w:p><w:pPr><w:pStyle w:val="Title"/></w:pPr><w:r><w:t>Design</w:t></w:r></w:p>
+<w:p><w:pPr><w:pStyle w:val="Heading2"/></w:pPr><w:r><w:t>Goals</w:t></w:r></w:p>
+<w:p><w:r><w:t xml:space="preserve">Make it </w:t></w:r><w:r><w:t>fast.</w:t></w:r></w:p>
+<w:p><w:pPr><w:numPr><w:ilvl w:val="0"/></w:numPr></w:pPr><w:r><w:t>First item</w:t></w:r></w:p>
+<w:p><w:r><w:br w:type="page"/><w:t>Next page</w:t></w:r></w:p>
+<w:tbl><w:tr><w:tc><w:p><w:r><w:t>a</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>b</w:t></w:r></w:p></w:tc></w:tr></w:tbl>
+</w:body></w:document>`)
+
+	text, err := DocumentToText(GetDocumentType("design.docx"), data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "# Design\n\n## Goals\n\nMake it fast.\n\n- First item\n\n--- page break ---\n\nNext page\n\n| a | b |"
+	if text != expected {
+		t.Errorf("unexpected docx text:\n%s", text)
+	}
+}
+
+func TestOdtToText(t *testing.T) {
+	data := zipDocument(t, "content.xml", `<?xml version="1.0" encoding="UTF-8"?>
+<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0" xmlns:style="urn:oasis:names:tc:opendocument:xmlns:style:1.0" xmlns:fo="urn:oasis:names:tc:opendocument:xmlns:xsl-fo-compatible:1.0">
+<office:automatic-styles><style:style style:name="P1" style:family="paragraph"><style:paragraph-properties fo:break-before="page"/></style:style></office:automatic-styles>
+<office:body><office:text>
+<text:h text:outline-level="1">Spec</text:h>
+<text:p>Hello<text:s text:c="2"/>world <text:span>again</text:span></text:p>
+<text:list><text:list-item><text:p>First item</text:p></text:list-item></text:list>
+<text:p text:style-name="P1">Next page</text:p>
+<text:h text:outline-level="3">Details</text:h>
+</office:text></office:body></office:document-content>`)
+
+	text, err := DocumentToText(GetDocumentType("spec.odt"), data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "# Spec\n\nHello  world again\n\n- First item\n\n--- page break ---\n\nNext page\n\n### Details"
+	if text != expected {
+		t.Errorf("unexpected odt text:\n%s", text)
+	}
+}
+
+func TestDocumentSizeLimit(t *testing.T) {
+	defer func(max int64) { maxDocumentXmlSize = max }(maxDocumentXmlSize)
+	maxDocumentXmlSize = 100
+
+	data := zipDocument(t, "word/document.xml", strings.Repeat("<w:p/>", 100))
+
+	_, err := DocumentToText(DocumentTypeDocx, data)
+	if err == nil || !strings.Contains(err.Error(), "too large") {
+		t.Errorf("expected too large error, got %v", err)
+	}
+}
+
+func TestGetDocumentType(t *testing.T) {
+	tests := map[string]string{
+		"docs/spec.PDF":                      DocumentTypePdf,
+		"https://example.com/a.docx?dl=1":    DocumentTypeDocx,
+		"notes.odt":                          DocumentTypeOdt,
+		"main.go":                            "",
+		"https://example.com/guide#spec.pdf": "",
+	}
+
+	for path, expected := range tests {
+		if got := GetDocumentType(path); got != expected {
+			t.Errorf("GetDocumentType(%s) = %q, expected %q", path, got, expected)
+		}
+	}
+
+	if got := GetDocumentTypeForContentType("application/pdf; qs=0.001"); got != DocumentTypePdf {
+		t.Errorf("expected pdf for application/pdf, got %q", got)
+	}
+}
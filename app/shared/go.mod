@@ -4,6 +4,7 @@ go 1.21.3
 
 require (
 	github.com/davecgh/go-spew v1.1.1
+	github.com/ledongthuc/pdf v0.0.0-20240201131950-da5b75280b06
 	github.com/pkoukk/tiktoken-go v0.1.6
 )
 
@@ -0,0 +1,130 @@
+package shared
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// LineRange is the span of a file loaded with 'path:start-end' (1-indexed, inclusive).
+// The text of the first and last lines is kept so the span can be found again after lines are added or removed above it.
+type LineRange struct {
+	Start     int    `json:"start"`
+	End       int    `json:"end"`
+	StartText string `json:"startText"`
+	EndText   string `json:"endText"`
+}
+
+func (r *LineRange) Label() string {
+	if r.Start == r.End {
+		return fmt.Sprintf("%d", r.Start)
+	}
+	return fmt.Sprintf("%d-%d", r.Start, r.End)
+}
+
+// NewLineRange returns the range and the text of lines start through end of content
+func NewLineRange(content string, start, end int) (*LineRange, string, error) {
+	lines := strings.Split(content, "\n")
+
+	if start < 1 || end < start {
+		return nil, "", fmt.Errorf("invalid line range %d-%d", start, end)
+	}
+
+	if start > len(lines) {
+		return nil, "", fmt.Errorf("line range %d-%d starts after the end of the file (%d lines)", start, end, len(lines))
+	}
+
+	if end > len(lines) {
+		end = len(lines)
+	}
+
+	return &LineRange{
+		Start:     start,
+		End:       end,
+		StartText: lines[start-1],
+		EndText:   lines[end-1],
+	}, strings.Join(lines[start-1:end], "\n"), nil
+}
+
+// ResolveLineRange finds the range's span in the current content of the file. If the span's text (which hashes to sha) is still present, it returns the span's position, which may have moved. Otherwise it re-resolves the span from its first and last lines, falling back to the original line numbers. changed is only true when the span's text differs -- a span that just moved is returned with changed false, and the caller can compare res with r to store its new position.
+func ResolveLineRange(content string, r *LineRange, sha string) (res *LineRange, body string, changed bool) {
+	lines := strings.Split(content, "\n")
+	numLines := r.End - r.Start + 1
+
+	spanText := func(start, end int) string {
+		return strings.Join(lines[start-1:end], "\n")
+	}
+
+	spanSha := func(start, end int) string {
+		hash := sha256.Sum256([]byte(spanText(start, end)))
+		return hex.EncodeToString(hash[:])
+	}
+
+	startCandidates := nearestMatchingLines(lines, r.StartText, r.Start)
+
+	// unchanged, possibly moved
+	for _, start := range startCandidates {
+		end := start + numLines - 1
+		if end > len(lines) {
+			continue
+		}
+		if spanSha(start, end) == sha {
+			if start == r.Start && end == r.End {
+				return r, spanText(start, end), false
+			}
+			return &LineRange{Start: start, End: end, StartText: r.StartText, EndText: r.EndText}, spanText(start, end), false
+		}
+	}
+
+	// changed -- re-resolve from the first and last lines
+	start := r.Start
+	if len(startCandidates) > 0 {
+		start = startCandidates[0]
+	}
+	if start > len(lines) {
+		start = len(lines)
+	}
+
+	end := start + numLines - 1
+	for _, candidate := range nearestMatchingLines(lines, r.EndText, end) {
+		if candidate >= start {
+			end = candidate
+			break
+		}
+	}
+	if end > len(lines) {
+		end = len(lines)
+	}
+
+	return &LineRange{
+		Start:     start,
+		End:       end,
+		StartText: lines[start-1],
+		EndText:   lines[end-1],
+	}, spanText(start, end), true
+}
+
+// nearestMatchingLines returns the 1-indexed numbers of lines equal to text, closest to near first
+func nearestMatchingLines(lines []string, text string, near int) []int {
+	var res []int
+	for i, line := range lines {
+		if line == text {
+			res = append(res, i+1)
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return absInt(res[i]-near) < absInt(res[j]-near)
+	})
+
+	return res
+}
+
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
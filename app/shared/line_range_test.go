@@ -0,0 +1,47 @@
+package shared
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func TestResolveLineRange(t *testing.T) {
+	content := "a\nb\nfunc x() {\n\treturn 1\n}\nc"
+
+	r, body, err := NewLineRange(content, 3, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body != "func x() {\n\treturn 1\n}" {
+		t.Fatalf("unexpected body: %q", body)
+	}
+
+	hash := sha256.Sum256([]byte(body))
+	sha := hex.EncodeToString(hash[:])
+
+	res, resBody, changed := ResolveLineRange(content, r, sha)
+	if changed || res.Start != 3 || res.End != 5 || resBody != body {
+		t.Fatalf("expected span in place to be unchanged: %+v %q %v", res, resBody, changed)
+	}
+
+	// lines added above the span move it without changing its text, so it isn't changed but its new position is returned
+	moved := "new\nlines\n" + content
+	res, resBody, changed = ResolveLineRange(moved, r, sha)
+	if changed {
+		t.Fatal("expected moved span not to be changed")
+	}
+	if res.Start != 5 || res.End != 7 || resBody != body {
+		t.Fatalf("unexpected resolved range: %+v %q", res, resBody)
+	}
+
+	// edits inside the span are found from its first and last lines
+	edited := "new\na\nb\nfunc x() {\n\ty := 2\n\treturn y\n}\nc"
+	res, resBody, changed = ResolveLineRange(edited, r, sha)
+	if !changed {
+		t.Fatal("expected edited span to be changed")
+	}
+	if res.Start != 4 || res.End != 7 || resBody != "func x() {\n\ty := 2\n\treturn y\n}" {
+		t.Fatalf("unexpected resolved range: %+v %q", res, resBody)
+	}
+}
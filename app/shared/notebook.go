@@ -0,0 +1,305 @@
+package shared
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Notebooks are loaded into context as a cell-delimited text view rather than raw nbformat JSON.
+// Outputs are dropped from the view, and each cell is preceded by a marker line with its index in the original notebook.
+// New cells use 'new' in place of an index. Edits are merged back into the original notebook on apply.
+
+const NotebookNewCellIndex = "new"
+
+var notebookCellMarkerRegex = regexp.MustCompile(`^# %% \[cell (\d+|new)\] (code|markdown|raw)$`)
+
+type notebook struct {
+	Cells         []map[string]json.RawMessage `json:"cells"`
+	Metadata      json.RawMessage              `json:"metadata"`
+	NbFormat      int                          `json:"nbformat"`
+	NbFormatMinor int                          `json:"nbformat_minor"`
+}
+
+type NotebookCell struct {
+	// Index of the cell in the original notebook, or -1 for a new cell
+	Index    int
+	CellType string
+	Source   string
+}
+
+func IsNotebookFile(filePath string) bool {
+	return strings.ToLower(filepath.Ext(filePath)) == ".ipynb"
+}
+
+func NotebookCellMarker(idx int, cellType string) string {
+	idxStr := NotebookNewCellIndex
+	if idx >= 0 {
+		idxStr = strconv.Itoa(idx)
+	}
+	return fmt.Sprintf("# %%%% [cell %s] %s", idxStr, cellType)
+}
+
+func NotebookToText(raw string) (string, error) {
+	nb, err := parseNotebook(raw)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	for i, cell := range nb.Cells {
+		cellType, err := getNotebookCellType(cell)
+		if err != nil {
+			return "", fmt.Errorf("error reading cell %d: %v", i, err)
+		}
+
+		source, err := getNotebookCellSource(cell)
+		if err != nil {
+			return "", fmt.Errorf("error reading cell %d: %v", i, err)
+		}
+
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(NotebookCellMarker(i, cellType))
+		sb.WriteString("\n")
+		sb.WriteString(source)
+		if source != "" && !strings.HasSuffix(source, "\n") {
+			sb.WriteString("\n")
+		}
+	}
+
+	return sb.String(), nil
+}
+
+func ParseNotebookText(text string) ([]*NotebookCell, error) {
+	var cells []*NotebookCell
+	var current *NotebookCell
+	var lines []string
+
+	flush := func(isLast bool) {
+		if current == nil {
+			return
+		}
+		// drop the blank separator line that NotebookToText adds between cells
+		if !isLast && len(lines) > 0 && lines[len(lines)-1] == "" {
+			lines = lines[:len(lines)-1]
+		}
+		current.Source = strings.Join(lines, "\n")
+		cells = append(cells, current)
+	}
+
+	for i, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		matches := notebookCellMarkerRegex.FindStringSubmatch(line)
+		if matches == nil {
+			if current == nil {
+				if strings.TrimSpace(line) == "" {
+					continue
+				}
+				// content without a leading marker goes into a new code cell
+				current = &NotebookCell{Index: -1, CellType: "code"}
+			}
+			lines = append(lines, line)
+			continue
+		}
+
+		flush(false)
+
+		idx := -1
+		if matches[1] != NotebookNewCellIndex {
+			var err error
+			idx, err = strconv.Atoi(matches[1])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid cell index: %v", i+1, err)
+			}
+		}
+
+		current = &NotebookCell{Index: idx, CellType: matches[2]}
+		lines = nil
+	}
+
+	flush(true)
+
+	return cells, nil
+}
+
+// NotebookFromText merges the cell-delimited text view back into the original notebook.
+// Cells whose type and source are unchanged are kept as-is, including outputs and metadata.
+// Edited cells keep their metadata but have outputs cleared since they're no longer valid.
+// If original is empty, a new nbformat 4 notebook is created.
+func NotebookFromText(original, text string) (string, error) {
+	var nb *notebook
+	if strings.TrimSpace(original) == "" {
+		nb = &notebook{
+			Metadata:      json.RawMessage("{}"),
+			NbFormat:      4,
+			NbFormatMinor: 5,
+		}
+	} else {
+		var err error
+		nb, err = parseNotebook(original)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	cells, err := ParseNotebookText(text)
+	if err != nil {
+		return "", err
+	}
+
+	updatedCells := []map[string]json.RawMessage{}
+	usedIdx := map[int]bool{}
+
+	for _, cell := range cells {
+		if cell.Index >= 0 && cell.Index < len(nb.Cells) && !usedIdx[cell.Index] {
+			usedIdx[cell.Index] = true
+			originalCell := nb.Cells[cell.Index]
+
+			originalType, err := getNotebookCellType(originalCell)
+			if err != nil {
+				return "", fmt.Errorf("error reading cell %d: %v", cell.Index, err)
+			}
+			originalSource, err := getNotebookCellSource(originalCell)
+			if err != nil {
+				return "", fmt.Errorf("error reading cell %d: %v", cell.Index, err)
+			}
+
+			if originalType == cell.CellType && strings.TrimSuffix(originalSource, "\n") == cell.Source {
+				updatedCells = append(updatedCells, originalCell)
+				continue
+			}
+
+			updated, err := newNotebookCell(cell, originalCell["metadata"], false)
+			if err != nil {
+				return "", err
+			}
+			for k, v := range originalCell {
+				if _, ok := updated[k]; !ok && k != "outputs" && k != "execution_count" && k != "attachments" {
+					updated[k] = v
+				}
+			}
+			updatedCells = append(updatedCells, updated)
+			continue
+		}
+
+		// cell ids are required from nbformat 4.5 on
+		needsId := nb.NbFormat > 4 || (nb.NbFormat == 4 && nb.NbFormatMinor >= 5)
+		newCell, err := newNotebookCell(cell, nil, needsId)
+		if err != nil {
+			return "", err
+		}
+		updatedCells = append(updatedCells, newCell)
+	}
+
+	nb.Cells = updatedCells
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", " ")
+	err = encoder.Encode(nb)
+	if err != nil {
+		return "", fmt.Errorf("error encoding notebook: %v", err)
+	}
+
+	return buf.String(), nil
+}
+
+func parseNotebook(raw string) (*notebook, error) {
+	var nb notebook
+	err := json.Unmarshal([]byte(raw), &nb)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing notebook: %v", err)
+	}
+	if nb.Metadata == nil {
+		nb.Metadata = json.RawMessage("{}")
+	}
+	return &nb, nil
+}
+
+func getNotebookCellType(cell map[string]json.RawMessage) (string, error) {
+	var cellType string
+	err := json.Unmarshal(cell["cell_type"], &cellType)
+	if err != nil {
+		return "", fmt.Errorf("invalid cell_type: %v", err)
+	}
+	return cellType, nil
+}
+
+func getNotebookCellSource(cell map[string]json.RawMessage) (string, error) {
+	raw, ok := cell["source"]
+	if !ok {
+		return "", nil
+	}
+
+	// nbformat allows the source to be either a string or a list of lines
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s, nil
+	}
+
+	var lines []string
+	err := json.Unmarshal(raw, &lines)
+	if err != nil {
+		return "", fmt.Errorf("invalid source: %v", err)
+	}
+	return strings.Join(lines, ""), nil
+}
+
+func newNotebookCell(cell *NotebookCell, metadata json.RawMessage, withId bool) (map[string]json.RawMessage, error) {
+	if metadata == nil {
+		metadata = json.RawMessage("{}")
+	}
+
+	var sourceLines []string
+	if cell.Source != "" {
+		split := strings.SplitAfter(cell.Source, "\n")
+		for _, line := range split {
+			if line != "" {
+				sourceLines = append(sourceLines, line)
+			}
+		}
+	}
+	if sourceLines == nil {
+		sourceLines = []string{}
+	}
+
+	source, err := json.Marshal(sourceLines)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding cell source: %v", err)
+	}
+
+	cellType, err := json.Marshal(cell.CellType)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding cell type: %v", err)
+	}
+
+	res := map[string]json.RawMessage{
+		"cell_type": cellType,
+		"metadata":  metadata,
+		"source":    source,
+	}
+
+	if cell.CellType == "code" {
+		res["outputs"] = json.RawMessage("[]")
+		res["execution_count"] = json.RawMessage("null")
+	}
+
+	if withId {
+		id, err := GetRandomAlphanumeric(8)
+		if err != nil {
+			return nil, fmt.Errorf("error generating cell id: %v", err)
+		}
+		res["id"], err = json.Marshal(string(id))
+		if err != nil {
+			return nil, fmt.Errorf("error encoding cell id: %v", err)
+		}
+	}
+
+	return res, nil
+}
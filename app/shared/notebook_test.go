@@ -0,0 +1,123 @@
+package shared
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const testNotebook = `{
+ "cells": [
+  {
+   "cell_type": "markdown",
+   "id": "a1",
+   "metadata": {},
+   "source": ["# Title\n", "Some text"]
+  },
+  {
+   "cell_type": "code",
+   "execution_count": 1,
+   "id": "a2",
+   "metadata": {"tags": ["setup"]},
+   "outputs": [{"output_type": "stream", "name": "stdout", "text": ["hi\n"]}],
+   "source": "print('hi')"
+  },
+  {
+   "cell_type": "code",
+   "execution_count": 2,
+   "id": "a3",
+   "metadata": {},
+   "outputs": [{"output_type": "stream", "name": "stdout", "text": ["2\n"]}],
+   "source": ["x = 1\n", "print(x + 1)"]
+  }
+ ],
+ "metadata": {"kernelspec": {"name": "python3"}},
+ "nbformat": 4,
+ "nbformat_minor": 5
+}`
+
+func TestNotebookToText(t *testing.T) {
+	text, err := NotebookToText(testNotebook)
+	if err != nil {
+		t.Fatalf("NotebookToText: %v", err)
+	}
+
+	expected := "# %% [cell 0] markdown\n# Title\nSome text\n\n# %% [cell 1] code\nprint('hi')\n\n# %% [cell 2] code\nx = 1\nprint(x + 1)\n"
+	if text != expected {
+		t.Fatalf("unexpected text view:\n%s", text)
+	}
+
+	if strings.Contains(text, "output_type") {
+		t.Fatalf("text view should not include outputs")
+	}
+}
+
+func TestNotebookFromTextUnchanged(t *testing.T) {
+	text, err := NotebookToText(testNotebook)
+	if err != nil {
+		t.Fatalf("NotebookToText: %v", err)
+	}
+
+	updated, err := NotebookFromText(testNotebook, text)
+	if err != nil {
+		t.Fatalf("NotebookFromText: %v", err)
+	}
+
+	cells := decodeTestCells(t, updated)
+	if len(cells) != 3 {
+		t.Fatalf("expected 3 cells, got %d", len(cells))
+	}
+	for i, cell := range cells {
+		if _, ok := cell["outputs"]; i > 0 && !ok {
+			t.Fatalf("cell %d lost its outputs", i)
+		}
+	}
+	if string(cells[1]["execution_count"]) != "1" {
+		t.Fatalf("cell 1 lost its execution count")
+	}
+}
+
+func TestNotebookFromTextEdits(t *testing.T) {
+	text := "# %% [cell 0] markdown\n# Title\nSome text\n\n" +
+		"# %% [cell 2] code\nx = 2\nprint(x + 1)\n\n" +
+		"# %% [cell new] code\nprint('done')\n"
+
+	updated, err := NotebookFromText(testNotebook, text)
+	if err != nil {
+		t.Fatalf("NotebookFromText: %v", err)
+	}
+
+	cells := decodeTestCells(t, updated)
+	if len(cells) != 3 {
+		t.Fatalf("expected 3 cells, got %d", len(cells))
+	}
+
+	if string(cells[1]["id"]) != `"a3"` {
+		t.Fatalf("edited cell should keep its id, got %s", cells[1]["id"])
+	}
+	if string(cells[1]["outputs"]) != "[]" {
+		t.Fatalf("edited cell should have outputs cleared, got %s", cells[1]["outputs"])
+	}
+
+	var source []string
+	if err := json.Unmarshal(cells[1]["source"], &source); err != nil {
+		t.Fatalf("invalid source: %v", err)
+	}
+	if strings.Join(source, "") != "x = 2\nprint(x + 1)" {
+		t.Fatalf("unexpected edited source: %q", source)
+	}
+
+	if _, ok := cells[2]["id"]; !ok {
+		t.Fatalf("new cell should have an id")
+	}
+}
+
+func decodeTestCells(t *testing.T, raw string) []map[string]json.RawMessage {
+	var nb struct {
+		Cells []map[string]json.RawMessage `json:"cells"`
+	}
+	if err := json.Unmarshal([]byte(raw), &nb); err != nil {
+		t.Fatalf("result is not valid JSON: %v", err)
+	}
+	return nb.Cells
+}
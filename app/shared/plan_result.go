@@ -1,6 +1,7 @@
 package shared
 
 import (
+	"sort"
 	"time"
 )
 
@@ -131,6 +132,30 @@ func (r PlanResult) NumPendingForPath(path string) int {
 	return res
 }
 
+func (r PlanResult) PendingResultIds() []string {
+	var ids []string
+	for _, results := range r.FileResultsByPath {
+		for _, result := range results {
+			if result.IsPending() {
+				ids = append(ids, result.Id)
+			}
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (r PlanResult) PendingResultIdsForPath(path string) []string {
+	var ids []string
+	for _, result := range r.FileResultsByPath[path] {
+		if result.IsPending() {
+			ids = append(ids, result.Id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
 func (desc *ConvoMessageDescription) NumBuildsPendingByPath() map[string]int {
 	res := map[string]int{}
 	if (!desc.DidBuild && len(desc.Files) > 0) || len(desc.BuildPathsInvalidated) > 0 {
@@ -39,9 +39,10 @@ const (
 	ModelRoleExecStatus  ModelRole = "auto-continue"
 	ModelRoleVerifier    ModelRole = "verifier"
 	ModelRoleAutoFix     ModelRole = "auto-fix"
+	ModelRoleReviewer    ModelRole = "reviewer"
 )
 
-var AllModelRoles = []ModelRole{ModelRolePlanner, ModelRolePlanSummary, ModelRoleBuilder, ModelRoleName, ModelRoleCommitMsg, ModelRoleExecStatus, ModelRoleVerifier, ModelRoleAutoFix}
+var AllModelRoles = []ModelRole{ModelRolePlanner, ModelRolePlanSummary, ModelRoleBuilder, ModelRoleName, ModelRoleCommitMsg, ModelRoleExecStatus, ModelRoleVerifier, ModelRoleAutoFix, ModelRoleReviewer}
 var ModelRoleDescriptions = map[ModelRole]string{
 	ModelRolePlanner:     "replies to prompts and makes plans",
 	ModelRolePlanSummary: "summarizes conversations exceeding max-convo-tokens",
@@ -51,6 +52,7 @@ var ModelRoleDescriptions = map[ModelRole]string{
 	ModelRoleExecStatus:  "determines whether to auto-continue",
 	ModelRoleVerifier:    "verifies file correctness",
 	ModelRoleAutoFix:     "automatically fixes syntax errors",
+	ModelRoleReviewer:    "reviews pending changes for bugs and security issues",
 }
 var SettingDescriptions = map[string]string{
 	"max-convo-tokens":       "max conversation 🪙 before summarization",
@@ -116,6 +118,7 @@ func (ps PlanSettings) GetRequiredEnvVars() map[string]bool {
 	envVars[ms.ExecStatus.BaseModelConfig.ApiKeyEnvVar] = true
 	envVars[ms.GetVerifier().BaseModelConfig.ApiKeyEnvVar] = true
 	envVars[ms.GetAutoFix().BaseModelConfig.ApiKeyEnvVar] = true
+	envVars[ms.GetReviewer().BaseModelConfig.ApiKeyEnvVar] = true
 
 	// for backward compatibility with <= 0.8.4 server versions
 	if len(envVars) == 0 {
@@ -0,0 +1,61 @@
+package shared
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+type PromptName string
+
+const (
+	PromptPlanner    PromptName = "planner"
+	PromptBuilder    PromptName = "builder"
+	PromptBuildFixer PromptName = "build-fixer"
+	PromptVerifier   PromptName = "verifier"
+	PromptSummarizer PromptName = "summarizer"
+	PromptChat       PromptName = "chat"
+)
+
+var AllPromptNames = []PromptName{PromptPlanner, PromptBuilder, PromptBuildFixer, PromptVerifier, PromptSummarizer, PromptChat}
+
+var PromptDescriptions = map[PromptName]string{
+	PromptPlanner:    "system prompt for planning and replying to prompts",
+	PromptBuilder:    "instructions for turning a plan's proposed updates into changes to a file",
+	PromptBuildFixer: "instructions for fixing a file that failed verification",
+	PromptVerifier:   "instructions for checking that a file was updated correctly",
+	PromptSummarizer: "instructions for summarizing the conversation so far",
+	PromptChat:       "system prompt for answering questions with 'plandex chat'",
+}
+
+func IsValidPromptName(name string) bool {
+	for _, n := range AllPromptNames {
+		if string(n) == name {
+			return true
+		}
+	}
+	return false
+}
+
+type PromptScope string
+
+const (
+	PromptScopeDefault PromptScope = "default"
+	PromptScopeOrg     PromptScope = "org"
+	PromptScopePlan    PromptScope = "plan"
+)
+
+// PromptTemplate is a prompt as a Go text/template. Templates can use {{.Identity}}, the assistant's identity statement.
+type PromptTemplate struct {
+	Name     PromptName  `json:"name"`
+	Scope    PromptScope `json:"scope"`
+	Template string      `json:"template"`
+	// Version identifies the template's text, so the same text always has the same version wherever it's set
+	Version   string     `json:"version"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+}
+
+func GetPromptVersion(template string) string {
+	hash := sha256.Sum256([]byte(template))
+	return hex.EncodeToString(hash[:])[:12]
+}
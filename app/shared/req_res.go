@@ -111,27 +111,33 @@ const (
 )
 
 type TellPlanRequest struct {
-	Prompt         string            `json:"prompt"`
-	BuildMode      BuildMode         `json:"buildMode"`
-	ConnectStream  bool              `json:"connectStream"`
-	AutoContinue   bool              `json:"autoContinue"`
-	IsUserContinue bool              `json:"isUserContinue"`
-	ApiKey         string            `json:"apiKey"`   // deprecated
-	Endpoint       string            `json:"endpoint"` // deprecated
-	ApiKeys        map[string]string `json:"apiKeys"`
-	OpenAIBase     string            `json:"openAIBase"`
-	OpenAIOrgId    string            `json:"openAIOrgId"`
-	ProjectPaths   map[string]bool   `json:"projectPaths"`
+	Prompt              string            `json:"prompt"`
+	BuildMode           BuildMode         `json:"buildMode"`
+	ConnectStream       bool              `json:"connectStream"`
+	AutoContinue        bool              `json:"autoContinue"`
+	IsUserContinue      bool              `json:"isUserContinue"`
+	ApiKey              string            `json:"apiKey"`   // deprecated
+	Endpoint            string            `json:"endpoint"` // deprecated
+	ApiKeys             map[string]string `json:"apiKeys"`
+	OpenAIBase          string            `json:"openAIBase"`
+	OpenAIOrgId         string            `json:"openAIOrgId"`
+	ProjectPaths        map[string]bool   `json:"projectPaths"`
+	ConfigSchemas       map[string]string `json:"configSchemas"`
+	ProjectInstructions string            `json:"projectInstructions,omitempty"`
+	// IsChat answers the prompt with the chat prompt instead of the planner prompt, without parsing file blocks or building
+	IsChat bool `json:"isChat,omitempty"`
 }
 
 type BuildPlanRequest struct {
-	ConnectStream bool              `json:"connectStream"`
-	ApiKey        string            `json:"apiKey"`   // deprecated
-	Endpoint      string            `json:"endpoint"` // deprecated
-	ApiKeys       map[string]string `json:"apiKeys"`
-	OpenAIBase    string            `json:"openAIBase"`
-	OpenAIOrgId   string            `json:"openAIOrgId"`
-	ProjectPaths  map[string]bool   `json:"projectPaths"`
+	ConnectStream       bool              `json:"connectStream"`
+	ApiKey              string            `json:"apiKey"`   // deprecated
+	Endpoint            string            `json:"endpoint"` // deprecated
+	ApiKeys             map[string]string `json:"apiKeys"`
+	OpenAIBase          string            `json:"openAIBase"`
+	OpenAIOrgId         string            `json:"openAIOrgId"`
+	ProjectPaths        map[string]bool   `json:"projectPaths"`
+	ConfigSchemas       map[string]string `json:"configSchemas"`
+	ProjectInstructions string            `json:"projectInstructions,omitempty"`
 }
 
 const NoBuildsErr string = "No builds"
@@ -158,6 +164,12 @@ type LoadContextParams struct {
 	Body            string                `json:"body"`
 	ForceSkipIgnore bool                  `json:"forceSkipIgnore"`
 	ImageDetail     openai.ImageURLDetail `json:"imageDetail"`
+	Group           string                `json:"group"`
+	LineRange       *LineRange            `json:"lineRange,omitempty"`
+	GitRef          string                `json:"gitRef,omitempty"`
+	Command         string                `json:"command,omitempty"`
+	SourceSha       string                `json:"sourceSha,omitempty"`
+	LoadedFrom      string                `json:"loadedFrom,omitempty"`
 
 	// For naming piped data
 	ApiKeys     map[string]string `json:"apiKeys"`
@@ -176,7 +188,9 @@ type LoadContextResponse struct {
 }
 
 type UpdateContextParams struct {
-	Body string `json:"body"`
+	Body      string     `json:"body"`
+	LineRange *LineRange `json:"lineRange,omitempty"`
+	SourceSha string     `json:"sourceSha,omitempty"`
 }
 
 type UpdateContextRequest map[string]*UpdateContextParams
@@ -193,6 +207,32 @@ type DeleteContextResponse struct {
 	Msg           string `json:"msg"`
 }
 
+type UpdateContextGroupRequest struct {
+	Group    string `json:"group"`
+	Disabled bool   `json:"disabled"`
+}
+
+type UpdateContextGroupResponse struct {
+	NumContexts       int    `json:"numContexts"`
+	TokensChanged     int    `json:"tokensChanged"`
+	TotalTokens       int    `json:"totalTokens"`
+	MaxTokens         int    `json:"maxTokens"`
+	MaxTokensExceeded bool   `json:"maxTokensExceeded"`
+	Msg               string `json:"msg"`
+}
+
+// ReplayContextChangesRequest copies the context changes made on SourceBranch between two of its prompts
+type ReplayContextChangesRequest struct {
+	SourceBranch  string `json:"sourceBranch"`
+	FromMessageId string `json:"fromMessageId"`
+	ToMessageId   string `json:"toMessageId"`
+}
+
+type ReplayContextChangesResponse struct {
+	NumChanged int    `json:"numChanged"`
+	Msg        string `json:"msg"`
+}
+
 type RejectFileRequest struct {
 	FilePath string `json:"filePath"`
 }
@@ -203,6 +243,10 @@ type RejectFilesRequest struct {
 
 type RewindPlanRequest struct {
 	Sha string `json:"sha"`
+	// BeforeMessageId rewinds to just before the conversation message was added, in place of Sha
+	BeforeMessageId string `json:"beforeMessageId,omitempty"`
+	// Tag rewinds to a tagged checkpoint, in place of Sha
+	Tag string `json:"tag,omitempty"`
 }
 
 type RewindPlanResponse struct {
@@ -238,6 +282,123 @@ type ApplyPlanRequest struct {
 	OpenAIOrgId string            `json:"openAIOrgId"`
 }
 
+type ReviewPlanRequest struct {
+	ApiKeys             map[string]string `json:"apiKeys"`
+	OpenAIBase          string            `json:"openAIBase"`
+	OpenAIOrgId         string            `json:"openAIOrgId"`
+	ProjectInstructions string            `json:"projectInstructions,omitempty"`
+}
+
+type UpdatePromptTemplateRequest struct {
+	Template string `json:"template"`
+}
+
 type RenamePlanRequest struct {
 	Name string `json:"name"`
 }
+
+type SharePlanRequest struct {
+	// Email is the user to share with. If it's empty, the plan is shared with everyone in the org.
+	Email  string     `json:"email,omitempty"`
+	Access PlanAccess `json:"access"`
+}
+
+type UnsharePlanRequest struct {
+	// Email is the user to stop sharing with. If it's empty, the plan stops being shared with the org.
+	Email string `json:"email,omitempty"`
+}
+
+type ListPlanCollaboratorsResponse struct {
+	SharedWithOrgAccess PlanAccess          `json:"sharedWithOrgAccess,omitempty"`
+	Collaborators       []*PlanCollaborator `json:"collaborators"`
+}
+
+type RequestReviewRequest struct {
+	// Reviewers are emails or names of org members, optionally prefixed with @
+	Reviewers []string `json:"reviewers"`
+}
+
+type ReviewFeedbackRequest struct {
+	Path string `json:"path"`
+	// empty to leave feedback on the whole file
+	ReplacementId string `json:"replacementId,omitempty"`
+	Approve       bool   `json:"approve"`
+	Comment       string `json:"comment,omitempty"`
+}
+
+type MergeBranchRequest struct {
+	Branch string `json:"branch"`
+	// Force replaces pending changes on the current branch with the merged branch's pending changes where both have pending changes to the same path
+	Force bool `json:"force"`
+}
+
+type MergeBranchResponse struct {
+	// ConflictedPaths have pending changes on both branches. If the request wasn't forced and there are conflicts, nothing is merged.
+	ConflictedPaths []string `json:"conflictedPaths"`
+	Merged          bool     `json:"merged"`
+	Msg             string   `json:"msg"`
+}
+
+type BranchComparisonSummary struct {
+	Name string `json:"name"`
+	// paths with pending changes on the branch
+	PendingPaths  []string `json:"pendingPaths"`
+	NumContexts   int      `json:"numContexts"`
+	ContextTokens int      `json:"contextTokens"`
+	NumMessages   int      `json:"numMessages"`
+	ConvoTokens   int      `json:"convoTokens"`
+}
+
+type CompareBranchesResponse struct {
+	A BranchComparisonSummary `json:"a"`
+	B BranchComparisonSummary `json:"b"`
+	// names of context loaded on only one of the branches
+	ContextOnlyA []string `json:"contextOnlyA"`
+	ContextOnlyB []string `json:"contextOnlyB"`
+	// names of context loaded on both branches with different contents
+	ContextChanged []string `json:"contextChanged"`
+	// Diffs is a git diff from the files as they'd be if A's pending changes were applied to the files as they'd be if B's were
+	Diffs string `json:"diffs"`
+}
+
+type CreatePlanTagRequest struct {
+	Name    string `json:"name"`
+	Message string `json:"message"`
+}
+
+type DeletePlanTagRequest struct {
+	// tags are protected, so deleting one must be forced
+	Force bool `json:"force"`
+}
+
+type PlanSearchResultKind string
+
+const (
+	PlanSearchResultKindPlan        PlanSearchResultKind = "plan"
+	PlanSearchResultKindMessage     PlanSearchResultKind = "message"
+	PlanSearchResultKindDescription PlanSearchResultKind = "description"
+)
+
+// PlanSearchMatchStart and PlanSearchMatchStop wrap matched terms in search snippets. Control characters are used so they can't be confused with markdown in the matched text.
+const (
+	PlanSearchMatchStart = "\x02"
+	PlanSearchMatchStop  = "\x03"
+)
+
+type PlanSearchResult struct {
+	PlanId    string               `json:"planId"`
+	PlanName  string               `json:"planName"`
+	ProjectId string               `json:"projectId"`
+	Kind      PlanSearchResultKind `json:"kind"`
+	// branches the matching message is on -- empty for plan name matches
+	Branches   []string `json:"branches,omitempty"`
+	MessageNum int      `json:"messageNum,omitempty"`
+	Role       string   `json:"role,omitempty"`
+	// Snippet is the matching text, without markdown emphasis, with matched terms wrapped in PlanSearchMatchStart and PlanSearchMatchStop
+	Snippet string  `json:"snippet"`
+	Rank    float64 `json:"rank"`
+}
+
+type SearchPlansResponse struct {
+	Results []*PlanSearchResult `json:"results"`
+}
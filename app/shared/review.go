@@ -0,0 +1,94 @@
+package shared
+
+import (
+	"sort"
+	"time"
+)
+
+type ReviewSeverity string
+
+const (
+	ReviewSeverityLow    ReviewSeverity = "low"
+	ReviewSeverityMedium ReviewSeverity = "medium"
+	ReviewSeverityHigh   ReviewSeverity = "high"
+)
+
+var AllReviewSeverities = []string{string(ReviewSeverityLow), string(ReviewSeverityMedium), string(ReviewSeverityHigh)}
+
+var reviewSeverityRank = map[ReviewSeverity]int{
+	ReviewSeverityLow:    1,
+	ReviewSeverityMedium: 2,
+	ReviewSeverityHigh:   3,
+}
+
+type ReviewFinding struct {
+	Path     string         `json:"path"`
+	Line     int            `json:"line"`
+	Severity ReviewSeverity `json:"severity"`
+	Message  string         `json:"message"`
+}
+
+type PlanReview struct {
+	Id      string `json:"id"`
+	Summary string `json:"summary"`
+	// pending result ids at the time of the review -- once these change, the review no longer applies
+	ResultIds []string         `json:"resultIds"`
+	Findings  []*ReviewFinding `json:"findings"`
+	CreatedAt time.Time        `json:"createdAt"`
+}
+
+func (s ReviewSeverity) Icon() string {
+	switch s {
+	case ReviewSeverityHigh:
+		return "🔴"
+	case ReviewSeverityMedium:
+		return "🟠"
+	default:
+		return "🟡"
+	}
+}
+
+func (r *PlanReview) FindingsForPath(path string) []*ReviewFinding {
+	var res []*ReviewFinding
+	for _, finding := range r.Findings {
+		if finding.Path == path {
+			res = append(res, finding)
+		}
+	}
+	return res
+}
+
+// MaxSeverityForPath returns an empty severity if there are no findings for the path
+func (r *PlanReview) MaxSeverityForPath(path string) ReviewSeverity {
+	var max ReviewSeverity
+	for _, finding := range r.FindingsForPath(path) {
+		if reviewSeverityRank[finding.Severity] > reviewSeverityRank[max] {
+			max = finding.Severity
+		}
+	}
+	return max
+}
+
+func (r *PlanReview) NumHighSeverity() int {
+	num := 0
+	for _, finding := range r.Findings {
+		if finding.Severity == ReviewSeverityHigh {
+			num++
+		}
+	}
+	return num
+}
+
+// SortFindings orders findings by severity (highest first), then path and line
+func (r *PlanReview) SortFindings() {
+	sort.SliceStable(r.Findings, func(i, j int) bool {
+		a, b := r.Findings[i], r.Findings[j]
+		if reviewSeverityRank[a.Severity] != reviewSeverityRank[b.Severity] {
+			return reviewSeverityRank[a.Severity] > reviewSeverityRank[b.Severity]
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Line < b.Line
+	})
+}
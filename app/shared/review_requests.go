@@ -0,0 +1,133 @@
+package shared
+
+import "time"
+
+// ReviewRequest asks a teammate to approve a plan's pending changes. While any request is open, pending changes can't be applied until the reviewer approves every pending file.
+type ReviewRequest struct {
+	Id              string    `json:"id"`
+	ReviewerId      string    `json:"reviewerId"`
+	ReviewerName    string    `json:"reviewerName"`
+	ReviewerEmail   string    `json:"reviewerEmail"`
+	RequestedById   string    `json:"requestedById"`
+	RequestedByName string    `json:"requestedByName"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
+
+// ReviewFeedback is an approval of, or a comment on, the pending changes to a file or a single replacement
+type ReviewFeedback struct {
+	Id       string `json:"id"`
+	UserId   string `json:"userId"`
+	UserName string `json:"userName"`
+	Path     string `json:"path"`
+	// empty when the feedback covers the whole file
+	ReplacementId string `json:"replacementId,omitempty"`
+	// pending result ids for the path when the feedback was left -- a file approval no longer counts once these change
+	ResultIds []string  `json:"resultIds"`
+	Approved  bool      `json:"approved"`
+	Comment   string    `json:"comment,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type PlanReviewState struct {
+	Requests []*ReviewRequest  `json:"requests"`
+	Feedback []*ReviewFeedback `json:"feedback"`
+}
+
+func (s *PlanReviewState) RequestForReviewer(reviewerId string) *ReviewRequest {
+	for _, request := range s.Requests {
+		if request.ReviewerId == reviewerId {
+			return request
+		}
+	}
+	return nil
+}
+
+// IsPathApproved is true if the reviewer approved the path as a whole since its pending results last changed, or approved each of its pending replacements
+func (s *PlanReviewState) IsPathApproved(reviewerId, path string, planResult *PlanResult) bool {
+	resultIds := planResult.PendingResultIdsForPath(path)
+	if len(resultIds) == 0 {
+		return true
+	}
+
+	approvedReplacements := map[string]bool{}
+	for _, feedback := range s.Feedback {
+		if feedback.UserId != reviewerId || feedback.Path != path || !feedback.Approved {
+			continue
+		}
+		if feedback.ReplacementId != "" {
+			approvedReplacements[feedback.ReplacementId] = true
+		} else if equalStrings(feedback.ResultIds, resultIds) {
+			return true
+		}
+	}
+
+	for _, result := range planResult.FileResultsByPath[path] {
+		if !result.IsPending() {
+			continue
+		}
+		// a new file has no replacements to approve one by one
+		if len(result.Replacements) == 0 {
+			return false
+		}
+		for _, rep := range result.Replacements {
+			if rep.IsPending() && !approvedReplacements[rep.Id] {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func (s *PlanReviewState) UnapprovedPaths(reviewerId string, planResult *PlanResult) []string {
+	var paths []string
+	for _, path := range planResult.SortedPaths {
+		if !s.IsPathApproved(reviewerId, path, planResult) {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// PendingRequests returns the open requests whose reviewer hasn't yet approved every pending file
+func (s *PlanReviewState) PendingRequests(planResult *PlanResult) []*ReviewRequest {
+	var res []*ReviewRequest
+	for _, request := range s.Requests {
+		if len(s.UnapprovedPaths(request.ReviewerId, planResult)) > 0 {
+			res = append(res, request)
+		}
+	}
+	return res
+}
+
+func (s *PlanReviewState) FeedbackForPath(path string) []*ReviewFeedback {
+	var res []*ReviewFeedback
+	for _, feedback := range s.Feedback {
+		if feedback.Path == path {
+			res = append(res, feedback)
+		}
+	}
+	return res
+}
+
+func (s *PlanReviewState) FeedbackForReplacement(replacementId string) []*ReviewFeedback {
+	var res []*ReviewFeedback
+	for _, feedback := range s.Feedback {
+		if feedback.ReplacementId == replacementId {
+			res = append(res, feedback)
+		}
+	}
+	return res
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,43 @@
+package shared
+
+import "testing"
+
+func TestReviewApprovals(t *testing.T) {
+	planResult := &PlanResult{
+		SortedPaths: []string{"main.go", "new.go"},
+		FileResultsByPath: PlanFileResultsByPath{
+			"main.go": {{Id: "r1", Path: "main.go", Replacements: []*Replacement{{Id: "a"}, {Id: "b"}}}},
+			"new.go":  {{Id: "r2", Path: "new.go", Content: "package main"}},
+		},
+	}
+
+	state := &PlanReviewState{
+		Requests: []*ReviewRequest{{ReviewerId: "alice"}},
+		Feedback: []*ReviewFeedback{
+			{UserId: "alice", Path: "main.go", ReplacementId: "a", Approved: true},
+			{UserId: "alice", Path: "new.go", ResultIds: []string{"r2"}, Comment: "looks off"},
+			// only requested reviewers' approvals count toward the gate, but feedback is kept per user
+			{UserId: "bob", Path: "main.go", ResultIds: []string{"r1"}, Approved: true},
+		},
+	}
+
+	if got := state.UnapprovedPaths("alice", planResult); len(got) != 2 {
+		t.Fatalf("expected both paths to be unapproved, got %v", got)
+	}
+
+	state.Feedback = append(state.Feedback,
+		&ReviewFeedback{UserId: "alice", Path: "main.go", ReplacementId: "b", Approved: true},
+		&ReviewFeedback{UserId: "alice", Path: "new.go", ResultIds: []string{"r2"}, Approved: true},
+	)
+
+	if pending := state.PendingRequests(planResult); len(pending) != 0 {
+		t.Fatalf("expected every request to be approved, got %d pending", len(pending))
+	}
+
+	// a new build for the file invalidates the file-level approval
+	planResult.FileResultsByPath["new.go"] = append(planResult.FileResultsByPath["new.go"], &PlanFileResult{Id: "r3", Path: "new.go", Replacements: []*Replacement{{Id: "c"}}})
+
+	if got := state.UnapprovedPaths("alice", planResult); len(got) != 1 || got[0] != "new.go" {
+		t.Fatalf("expected new.go to need approval again, got %v", got)
+	}
+}